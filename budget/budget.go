@@ -0,0 +1,209 @@
+// Package budget enforces token, cost, wall-clock and tool call limits on
+// agent runs. Runs that exceed their budget stop gracefully: the messages
+// produced so far are returned along with an *ExceededError.
+package budget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/examples/internal/agentrun"
+	"github.com/agent-api/examples/usage"
+)
+
+// ErrBudgetExceeded is matched by every *ExceededError through errors.Is.
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
+// Limit names the limit a run exceeded.
+type Limit string
+
+const (
+	LimitTokens    Limit = "tokens"
+	LimitCost      Limit = "cost"
+	LimitDuration  Limit = "duration"
+	LimitToolCalls Limit = "tool_calls"
+)
+
+// ExceededError is returned when a run is stopped by its budget.
+type ExceededError struct {
+	Limit Limit
+
+	// Used and Max are in the limit's unit: tokens, US dollars, seconds or
+	// tool calls.
+	Used float64
+	Max  float64
+
+	// Summary is the usage of the run when it was stopped
+	Summary *usage.Summary
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("budget exceeded: %s used %g of %g", e.Limit, e.Used, e.Max)
+}
+
+func (e *ExceededError) Is(target error) bool {
+	return target == ErrBudgetExceeded
+}
+
+// BudgetOpts configures a new Budget. A zero limit is unlimited.
+type BudgetOpts struct {
+	// MaxTokens is the maximum prompt plus completion tokens for a run
+	MaxTokens int
+
+	// MaxCost is the maximum cost of a run in US dollars
+	MaxCost float64
+
+	// MaxDuration is the maximum wall-clock time of a run. It is checked
+	// between steps, so a provider call in flight is never interrupted.
+	MaxDuration time.Duration
+
+	// MaxToolCalls is the maximum number of tool calls in a run. Once a
+	// response would exceed it, its tool calls are not executed.
+	MaxToolCalls int
+
+	// Ledger is where the agent's provider records usage (see
+	// usage.NewProvider). It is required for token and cost limits.
+	Ledger *usage.Ledger
+
+	// StopCondition is the run's regular stop condition. Defaults to
+	// agent.DefaultStopCondition.
+	StopCondition agent.AgentStopCondition
+}
+
+// Budget applies limits to agent runs.
+type Budget struct {
+	opts *BudgetOpts
+}
+
+// New creates a new Budget.
+func New(opts *BudgetOpts) (*Budget, error) {
+	if (opts.MaxTokens > 0 || opts.MaxCost > 0) && opts.Ledger == nil {
+		return nil, errors.New("token and cost limits require a usage ledger")
+	}
+
+	if opts.StopCondition == nil {
+		opts.StopCondition = agent.DefaultStopCondition
+	}
+
+	return &Budget{
+		opts: opts,
+	}, nil
+}
+
+// run tracks a single budgeted run.
+type run struct {
+	budget *Budget
+	id     string
+	start  time.Time
+
+	mu       sync.Mutex
+	exceeded *ExceededError
+}
+
+// Run executes the runner within the budget. Calls are recorded in the ledger
+// against the run set with usage.WithRun on ctx, or a new run ID otherwise.
+//
+// When a limit is exceeded, Run returns the partial result and an
+// *ExceededError.
+func (b *Budget) Run(ctx context.Context, r agentrun.Runner, opts ...agent.RunOptionFunc) (*agent.AgentRunAggregator, error) {
+	info, ok := usage.RunFromContext(ctx)
+	if !ok {
+		info = &usage.RunInfo{ID: usage.NewRunID()}
+		ctx = usage.WithRun(ctx, info.ID, "")
+	}
+
+	tracked := &run{
+		budget: b,
+		id:     info.ID,
+		start:  time.Now(),
+	}
+
+	// the budget's stop condition must be applied last to take precedence
+	opts = append(opts, agent.WithStopCondition(tracked.stopCondition))
+
+	agg, err := r.Run(ctx, opts...)
+
+	tracked.mu.Lock()
+	exceeded := tracked.exceeded
+	tracked.mu.Unlock()
+
+	if exceeded != nil {
+		return agg, exceeded
+	}
+
+	return agg, err
+}
+
+// stopCondition stops a run on its regular stop condition or once any limit
+// has been exceeded.
+func (r *run) stopCondition(agg *agent.AgentRunAggregator) bool {
+	if exceeded := r.check(agg); exceeded != nil {
+		r.mu.Lock()
+		r.exceeded = exceeded
+		r.mu.Unlock()
+
+		return true
+	}
+
+	return r.budget.opts.StopCondition(agg)
+}
+
+func (r *run) check(agg *agent.AgentRunAggregator) *ExceededError {
+	opts := r.budget.opts
+
+	var summary *usage.Summary
+	if opts.Ledger != nil {
+		summary = opts.Ledger.RunSummary(r.id)
+	}
+
+	if opts.MaxTokens > 0 && summary.TotalTokens() > opts.MaxTokens {
+		return &ExceededError{
+			Limit:   LimitTokens,
+			Used:    float64(summary.TotalTokens()),
+			Max:     float64(opts.MaxTokens),
+			Summary: summary,
+		}
+	}
+
+	if opts.MaxCost > 0 && summary.Cost > opts.MaxCost {
+		return &ExceededError{
+			Limit:   LimitCost,
+			Used:    summary.Cost,
+			Max:     opts.MaxCost,
+			Summary: summary,
+		}
+	}
+
+	if elapsed := time.Since(r.start); opts.MaxDuration > 0 && elapsed > opts.MaxDuration {
+		return &ExceededError{
+			Limit:   LimitDuration,
+			Used:    elapsed.Seconds(),
+			Max:     opts.MaxDuration.Seconds(),
+			Summary: summary,
+		}
+	}
+
+	if opts.MaxToolCalls > 0 {
+		toolCalls := 0
+		for _, m := range agg.Messages {
+			if m != nil {
+				toolCalls += len(m.ToolCalls)
+			}
+		}
+
+		if toolCalls > opts.MaxToolCalls {
+			return &ExceededError{
+				Limit:   LimitToolCalls,
+				Used:    float64(toolCalls),
+				Max:     float64(opts.MaxToolCalls),
+				Summary: summary,
+			}
+		}
+	}
+
+	return nil
+}
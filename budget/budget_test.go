@@ -0,0 +1,215 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/core/agent/bootstrap"
+	"github.com/agent-api/examples/usage"
+)
+
+// loopingProvider calls the calculator in every response until it has made
+// calls tool calls, then answers. Every response reports 100 prompt and 50
+// completion tokens.
+type loopingProvider struct {
+	calls     int
+	responses int
+}
+
+func (p *loopingProvider) GetCapabilities(ctx context.Context) (*core.Capabilities, error) {
+	return &core.Capabilities{}, nil
+}
+
+func (p *loopingProvider) UseModel(ctx context.Context, model *core.Model) error {
+	return nil
+}
+
+func (p *loopingProvider) Generate(ctx context.Context, opts *core.GenerateOptions) (*core.Message, error) {
+	p.responses++
+
+	msg := &core.Message{
+		Role: core.AssistantMessageRole,
+		Metadata: &core.Metadata{
+			ProviderProperties: map[string]string{
+				usage.PromptTokensProperty:     "100",
+				usage.CompletionTokensProperty: "50",
+			},
+		},
+	}
+
+	if p.responses > p.calls {
+		msg.Content = "done"
+		return msg, nil
+	}
+
+	msg.ToolCalls = []*core.ToolCall{{ID: "call", Name: "calculator", Arguments: []byte(`{}`)}}
+
+	return msg, nil
+}
+
+func (p *loopingProvider) GenerateStream(ctx context.Context, opts *core.GenerateOptions) (<-chan *core.Message, <-chan string, <-chan error) {
+	return nil, nil, nil
+}
+
+// newTestAgent returns an agent whose provider records its usage in ledger,
+// priced at $1 per thousand tokens, and the number of calculator runs.
+func newTestAgent(t *testing.T, provider *loopingProvider) (*agent.Agent, *usage.Ledger, *int) {
+	t.Helper()
+
+	ledger := usage.NewLedger(usage.PriceTable{
+		"test-model": {PromptPerMillion: 1000, CompletionPerMillion: 1000},
+	})
+
+	executed := 0
+
+	a, err := agent.NewAgent(
+		bootstrap.WithProvider(usage.NewProvider(&usage.ProviderOpts{
+			Provider: provider,
+			Name:     "test",
+			Model:    &core.Model{ID: "test-model"},
+			Ledger:   ledger,
+		})),
+		bootstrap.WithTools(&core.Tool{
+			Name: "calculator",
+			WrappedToolFunction: func(ctx context.Context, args []byte) (interface{}, error) {
+				executed++
+				return 5, nil
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return a, ledger, &executed
+}
+
+func TestBudgetRun(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *BudgetOpts
+
+		// calls is the number of tool calls before the agent answers
+		calls         int
+		wantLimit     Limit
+		wantUsed      float64
+		wantResponses int
+
+		// the tool calls of the response that exceeded the budget are never
+		// executed
+		wantExecuted int
+	}{
+		{
+			name:          "within budget",
+			opts:          &BudgetOpts{MaxTokens: 1000, MaxCost: 1, MaxToolCalls: 5},
+			calls:         2,
+			wantResponses: 3,
+			wantExecuted:  2,
+		},
+		{
+			name:          "tokens",
+			opts:          &BudgetOpts{MaxTokens: 400},
+			calls:         10,
+			wantLimit:     LimitTokens,
+			wantUsed:      450,
+			wantResponses: 3,
+			wantExecuted:  2,
+		},
+		{
+			name:          "cost",
+			opts:          &BudgetOpts{MaxCost: 0.2},
+			calls:         10,
+			wantLimit:     LimitCost,
+			wantUsed:      0.3,
+			wantResponses: 2,
+			wantExecuted:  1,
+		},
+		{
+			name:          "tool calls",
+			opts:          &BudgetOpts{MaxToolCalls: 2},
+			calls:         10,
+			wantLimit:     LimitToolCalls,
+			wantUsed:      3,
+			wantResponses: 3,
+			wantExecuted:  2,
+		},
+		{
+			name:          "duration",
+			opts:          &BudgetOpts{MaxDuration: time.Nanosecond},
+			calls:         10,
+			wantLimit:     LimitDuration,
+			wantResponses: 1,
+			wantExecuted:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &loopingProvider{calls: tt.calls}
+			a, ledger, executed := newTestAgent(t, provider)
+
+			tt.opts.Ledger = ledger
+			b, err := New(tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := usage.WithRun(context.Background(), "run-1", "calc_agent")
+			agg, err := b.Run(ctx, a, agent.WithInput("What is 2 + 3?"))
+
+			if provider.responses != tt.wantResponses {
+				t.Errorf("got %d responses, want %d", provider.responses, tt.wantResponses)
+			}
+
+			if *executed != tt.wantExecuted {
+				t.Errorf("executed %d tool calls, want %d", *executed, tt.wantExecuted)
+			}
+
+			if got := ledger.RunSummary("run-1").Requests; got != provider.responses {
+				t.Errorf("recorded %d requests against the run, want %d", got, provider.responses)
+			}
+
+			if tt.wantLimit == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if agg.Pop().Content != "done" {
+					t.Errorf("got final message %+v", agg.Pop())
+				}
+				return
+			}
+
+			exceeded := &ExceededError{}
+			if !errors.As(err, &exceeded) || !errors.Is(err, ErrBudgetExceeded) {
+				t.Fatalf("got error %v", err)
+			}
+
+			if exceeded.Limit != tt.wantLimit || (tt.wantUsed != 0 && !approx(exceeded.Used, tt.wantUsed)) {
+				t.Errorf("got %s used %g, want %s used %g", exceeded.Limit, exceeded.Used, tt.wantLimit, tt.wantUsed)
+			}
+
+			if agg == nil || len(agg.Messages) == 0 {
+				t.Error("the partial result was not returned")
+			}
+		})
+	}
+}
+
+func approx(a, b float64) bool {
+	return a-b < 1e-9 && b-a < 1e-9
+}
+
+func TestNewRequiresLedger(t *testing.T) {
+	if _, err := New(&BudgetOpts{MaxTokens: 100}); err == nil {
+		t.Error("expected an error for a token limit without a ledger")
+	}
+
+	if _, err := New(&BudgetOpts{MaxToolCalls: 3}); err != nil {
+		t.Errorf("got error %v for a tool call limit without a ledger", err)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/examples/budget"
+	"github.com/agent-api/examples/usage"
+	"github.com/agent-api/ollama"
+	"github.com/agent-api/ollama/models"
+	"github.com/agent-api/webscraper-agent"
+)
+
+const PROMPT string = "Please scrape https://johncodes.com/archive/2025/01-11-whats-an-ai-agent/ and summarize it."
+
+func main() {
+	ctx := context.Background()
+
+	// Create a zap logger
+	config := zap.NewDevelopmentConfig()
+	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	zLogger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a logr.Logger using zapr adapter
+	logger := zapr.NewLogger(zLogger)
+
+	// Record the usage of every provider call in a ledger
+	ledger := usage.NewLedger(usage.DefaultPrices())
+
+	provider := usage.NewProvider(&usage.ProviderOpts{
+		Provider: ollama.NewProvider(&ollama.ProviderOpts{
+			Logger:  &logger,
+			BaseURL: "http://localhost",
+			Port:    11434,
+		}),
+		Name:   "ollama",
+		Ledger: ledger,
+	})
+	provider.UseModel(ctx, models.QWEN2_5_LATEST)
+
+	scraper, err := webscraper.NewWebScraperAgent(&webscraper.WebScraperConfig{
+		Provider: provider,
+		Logger:   &logger,
+		MaxSteps: 15,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// Beyond MaxSteps, cap the run's tokens, time and tool calls
+	b, err := budget.New(&budget.BudgetOpts{
+		MaxTokens:    50000,
+		MaxDuration:  2 * time.Minute,
+		MaxToolCalls: 4,
+		Ledger:       ledger,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	result, err := b.Run(
+		ctx,
+		scraper,
+		agent.WithInput(PROMPT),
+	)
+
+	var exceeded *budget.ExceededError
+	switch {
+	case errors.As(err, &exceeded):
+		logger.Info("run stopped by budget",
+			"limit", exceeded.Limit,
+			"used", exceeded.Used,
+			"max", exceeded.Max,
+			"messages", len(result.Messages),
+		)
+	case err != nil:
+		panic(err)
+	}
+
+	logger.Info(result.Messages[len(result.Messages)-1].Content)
+}
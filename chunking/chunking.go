@@ -0,0 +1,84 @@
+// Package chunking splits document text into chunks sized for embedding.
+// Every strategy implements Chunker, so ingestion can pick the one that
+// suits each document type.
+package chunking
+
+import (
+	"strings"
+
+	"github.com/agent-api/examples/modelinfo"
+)
+
+// Metadata keys set on chunks by the chunkers of this package.
+const (
+	// MetadataHeading is the heading path of a Markdown section, i.e.,
+	// "Spells > Cantrips"
+	MetadataHeading string = "heading"
+
+	// MetadataSymbol is the name of a Go function, method, type, constant or
+	// variable
+	MetadataSymbol string = "symbol"
+)
+
+// DefaultSize is the chunk size in tokens used when a chunker's size is zero.
+const DefaultSize int = 256
+
+// Chunk is a piece of a document.
+type Chunk struct {
+	Content string
+
+	// Metadata describes where in the document the chunk comes from. It is
+	// nil for chunkers that add none.
+	Metadata map[string]any
+}
+
+// Chunker splits text into chunks.
+type Chunker interface {
+	Chunk(text string) []*Chunk
+}
+
+// TokenCounter counts the tokens of a text.
+type TokenCounter func(s string) int
+
+// defaultTokenCounter roughly estimates tokens at 4 characters per token.
+var defaultTokenCounter TokenCounter = modelinfo.EstimateTokens
+
+func sizeOrDefault(size int) int {
+	if size <= 0 {
+		return DefaultSize
+	}
+
+	return size
+}
+
+func counterOrDefault(counter TokenCounter) TokenCounter {
+	if counter == nil {
+		return defaultTokenCounter
+	}
+
+	return counter
+}
+
+// chunksOf wraps texts as chunks with a copy of metadata, skipping blank ones.
+func chunksOf(texts []string, metadata map[string]any) []*Chunk {
+	chunks := make([]*Chunk, 0, len(texts))
+
+	for _, t := range texts {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+
+		c := &Chunk{Content: t}
+		if metadata != nil {
+			c.Metadata = make(map[string]any, len(metadata))
+			for k, v := range metadata {
+				c.Metadata[k] = v
+			}
+		}
+
+		chunks = append(chunks, c)
+	}
+
+	return chunks
+}
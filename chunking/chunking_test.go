@@ -0,0 +1,355 @@
+package chunking
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// countWords counts words as tokens, so that chunk sizes are easy to follow.
+func countWords(s string) int {
+	return len(strings.Fields(s))
+}
+
+func contents(chunks []*Chunk) []string {
+	out := []string{}
+	for _, c := range chunks {
+		out = append(out, c.Content)
+	}
+
+	return out
+}
+
+func TestRecursiveChunker(t *testing.T) {
+	tests := []struct {
+		name    string
+		chunker *RecursiveChunker
+		text    string
+		want    []string
+	}{
+		{
+			name:    "fits",
+			chunker: &RecursiveChunker{Size: 10, Counter: countWords},
+			text:    "a b c",
+			want:    []string{"a b c"},
+		},
+		{
+			name:    "merges paragraphs",
+			chunker: &RecursiveChunker{Size: 3, Counter: countWords},
+			text:    "a b\n\nc d\n\ne",
+			want:    []string{"a b", "c d\n\ne"},
+		},
+		{
+			name:    "splits words",
+			chunker: &RecursiveChunker{Size: 2, Counter: countWords},
+			text:    "a b c d e",
+			want:    []string{"a b", "c d", "e"},
+		},
+		{
+			name:    "splits characters",
+			chunker: &RecursiveChunker{Size: 3, Counter: func(s string) int { return len(s) }},
+			text:    "abcdefg",
+			want:    []string{"abc", "def", "g"},
+		},
+		{
+			name:    "custom separators",
+			chunker: &RecursiveChunker{Size: 2, Separators: []string{";"}, Counter: countWords},
+			text:    "a b; c d; e",
+			want:    []string{"a b;", "c d;", "e"},
+		},
+		{
+			name:    "blank",
+			chunker: &RecursiveChunker{},
+			text:    " \n\n ",
+			want:    []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := contents(tt.chunker.Chunk(tt.text)); !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownChunker(t *testing.T) {
+	tests := []struct {
+		name     string
+		chunker  *MarkdownChunker
+		text     string
+		want     []string
+		headings []any
+	}{
+		{
+			name:    "sections",
+			chunker: &MarkdownChunker{},
+			text:    "intro\n\n# Guide\n\nwelcome\n\n## Install\n\nrun it\n\n## Use\n\ncall it\n",
+			want: []string{
+				"intro",
+				"Guide\n\nwelcome",
+				"Guide > Install\n\nrun it",
+				"Guide > Use\n\ncall it",
+			},
+			headings: []any{nil, "Guide", "Guide > Install", "Guide > Use"},
+		},
+		{
+			name:     "headings in code fences",
+			chunker:  &MarkdownChunker{},
+			text:     "# Shell\n\n```sh\n# a comment\n```\n",
+			want:     []string{"Shell\n\n```sh\n# a comment\n```"},
+			headings: []any{"Shell"},
+		},
+		{
+			name:     "deep headings stay in their section",
+			chunker:  &MarkdownChunker{},
+			text:     "# A\n\none\n\n#### Detail\n\ntwo\n",
+			want:     []string{"A\n\none\n\n#### Detail\n\ntwo"},
+			headings: []any{"A"},
+		},
+		{
+			name:     "skipped levels",
+			chunker:  &MarkdownChunker{},
+			text:     "# A\n\n### C\n\nbody\n",
+			want:     []string{"A > C\n\nbody"},
+			headings: []any{"A > C"},
+		},
+		{
+			name:     "large sections are split under their heading",
+			chunker:  &MarkdownChunker{Size: 3, Counter: countWords},
+			text:     "# A\n\none two three four\n",
+			want:     []string{"A\n\none two", "A\n\nthree four"},
+			headings: []any{"A", "A"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := tt.chunker.Chunk(tt.text)
+
+			if got := contents(chunks); !slices.Equal(got, tt.want) {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+
+			for i, c := range chunks {
+				var heading any
+				if c.Metadata != nil {
+					heading = c.Metadata[MetadataHeading]
+				}
+
+				if heading != tt.headings[i] {
+					t.Errorf("chunk %d has heading %v, want %v", i, heading, tt.headings[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSplitSentences(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "sentences",
+			text: "It works. Does it? Yes!",
+			want: []string{"It works.", "Does it?", "Yes!"},
+		},
+		{
+			name: "abbreviations and initials",
+			text: "Dr. Smith met J. Doe, e.g. at noon. Then left.",
+			want: []string{"Dr. Smith met J. Doe, e.g. at noon.", "Then left."},
+		},
+		{
+			name: "decimals",
+			text: "It costs 3.5 dollars. Really.",
+			want: []string{"It costs 3.5 dollars.", "Really."},
+		},
+		{
+			name: "closing quotes",
+			text: `He said "stop." Then left.`,
+			want: []string{`He said "stop."`, "Then left."},
+		},
+		{
+			name: "lower case does not start a sentence",
+			text: "version 2. it continues",
+			want: []string{"version 2. it continues"},
+		},
+		{
+			name: "blank lines and whitespace",
+			text: "A title\n\nThe   body\nwraps",
+			want: []string{"A title", "The body wraps"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitSentences(tt.text); !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSentenceChunker(t *testing.T) {
+	tests := []struct {
+		name    string
+		chunker *SentenceChunker
+		text    string
+		want    []string
+	}{
+		{
+			name:    "groups sentences",
+			chunker: &SentenceChunker{Size: 4, Counter: countWords},
+			text:    "Ab cd. Ef gh. Ij kl.",
+			want:    []string{"Ab cd. Ef gh.", "Ij kl."},
+		},
+		{
+			name:    "overlap",
+			chunker: &SentenceChunker{Size: 4, Overlap: 1, Counter: countWords},
+			text:    "Ab cd. Ef gh. Ij kl.",
+			want:    []string{"Ab cd. Ef gh.", "Ef gh. Ij kl."},
+		},
+		{
+			name:    "large sentences are whole",
+			chunker: &SentenceChunker{Size: 1, Counter: countWords},
+			text:    "Ab cd ef. Gh ij kl.",
+			want:    []string{"Ab cd ef.", "Gh ij kl."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := contents(tt.chunker.Chunk(tt.text)); !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenChunker(t *testing.T) {
+	tests := []struct {
+		name    string
+		chunker *TokenChunker
+		text    string
+		want    []string
+	}{
+		{
+			name:    "windows",
+			chunker: &TokenChunker{Size: 3, Counter: countWords},
+			text:    "a b c d e",
+			want:    []string{"a b c", "d e"},
+		},
+		{
+			name:    "overlap",
+			chunker: &TokenChunker{Size: 3, Overlap: 1, Counter: countWords},
+			text:    "a b c d e",
+			want:    []string{"a b c", "c d e"},
+		},
+		{
+			name:    "overlap is smaller than size",
+			chunker: &TokenChunker{Size: 2, Overlap: 5, Counter: countWords},
+			text:    "a b c",
+			want:    []string{"a b", "b c"},
+		},
+		{
+			name:    "empty",
+			chunker: &TokenChunker{},
+			text:    "",
+			want:    []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := contents(tt.chunker.Chunk(tt.text)); !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoChunker(t *testing.T) {
+	source := `package shapes
+
+import "math"
+
+// Sizes of shapes
+const (
+	Small, Medium = 1, 2
+	Large         = 3
+)
+
+var unit = math.Sqrt(1)
+
+// Circle is round.
+type Circle struct{ R float64 }
+
+// Area of the circle.
+func (c *Circle) Area() float64 { return math.Pi * c.R * c.R }
+
+func New(r float64) *Circle { return &Circle{R: r} }
+`
+
+	tests := []struct {
+		name    string
+		chunker *GoChunker
+		text    string
+		want    []string
+		symbols []any
+	}{
+		{
+			name:    "declarations",
+			chunker: &GoChunker{},
+			text:    source,
+			want: []string{
+				"package shapes\n\n// Sizes of shapes\nconst (\n\tSmall, Medium = 1, 2\n\tLarge         = 3\n)",
+				"package shapes\n\nvar unit = math.Sqrt(1)",
+				"package shapes\n\n// Circle is round.\ntype Circle struct{ R float64 }",
+				"package shapes\n\n// Area of the circle.\nfunc (c *Circle) Area() float64 { return math.Pi * c.R * c.R }",
+				"package shapes\n\nfunc New(r float64) *Circle { return &Circle{R: r} }",
+			},
+			symbols: []any{"Small, Medium, Large", "unit", "Circle", "Circle.Area", "New"},
+		},
+		{
+			name:    "large declarations are split",
+			chunker: &GoChunker{Size: 5, Counter: countWords},
+			text:    "package p\n\nfunc F() {\n\ta()\n\tb()\n}\n",
+			want:    []string{"package p\n\nfunc F() {", "package p\n\na()\n\tb()\n}"},
+			symbols: []any{"F", "F"},
+		},
+		{
+			name:    "invalid source",
+			chunker: &GoChunker{},
+			text:    "package p\n\nfunc {",
+			want:    []string{"package p\n\nfunc {"},
+			symbols: []any{nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := tt.chunker.Chunk(tt.text)
+
+			if got := contents(chunks); !slices.Equal(got, tt.want) {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+
+			symbols := []any{}
+			for _, c := range chunks {
+				var symbol any
+				if c.Metadata != nil {
+					symbol = c.Metadata[MetadataSymbol]
+				}
+				symbols = append(symbols, symbol)
+			}
+
+			if !reflect.DeepEqual(symbols, tt.symbols) {
+				t.Errorf("got symbols %v, want %v", symbols, tt.symbols)
+			}
+		})
+	}
+}
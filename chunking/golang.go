@@ -0,0 +1,116 @@
+package chunking
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// GoChunker splits Go source into one chunk per top level function, method,
+// type, const and var declaration, each including its doc comment and
+// prefixed with the package clause. The declared names are set as
+// MetadataSymbol, with methods named "Receiver.Method" and the names of a
+// grouped declaration joined by ", ". Declarations larger than Size are split further by
+// lines, and source that does not parse is split with a RecursiveChunker.
+type GoChunker struct {
+	// Size is the maximum chunk size in tokens. Defaults to DefaultSize.
+	Size int
+
+	// Counter defaults to an estimate of 4 characters per token
+	Counter TokenCounter
+}
+
+// Chunk splits Go source by declaration.
+func (c *GoChunker) Chunk(text string) []*Chunk {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", text, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return (&RecursiveChunker{Size: c.Size, Counter: c.Counter}).Chunk(text)
+	}
+
+	prefix := "package " + file.Name.Name + "\n\n"
+	size := max(sizeOrDefault(c.Size)-counterOrDefault(c.Counter)(prefix), 1)
+	splitter := &RecursiveChunker{
+		Size:       size,
+		Separators: []string{"\n\n", "\n"},
+		Counter:    c.Counter,
+	}
+
+	chunks := []*Chunk{}
+
+	for _, decl := range file.Decls {
+		var symbol string
+		var doc *ast.CommentGroup
+
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			symbol = funcSymbol(d)
+			doc = d.Doc
+
+		case *ast.GenDecl:
+			if d.Tok == token.IMPORT {
+				continue
+			}
+
+			names := []string{}
+			for _, spec := range d.Specs {
+				switch sp := spec.(type) {
+				case *ast.TypeSpec:
+					names = append(names, sp.Name.Name)
+				case *ast.ValueSpec:
+					for _, name := range sp.Names {
+						names = append(names, name.Name)
+					}
+				}
+			}
+			symbol = strings.Join(names, ", ")
+			doc = d.Doc
+
+		default:
+			continue
+		}
+
+		start := decl.Pos()
+		if doc != nil {
+			start = doc.Pos()
+		}
+
+		source := text[fset.Position(start).Offset:fset.Position(decl.End()).Offset]
+
+		pieces := splitter.split(source)
+		for i := range pieces {
+			pieces[i] = prefix + strings.TrimSpace(pieces[i])
+		}
+
+		chunks = append(chunks, chunksOf(pieces, map[string]any{MetadataSymbol: symbol})...)
+	}
+
+	return chunks
+}
+
+// funcSymbol names a function, or a method as "Receiver.Method".
+func funcSymbol(d *ast.FuncDecl) string {
+	if d.Recv == nil || len(d.Recv.List) == 0 {
+		return d.Name.Name
+	}
+
+	recv := d.Recv.List[0].Type
+	for {
+		switch t := recv.(type) {
+		case *ast.StarExpr:
+			recv = t.X
+			continue
+		case *ast.IndexExpr:
+			recv = t.X
+			continue
+		case *ast.IndexListExpr:
+			recv = t.X
+			continue
+		case *ast.Ident:
+			return t.Name + "." + d.Name.Name
+		}
+
+		return d.Name.Name
+	}
+}
@@ -0,0 +1,125 @@
+package chunking
+
+import "strings"
+
+// MarkdownChunker splits Markdown into sections at headings, ignoring
+// headings inside fenced code blocks. Every chunk is prefixed with the path of
+// headings it falls under, which is also set as its MetadataHeading. Sections
+// larger than Size are split further with a RecursiveChunker.
+type MarkdownChunker struct {
+	// Size is the maximum chunk size in tokens. Defaults to DefaultSize.
+	Size int
+
+	// MaxLevel is the deepest heading level that starts a new section.
+	// Defaults to 3, so "####" and deeper headings stay within their section.
+	MaxLevel int
+
+	// Counter defaults to an estimate of 4 characters per token
+	Counter TokenCounter
+}
+
+type markdownSection struct {
+	headings []string
+	body     strings.Builder
+}
+
+// Chunk splits Markdown by headings.
+func (c *MarkdownChunker) Chunk(text string) []*Chunk {
+	maxLevel := c.MaxLevel
+	if maxLevel <= 0 {
+		maxLevel = 3
+	}
+
+	sections := []*markdownSection{{}}
+	path := []string{}
+	fence := ""
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if fence == "" && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")) {
+			fence = trimmed[:3]
+		} else if fence != "" && strings.HasPrefix(trimmed, fence) {
+			fence = ""
+		} else if fence == "" {
+			if level, title, ok := parseHeading(trimmed); ok && level <= maxLevel {
+				// keep the path of parent headings
+				for len(path) >= level {
+					path = path[:len(path)-1]
+				}
+				for len(path) < level-1 {
+					path = append(path, "")
+				}
+				path = append(path, title)
+
+				sections = append(sections, &markdownSection{
+					headings: nonEmpty(path),
+				})
+				continue
+			}
+		}
+
+		sections[len(sections)-1].body.WriteString(line)
+	}
+
+	splitter := &RecursiveChunker{Size: c.Size, Counter: c.Counter}
+	chunks := []*Chunk{}
+
+	for _, s := range sections {
+		body := strings.TrimSpace(s.body.String())
+		if body == "" {
+			continue
+		}
+
+		var metadata map[string]any
+		prefix := ""
+		if len(s.headings) > 0 {
+			heading := strings.Join(s.headings, " > ")
+			metadata = map[string]any{MetadataHeading: heading}
+			prefix = heading + "\n\n"
+		}
+
+		// leave room for the heading prefix repeated on every piece
+		splitter.Size = max(sizeOrDefault(c.Size)-counterOrDefault(c.Counter)(prefix), 1)
+
+		pieces := splitter.split(body)
+		for i := range pieces {
+			pieces[i] = prefix + strings.TrimSpace(pieces[i])
+		}
+
+		chunks = append(chunks, chunksOf(pieces, metadata)...)
+	}
+
+	return chunks
+}
+
+// parseHeading parses an ATX heading line such as "## Cantrips".
+func parseHeading(line string) (int, string, bool) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+
+	if level == 0 || level > 6 {
+		return 0, "", false
+	}
+
+	if level < len(line) && line[level] != ' ' && line[level] != '\t' {
+		return 0, "", false
+	}
+
+	title := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(line[level:]), "#"))
+
+	return level, title, true
+}
+
+func nonEmpty(path []string) []string {
+	out := []string{}
+	for _, p := range path {
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+
+	return out
+}
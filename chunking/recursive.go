@@ -0,0 +1,116 @@
+package chunking
+
+import "strings"
+
+// DefaultSeparators are tried in order by RecursiveChunker: paragraphs, lines,
+// sentences and finally words.
+var DefaultSeparators = []string{"\n\n", "\n", ". ", " "}
+
+// RecursiveChunker splits text on the first separator that occurs in it,
+// recursing with the next separators into pieces that are still too large,
+// then merges adjacent pieces back together up to Size tokens.
+type RecursiveChunker struct {
+	// Size is the maximum chunk size in tokens. Defaults to DefaultSize.
+	Size int
+
+	// Separators default to DefaultSeparators
+	Separators []string
+
+	// Counter defaults to an estimate of 4 characters per token
+	Counter TokenCounter
+}
+
+// Chunk splits text recursively.
+func (c *RecursiveChunker) Chunk(text string) []*Chunk {
+	return chunksOf(c.split(text), nil)
+}
+
+// split returns the chunk contents of text.
+func (c *RecursiveChunker) split(text string) []string {
+	separators := c.Separators
+	if len(separators) == 0 {
+		separators = DefaultSeparators
+	}
+
+	return splitRecursive(text, separators, sizeOrDefault(c.Size), counterOrDefault(c.Counter))
+}
+
+func splitRecursive(text string, separators []string, size int, count TokenCounter) []string {
+	if count(text) <= size {
+		return []string{text}
+	}
+
+	// find the first separator present in the text
+	sep := ""
+	rest := []string{}
+	for i, s := range separators {
+		if strings.Contains(text, s) {
+			sep = s
+			rest = separators[i+1:]
+			break
+		}
+	}
+
+	// nothing left to split on: hard split by characters
+	if sep == "" {
+		return splitChars(text, size, count)
+	}
+
+	// keep the separator with the piece it ends, so that merged chunks keep
+	// the original text
+	pieces := strings.SplitAfter(text, sep)
+
+	out := []string{}
+	var current strings.Builder
+
+	for _, piece := range pieces {
+		if count(piece) > size {
+			if current.Len() > 0 {
+				out = append(out, current.String())
+				current.Reset()
+			}
+
+			out = append(out, splitRecursive(piece, rest, size, count)...)
+			continue
+		}
+
+		if current.Len() > 0 && count(current.String()+piece) > size {
+			out = append(out, current.String())
+			current.Reset()
+		}
+
+		current.WriteString(piece)
+	}
+
+	if current.Len() > 0 {
+		out = append(out, current.String())
+	}
+
+	return out
+}
+
+// splitChars splits text without separators into pieces of at most size
+// tokens.
+func splitChars(text string, size int, count TokenCounter) []string {
+	runes := []rune(text)
+	out := []string{}
+
+	for len(runes) > 0 {
+		// binary search the longest piece that fits, of at least one rune
+		lo, hi := 1, len(runes)
+		for lo < hi {
+			mid := (lo + hi + 1) / 2
+			if count(string(runes[:mid])) <= size {
+				lo = mid
+			} else {
+				hi = mid - 1
+			}
+		}
+		end := lo
+
+		out = append(out, string(runes[:end]))
+		runes = runes[end:]
+	}
+
+	return out
+}
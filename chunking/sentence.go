@@ -0,0 +1,140 @@
+package chunking
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SentenceChunker groups whole sentences into chunks of up to Size tokens, so
+// that no chunk starts or ends mid sentence. A single sentence larger than
+// Size becomes its own chunk.
+type SentenceChunker struct {
+	// Size is the maximum chunk size in tokens. Defaults to DefaultSize.
+	Size int
+
+	// Overlap is the number of sentences repeated from the end of the
+	// previous chunk
+	Overlap int
+
+	// Counter defaults to an estimate of 4 characters per token
+	Counter TokenCounter
+}
+
+// Chunk splits text into groups of sentences.
+func (c *SentenceChunker) Chunk(text string) []*Chunk {
+	size := sizeOrDefault(c.Size)
+	count := counterOrDefault(c.Counter)
+
+	sentences := SplitSentences(text)
+	if len(sentences) == 0 {
+		return nil
+	}
+
+	chunks := []string{}
+	start := 0
+
+	for start < len(sentences) {
+		end := start
+		total := 0
+		for end < len(sentences) && (end == start || total+count(sentences[end]) <= size) {
+			total += count(sentences[end])
+			end++
+		}
+
+		chunks = append(chunks, strings.Join(sentences[start:end], " "))
+		if end == len(sentences) {
+			break
+		}
+
+		// always move forward by at least a sentence
+		start = max(end-max(c.Overlap, 0), start+1)
+	}
+
+	return chunksOf(chunks, nil)
+}
+
+// SplitSentences splits text at sentence ending punctuation followed by
+// whitespace and an upper case letter, digit or quote, and at blank lines.
+// Common abbreviations and initials do not end a sentence.
+// Whitespace within sentences is collapsed.
+func SplitSentences(text string) []string {
+	runes := []rune(text)
+	sentences := []string{}
+	start := 0
+
+	emit := func(end int) {
+		s := strings.Join(strings.Fields(string(runes[start:end])), " ")
+		if s != "" {
+			sentences = append(sentences, s)
+		}
+		start = end
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		// blank line
+		if r == '\n' {
+			j := i + 1
+			for j < len(runes) && (runes[j] == ' ' || runes[j] == '\t' || runes[j] == '\r') {
+				j++
+			}
+
+			if j < len(runes) && runes[j] == '\n' {
+				emit(i)
+				continue
+			}
+		}
+
+		if r != '.' && r != '!' && r != '?' {
+			continue
+		}
+
+		if r == '.' && isAbbreviation(runes[start:i]) {
+			continue
+		}
+
+		// include closing quotes and brackets in the sentence
+		j := i + 1
+		for j < len(runes) && strings.ContainsRune(`"')]”’`, runes[j]) {
+			j++
+		}
+
+		if j >= len(runes) || !unicode.IsSpace(runes[j]) {
+			continue
+		}
+
+		k := j
+		for k < len(runes) && unicode.IsSpace(runes[k]) {
+			k++
+		}
+
+		if k == len(runes) || unicode.IsUpper(runes[k]) || unicode.IsDigit(runes[k]) || strings.ContainsRune(`"'(“‘`, runes[k]) {
+			emit(j)
+			i = j - 1
+		}
+	}
+
+	emit(len(runes))
+
+	return sentences
+}
+
+// abbreviations that end with a period without ending a sentence
+var abbreviations = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true, "st": true,
+	"vs": true, "etc": true, "e.g": true, "i.e": true, "fig": true, "no": true,
+}
+
+// isAbbreviation reports whether the last word of text is a known
+// abbreviation or a single letter initial.
+func isAbbreviation(text []rune) bool {
+	fields := strings.Fields(string(text))
+	if len(fields) == 0 {
+		return false
+	}
+
+	word := strings.ToLower(strings.TrimLeft(fields[len(fields)-1], `"'(“‘`))
+
+	return abbreviations[word] || (len([]rune(word)) == 1 && unicode.IsLetter([]rune(word)[0]))
+}
@@ -0,0 +1,65 @@
+package chunking
+
+import "strings"
+
+// TokenChunker splits text into fixed size windows of tokens, each
+// overlapping the previous one. Windows break between words.
+type TokenChunker struct {
+	// Size is the window size in tokens. Defaults to DefaultSize.
+	Size int
+
+	// Overlap is the number of tokens repeated from the end of the previous
+	// window. It must be smaller than Size.
+	Overlap int
+
+	// Counter defaults to an estimate of 4 characters per token
+	Counter TokenCounter
+}
+
+// Chunk splits text into windows.
+func (c *TokenChunker) Chunk(text string) []*Chunk {
+	size := sizeOrDefault(c.Size)
+	overlap := min(max(c.Overlap, 0), size-1)
+	count := counterOrDefault(c.Counter)
+
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return nil
+	}
+
+	tokens := make([]int, len(words))
+	for i, w := range words {
+		// every word is at least one token, including its leading space
+		tokens[i] = max(count(" "+w), 1)
+	}
+
+	windows := []string{}
+	start := 0
+
+	for {
+		end := start
+		total := 0
+		for end < len(words) && (end == start || total+tokens[end] <= size) {
+			total += tokens[end]
+			end++
+		}
+
+		windows = append(windows, strings.Join(words[start:end], " "))
+		if end == len(words) {
+			break
+		}
+
+		// step back from the end of the window by overlap tokens, always
+		// moving forward by at least a word
+		next := end
+		backed := 0
+		for next-1 > start && backed+tokens[next-1] <= overlap {
+			next--
+			backed += tokens[next]
+		}
+
+		start = next
+	}
+
+	return chunksOf(windows, nil)
+}
@@ -0,0 +1,71 @@
+package eval
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Case is a single question of an evaluation dataset.
+type Case struct {
+	// ID identifies the case in reports. Defaults to the case's line number.
+	ID string `json:"id,omitempty"`
+
+	Question string `json:"question"`
+
+	// ExpectedSources are the documents, as recorded in
+	// vectorstorer.MetadataSource, that answer the question
+	ExpectedSources []string `json:"expected_sources"`
+
+	// ReferenceAnswer is a correct answer to the question. Correctness is
+	// only judged for cases that have one.
+	ReferenceAnswer string `json:"reference_answer,omitempty"`
+}
+
+// LoadDataset reads a JSONL file of cases, one per line. Blank lines are
+// skipped.
+func LoadDataset(path string) ([]*Case, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening dataset: %w", err)
+	}
+	defer f.Close()
+
+	cases := []*Case{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		c := &Case{}
+		if err := json.Unmarshal([]byte(text), c); err != nil {
+			return nil, fmt.Errorf("error unmarshaling dataset line %d: %w", line, err)
+		}
+
+		if c.Question == "" {
+			return nil, fmt.Errorf("dataset line %d has no question", line)
+		}
+
+		if c.ID == "" {
+			c.ID = strconv.Itoa(line)
+		}
+
+		cases = append(cases, c)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading dataset: %w", err)
+	}
+
+	return cases, nil
+}
@@ -0,0 +1,273 @@
+// Package eval measures a retrieval augmented generation setup against a
+// dataset of questions with known source documents and reference answers.
+//
+// Retrieval is scored by recall@k and mean reciprocal rank over the
+// documents of the retrieved chunks. Answers are scored for faithfulness to
+// the retrieved sources and correctness against the reference answer by a
+// Judge, either a model or lexical matching.
+package eval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+
+	"github.com/agent-api/examples/internal/agentrun"
+	"github.com/agent-api/examples/rag"
+	"github.com/agent-api/examples/vectorstorer"
+)
+
+// EvaluatorOpts configures a new Evaluator.
+type EvaluatorOpts struct {
+	// Store is searched for each question, i.e., a reranking or hybrid store
+	Store vectorstorer.VectorStorer
+
+	// NewAgent creates the agent that answers a question. A new agent is
+	// created per case so no case sees another's conversation. When nil,
+	// only retrieval is evaluated.
+	NewAgent func() (agentrun.Runner, error)
+
+	// Judge scores the answers. Defaults to a fuzzy MatchJudge.
+	Judge Judge
+
+	// K is the number of chunks retrieved per question. Defaults to 5.
+	K int
+
+	Logger *logr.Logger
+}
+
+// Evaluator runs a dataset through retrieval and, optionally, a RAG agent.
+type Evaluator struct {
+	store    vectorstorer.VectorStorer
+	newAgent func() (agentrun.Runner, error)
+	judge    Judge
+	k        int
+
+	logger *logr.Logger
+}
+
+// CaseResult is the evaluation of a single case.
+type CaseResult struct {
+	ID       string `json:"id"`
+	Question string `json:"question"`
+
+	// Retrieved are the documents of the retrieved chunks, in rank order
+	Retrieved []string `json:"retrieved"`
+	Expected  []string `json:"expected"`
+
+	RecallAtK      float64 `json:"recall_at_k"`
+	ReciprocalRank float64 `json:"reciprocal_rank"`
+
+	Reference string `json:"reference_answer,omitempty"`
+	Answer    string `json:"answer,omitempty"`
+
+	// Cited are the documents of the sources the answer cites
+	Cited []string `json:"cited,omitempty"`
+
+	// Judgement is nil when no answer was generated or judged
+	Judgement *Judgement `json:"judgement,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// Summary aggregates the results of all cases.
+type Summary struct {
+	Cases int `json:"cases"`
+
+	// Errors counts cases whose retrieval, run or judgement failed
+	Errors int `json:"errors"`
+
+	K         int     `json:"k"`
+	RecallAtK float64 `json:"recall_at_k"`
+	MRR       float64 `json:"mrr"`
+
+	// Judge is the name of the judge, empty when only retrieval was
+	// evaluated
+	Judge string `json:"judge,omitempty"`
+
+	// Faithfulness is averaged over the Judged answers and Correctness over
+	// the judged answers that have a reference answer, WithReference
+	Faithfulness  float64 `json:"faithfulness"`
+	Correctness   float64 `json:"correctness"`
+	Judged        int     `json:"judged"`
+	WithReference int     `json:"with_reference"`
+}
+
+// Report is the outcome of an evaluation.
+type Report struct {
+	Summary *Summary      `json:"summary"`
+	Cases   []*CaseResult `json:"cases"`
+}
+
+// NewEvaluator creates a new Evaluator.
+func NewEvaluator(opts *EvaluatorOpts) (*Evaluator, error) {
+	if opts.Store == nil {
+		return nil, errors.New("evaluator requires a store")
+	}
+
+	if opts.Judge == nil {
+		opts.Judge = &MatchJudge{Fuzzy: true}
+	}
+
+	if opts.K <= 0 {
+		opts.K = 5
+	}
+
+	return &Evaluator{
+		store:    opts.Store,
+		newAgent: opts.NewAgent,
+		judge:    opts.Judge,
+		k:        opts.K,
+		logger:   opts.Logger,
+	}, nil
+}
+
+// Evaluate runs every case in order and summarizes the results. A failing
+// case is recorded in its CaseResult and does not stop the evaluation.
+// Evaluate only returns an error if ctx is done.
+func (e *Evaluator) Evaluate(ctx context.Context, cases []*Case) (*Report, error) {
+	report := &Report{
+		Cases: make([]*CaseResult, 0, len(cases)),
+	}
+
+	for _, c := range cases {
+		if err := ctx.Err(); err != nil {
+			report.Summary = e.summarize(report.Cases)
+			return report, err
+		}
+
+		result := e.evaluateCase(ctx, c)
+		report.Cases = append(report.Cases, result)
+
+		if e.logger != nil {
+			e.logger.V(1).Info("evaluated case",
+				"id", result.ID,
+				"recall", result.RecallAtK,
+				"reciprocalRank", result.ReciprocalRank,
+				"error", result.Error,
+			)
+		}
+	}
+
+	report.Summary = e.summarize(report.Cases)
+
+	return report, nil
+}
+
+func (e *Evaluator) evaluateCase(ctx context.Context, c *Case) *CaseResult {
+	result := &CaseResult{
+		ID:        c.ID,
+		Question:  c.Question,
+		Expected:  c.ExpectedSources,
+		Reference: c.ReferenceAnswer,
+	}
+
+	if e.newAgent == nil {
+		results, err := e.store.Search(ctx, &vectorstorer.SearchParams{
+			Query: c.Question,
+			Limit: e.k,
+		})
+		if err != nil {
+			result.Error = fmt.Sprintf("error retrieving: %s", err)
+			return result
+		}
+
+		e.scoreRetrieval(result, rag.NewSources(results))
+		return result
+	}
+
+	pipeline, err := rag.NewPipeline(&rag.PipelineOpts{
+		Store:    e.store,
+		NewAgent: e.newAgent,
+		Limit:    e.k,
+		Logger:   e.logger,
+	})
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	answer, err := pipeline.Answer(ctx, c.Question)
+	if answer != nil {
+		e.scoreRetrieval(result, answer.Retrieved)
+		result.Answer = answer.Content
+		result.Cited = Documents(answer.Citations)
+	}
+
+	if err != nil {
+		result.Error = fmt.Sprintf("error answering: %s", err)
+		return result
+	}
+
+	judgement, err := e.judge.Judge(ctx, c, answer)
+	if err != nil {
+		result.Error = fmt.Sprintf("error judging: %s", err)
+		return result
+	}
+
+	result.Judgement = judgement
+
+	return result
+}
+
+func (e *Evaluator) scoreRetrieval(result *CaseResult, sources []*rag.Source) {
+	result.Retrieved = Documents(sources)
+	result.RecallAtK = RecallAtK(result.Retrieved, result.Expected, e.k)
+	result.ReciprocalRank = ReciprocalRank(result.Retrieved, result.Expected)
+}
+
+// summarize averages retrieval metrics over the cases that retrieved,
+// faithfulness over judged answers and correctness over judged answers
+// with a reference answer.
+func (e *Evaluator) summarize(results []*CaseResult) *Summary {
+	summary := &Summary{
+		Cases: len(results),
+		K:     e.k,
+	}
+
+	if e.newAgent != nil {
+		summary.Judge = e.judge.Name()
+	}
+
+	retrieved := 0
+	for _, r := range results {
+		if r.Error != "" {
+			summary.Errors++
+		}
+
+		if r.Retrieved != nil {
+			retrieved++
+			summary.RecallAtK += r.RecallAtK
+			summary.MRR += r.ReciprocalRank
+		}
+
+		if r.Judgement == nil {
+			continue
+		}
+
+		summary.Judged++
+		summary.Faithfulness += r.Judgement.Faithfulness
+
+		if r.Reference != "" {
+			summary.WithReference++
+			summary.Correctness += r.Judgement.Correctness
+		}
+	}
+
+	if retrieved > 0 {
+		summary.RecallAtK /= float64(retrieved)
+		summary.MRR /= float64(retrieved)
+	}
+
+	if summary.Judged > 0 {
+		summary.Faithfulness /= float64(summary.Judged)
+	}
+
+	if summary.WithReference > 0 {
+		summary.Correctness /= float64(summary.WithReference)
+	}
+
+	return summary
+}
@@ -0,0 +1,157 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/examples/internal/llmjson"
+	"github.com/agent-api/examples/rag"
+)
+
+// Judgement scores a single answer. Scores are in the range 0.0-1.0.
+type Judgement struct {
+	// Faithfulness is how well the answer is supported by the retrieved
+	// sources
+	Faithfulness float64 `json:"faithfulness"`
+
+	// Correctness is how well the answer agrees with the reference answer
+	Correctness float64 `json:"correctness"`
+
+	Reason string `json:"reason,omitempty"`
+}
+
+// Judge scores an answer to a case.
+type Judge interface {
+	Name() string
+	Judge(ctx context.Context, c *Case, answer *rag.Answer) (*Judgement, error)
+}
+
+// MatchJudge judges answers lexically, without a model. Faithfulness is the
+// Support of the answer by its retrieved sources. Correctness is 1 or 0 by
+// ExactMatch, or the TokenF1 with the reference when Fuzzy.
+type MatchJudge struct {
+	Fuzzy bool
+}
+
+// Name implements Judge.
+func (m *MatchJudge) Name() string {
+	if m.Fuzzy {
+		return "fuzzy"
+	}
+
+	return "exact"
+}
+
+// Judge implements Judge.
+func (m *MatchJudge) Judge(ctx context.Context, c *Case, answer *rag.Answer) (*Judgement, error) {
+	judgement := &Judgement{
+		Faithfulness: Support(answer.Content, answer.Retrieved),
+	}
+
+	switch {
+	case m.Fuzzy:
+		judgement.Correctness = TokenF1(answer.Content, c.ReferenceAnswer)
+	case ExactMatch(answer.Content, c.ReferenceAnswer):
+		judgement.Correctness = 1
+	}
+
+	return judgement, nil
+}
+
+// LLMJudge asks a model to grade answers against their sources and
+// reference answer.
+type LLMJudge struct {
+	provider core.Provider
+}
+
+// NewLLMJudge returns a judge that uses the given provider. The provider
+// should already have a model set via UseModel.
+func NewLLMJudge(provider core.Provider) *LLMJudge {
+	return &LLMJudge{
+		provider: provider,
+	}
+}
+
+// Name implements Judge.
+func (l *LLMJudge) Name() string {
+	return "llm"
+}
+
+const llmJudgePrompt string = `You are grading an answer produced by a retrieval augmented assistant.
+
+Question:
+%s
+
+Sources the assistant was given:
+%s
+Reference answer:
+%s
+
+Assistant's answer:
+%s
+
+Rate from 0 to 10:
+- faithfulness: every claim in the answer is supported by the sources (10) or the answer makes claims the sources do not support (0)
+- correctness: the answer agrees with the reference answer (10) or contradicts or misses it (0). Rate 0 if there is no reference answer.
+
+Respond ONLY with a JSON object in the form {"faithfulness": 0, "correctness": 0, "reason": "a short explanation"}.`
+
+type llmJudgement struct {
+	Faithfulness json.Number `json:"faithfulness"`
+	Correctness  json.Number `json:"correctness"`
+	Reason       string      `json:"reason"`
+}
+
+// Judge implements Judge.
+func (l *LLMJudge) Judge(ctx context.Context, c *Case, answer *rag.Answer) (*Judgement, error) {
+	var sources strings.Builder
+	for _, s := range answer.Retrieved {
+		fmt.Fprintf(&sources, "[%s] %s\n\n", s.ID, strings.TrimSpace(s.Embedding.Content))
+	}
+
+	reference := c.ReferenceAnswer
+	if reference == "" {
+		reference = "(none)"
+	}
+
+	resp, err := l.provider.Generate(ctx, &core.GenerateOptions{
+		Messages: []*core.Message{
+			{
+				Role:    core.UserMessageRole,
+				Content: fmt.Sprintf(llmJudgePrompt, c.Question, sources.String(), reference, answer.Content),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error generating judgement: %w", err)
+	}
+
+	data, ok := llmjson.Extract(resp.Content, '{', '}')
+	if !ok {
+		return nil, fmt.Errorf("judgement was not JSON: %s", resp.Content)
+	}
+
+	parsed := &llmJudgement{}
+	if err := json.Unmarshal([]byte(data), parsed); err != nil {
+		return nil, fmt.Errorf("error unmarshaling judgement: %w", err)
+	}
+
+	return &Judgement{
+		Faithfulness: rating(parsed.Faithfulness),
+		Correctness:  rating(parsed.Correctness),
+		Reason:       parsed.Reason,
+	}, nil
+}
+
+// rating scales a 0-10 rating to 0.0-1.0. Missing ratings are 0.
+func rating(n json.Number) float64 {
+	value, err := n.Float64()
+	if err != nil {
+		return 0
+	}
+
+	return min(max(value, 0), 10) / 10
+}
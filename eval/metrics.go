@@ -0,0 +1,149 @@
+package eval
+
+import (
+	"strings"
+
+	"github.com/agent-api/examples/rag"
+	"github.com/agent-api/examples/vectorstorer"
+)
+
+// Documents returns the distinct documents of sources in the order they
+// were first retrieved. Sources without a document are skipped.
+func Documents(sources []*rag.Source) []string {
+	seen := map[string]bool{}
+	documents := []string{}
+
+	for _, s := range sources {
+		document := s.Document()
+		if document == "" || seen[document] {
+			continue
+		}
+
+		seen[document] = true
+		documents = append(documents, document)
+	}
+
+	return documents
+}
+
+// RecallAtK returns the fraction of expected documents found in the first k
+// retrieved documents. It is 1 when nothing is expected.
+func RecallAtK(retrieved, expected []string, k int) float64 {
+	if len(expected) == 0 {
+		return 1
+	}
+
+	if k < len(retrieved) {
+		retrieved = retrieved[:k]
+	}
+
+	found := 0
+	for _, e := range expected {
+		for _, r := range retrieved {
+			if r == e {
+				found++
+				break
+			}
+		}
+	}
+
+	return float64(found) / float64(len(expected))
+}
+
+// ReciprocalRank returns 1/rank of the first retrieved document that is
+// expected, or 0 if none is. Averaged over cases it is the mean reciprocal
+// rank (MRR).
+func ReciprocalRank(retrieved, expected []string) float64 {
+	for i, r := range retrieved {
+		for _, e := range expected {
+			if r == e {
+				return 1 / float64(i+1)
+			}
+		}
+	}
+
+	return 0
+}
+
+// Normalize lower cases text and reduces it to its words, for comparing
+// answers regardless of punctuation, spacing and citations.
+func Normalize(text string) string {
+	return strings.Join(vectorstorer.Tokenize(rag.StripCitations(text)), " ")
+}
+
+// ExactMatch reports whether the normalized answer contains the normalized
+// reference.
+func ExactMatch(answer, reference string) bool {
+	reference = Normalize(reference)
+	if reference == "" {
+		return false
+	}
+
+	return strings.Contains(" "+Normalize(answer)+" ", " "+reference+" ")
+}
+
+// TokenF1 returns the harmonic mean of the precision and recall of the
+// answer's words against the reference's words.
+func TokenF1(answer, reference string) float64 {
+	answerTokens := vectorstorer.Tokenize(rag.StripCitations(answer))
+	referenceTokens := vectorstorer.Tokenize(rag.StripCitations(reference))
+	if len(answerTokens) == 0 || len(referenceTokens) == 0 {
+		return 0
+	}
+
+	counts := map[string]int{}
+	for _, t := range referenceTokens {
+		counts[t]++
+	}
+
+	common := 0
+	for _, t := range answerTokens {
+		if counts[t] > 0 {
+			counts[t]--
+			common++
+		}
+	}
+
+	if common == 0 {
+		return 0
+	}
+
+	precision := float64(common) / float64(len(answerTokens))
+	recall := float64(common) / float64(len(referenceTokens))
+
+	return 2 * precision * recall / (precision + recall)
+}
+
+// minContentWordLength excludes most function words, i.e., "the" or "and",
+// from Support.
+const minContentWordLength = 4
+
+// Support returns the fraction of the answer's content words that appear in
+// the sources, a lexical approximation of faithfulness. An answer with no
+// content words is fully supported.
+func Support(answer string, sources []*rag.Source) float64 {
+	available := map[string]bool{}
+	for _, s := range sources {
+		for _, t := range vectorstorer.Tokenize(s.Embedding.Content) {
+			available[t] = true
+		}
+	}
+
+	words, supported := 0, 0
+	for _, t := range vectorstorer.Tokenize(rag.StripCitations(answer)) {
+		if len(t) < minContentWordLength {
+			continue
+		}
+
+		words++
+		if available[t] {
+			supported++
+		}
+	}
+
+	if words == 0 {
+		return 1
+	}
+
+	return float64(supported) / float64(words)
+}
@@ -0,0 +1,196 @@
+package eval
+
+import (
+	"context"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/agent-api/examples/rag"
+	"github.com/agent-api/examples/vectorstorer"
+)
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestRetrievalMetrics(t *testing.T) {
+	tests := []struct {
+		name      string
+		retrieved []string
+		expected  []string
+		k         int
+		recall    float64
+		rank      float64
+	}{
+		{
+			name:      "first hit second",
+			retrieved: []string{"a", "b", "c"},
+			expected:  []string{"b", "d"},
+			k:         3,
+			recall:    0.5,
+			rank:      0.5,
+		},
+		{
+			name:      "hit past k",
+			retrieved: []string{"a", "b", "c"},
+			expected:  []string{"c"},
+			k:         2,
+			recall:    0,
+			rank:      1.0 / 3,
+		},
+		{
+			name:      "all found",
+			retrieved: []string{"b", "a"},
+			expected:  []string{"a", "b"},
+			k:         5,
+			recall:    1,
+			rank:      1,
+		},
+		{
+			name:      "nothing expected",
+			retrieved: []string{"a"},
+			k:         5,
+			recall:    1,
+			rank:      0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RecallAtK(tt.retrieved, tt.expected, tt.k); !approx(got, tt.recall) {
+				t.Errorf("got recall %f, want %f", got, tt.recall)
+			}
+
+			if got := ReciprocalRank(tt.retrieved, tt.expected); !approx(got, tt.rank) {
+				t.Errorf("got reciprocal rank %f, want %f", got, tt.rank)
+			}
+		})
+	}
+}
+
+func TestAnswerMetrics(t *testing.T) {
+	tests := []struct {
+		name      string
+		answer    string
+		reference string
+		exact     bool
+		f1        float64
+	}{
+		{
+			name:      "reference within the answer",
+			answer:    "Fire Bolt deals 1d10 fire damage [a1b2c3d4].",
+			reference: "1d10 fire damage",
+			exact:     true,
+			f1:        2 * (3.0 / 6) * 1 / (3.0/6 + 1),
+		},
+		{
+			name:      "partial words do not match",
+			answer:    "It deals fire damages",
+			reference: "fire damage",
+			f1:        2 * (1.0 / 4) * (1.0 / 2) / (1.0/4 + 1.0/2),
+		},
+		{
+			name:      "punctuation and case",
+			answer:    "120 FEET!",
+			reference: "120 feet",
+			exact:     true,
+			f1:        1,
+		},
+		{
+			name:   "no reference",
+			answer: "anything",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExactMatch(tt.answer, tt.reference); got != tt.exact {
+				t.Errorf("got exact match %t, want %t", got, tt.exact)
+			}
+
+			if got := TokenF1(tt.answer, tt.reference); !approx(got, tt.f1) {
+				t.Errorf("got F1 %f, want %f", got, tt.f1)
+			}
+		})
+	}
+}
+
+func source(document, content string) *rag.Source {
+	return &rag.Source{
+		Embedding: &vectorstorer.Embedding{
+			Content:  content,
+			Metadata: map[string]any{vectorstorer.MetadataSource: document},
+		},
+	}
+}
+
+func TestSupport(t *testing.T) {
+	sources := []*rag.Source{
+		source("fire-bolt.md", "Fire Bolt deals fire damage to a creature within range"),
+	}
+
+	// "deals", "fire" and "damage" are supported, "cold" is not and words
+	// shorter than four letters are skipped
+	if got := Support("It deals fire damage, not cold [a1b2c3d4]", sources); !approx(got, 0.75) {
+		t.Errorf("got support %f, want 0.75", got)
+	}
+
+	if got := Support("Yes.", sources); got != 1 {
+		t.Errorf("got support %f for an answer without content words", got)
+	}
+}
+
+func TestDocuments(t *testing.T) {
+	sources := []*rag.Source{
+		source("b.md", "b1"),
+		source("a.md", "a1"),
+		source("b.md", "b2"),
+		source("", "unknown"),
+	}
+
+	if got := Documents(sources); !slices.Equal(got, []string{"b.md", "a.md"}) {
+		t.Errorf("got documents %v", got)
+	}
+}
+
+func TestEvaluateRetrieval(t *testing.T) {
+	ctx := context.Background()
+	embedder := vectorstorer.NewHashEmbedder(&vectorstorer.HashEmbedderOpts{})
+
+	store, err := vectorstorer.NewMemoryStore(&vectorstorer.MemoryStoreOpts{Embedder: embedder})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	docs := []*vectorstorer.Embedding{
+		{ID: "fire-bolt", Content: "Fire Bolt hurls a mote of fire", Metadata: map[string]any{vectorstorer.MetadataSource: "fire-bolt.md"}},
+		{ID: "ray-of-frost", Content: "Ray of Frost is a frigid beam", Metadata: map[string]any{vectorstorer.MetadataSource: "ray-of-frost.md"}},
+	}
+
+	if _, err := vectorstorer.UpsertByContentHash(ctx, store, embedder, docs); err != nil {
+		t.Fatal(err)
+	}
+
+	evaluator, err := NewEvaluator(&EvaluatorOpts{Store: store, K: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := evaluator.Evaluate(ctx, []*Case{
+		{ID: "bolt", Question: "mote of fire", ExpectedSources: []string{"fire-bolt.md"}},
+		{ID: "frost", Question: "frigid beam", ExpectedSources: []string{"fire-bolt.md"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary := report.Summary
+	if summary.Cases != 2 || summary.Errors != 0 || summary.Judge != "" || summary.Judged != 0 {
+		t.Errorf("got summary %+v", summary)
+	}
+
+	if !approx(summary.RecallAtK, 0.5) || !approx(summary.MRR, 0.5) {
+		t.Errorf("got recall %f and MRR %f, want 0.5 and 0.5", summary.RecallAtK, summary.MRR)
+	}
+}
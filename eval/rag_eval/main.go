@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/core/agent/bootstrap"
+	"github.com/agent-api/examples/chunking"
+	"github.com/agent-api/examples/eval"
+	"github.com/agent-api/examples/ingest"
+	"github.com/agent-api/examples/internal/agentrun"
+	"github.com/agent-api/examples/rerank"
+	"github.com/agent-api/examples/vectorstorer"
+	"github.com/agent-api/ollama"
+	ollamamodels "github.com/agent-api/ollama/models"
+	"github.com/agent-api/openai"
+	openaimodels "github.com/agent-api/openai/models"
+)
+
+func main() {
+	dataset := flag.String("dataset", "./dataset.jsonl", "JSONL file of questions, expected sources and reference answers")
+	docs := flag.String("docs", "./docs", "directory of documents to ingest and answer from")
+	k := flag.Int("k", 5, "chunks retrieved per question")
+	embedderName := flag.String("embedder", "ollama", "embedder: hash, ollama or openai")
+	chunkerName := flag.String("chunker", "default", "chunker: default (by file type), recursive, token, sentence or markdown")
+	chunkSize := flag.Int("chunk-size", chunking.DefaultSize, "chunk size in tokens")
+	hybrid := flag.Bool("hybrid", false, "fuse vector search with BM25")
+	rerankerName := flag.String("rerank", "none", "reranker: none, lexical or llm")
+	candidates := flag.Int("candidates", 20, "candidates retrieved for reranking")
+	judgeName := flag.String("judge", "fuzzy", "answer judge: llm, exact or fuzzy")
+	providerName := flag.String("provider", "ollama", "provider answering, judging and reranking: ollama or openai")
+	retrievalOnly := flag.Bool("retrieval-only", false, "only evaluate retrieval, without running the agent")
+	out := flag.String("out", "", "file to write the full JSON report to")
+	flag.Parse()
+
+	ctx := context.Background()
+
+	// Create a zap logger
+	config := zap.NewDevelopmentConfig()
+	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	zLogger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a logr.Logger using zapr adapter
+	logger := zapr.NewLogger(zLogger)
+
+	cases, err := eval.LoadDataset(*dataset)
+	if err != nil {
+		panic(err)
+	}
+
+	embedder, err := newEmbedder(*embedderName, &logger)
+	if err != nil {
+		panic(err)
+	}
+
+	memory, err := vectorstorer.NewMemoryStore(&vectorstorer.MemoryStoreOpts{
+		Embedder: embedder,
+		Logger:   &logger,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	ingesterOpts := &ingest.IngesterOpts{
+		Store:    memory,
+		Embedder: embedder,
+		Logger:   &logger,
+	}
+
+	// any chunker other than the default is used for every file type
+	if *chunkerName != "default" {
+		chunker, err := newChunker(*chunkerName, *chunkSize)
+		if err != nil {
+			panic(err)
+		}
+
+		ingesterOpts.Chunkers = map[string]chunking.Chunker{}
+		ingesterOpts.DefaultChunker = chunker
+	}
+
+	ingester, err := ingest.NewIngester(ingesterOpts)
+	if err != nil {
+		panic(err)
+	}
+
+	stats, err := ingester.IngestDir(ctx, *docs)
+	if err != nil {
+		panic(err)
+	}
+
+	logger.Info("ingested documents", "files", stats.Files, "chunks", stats.Chunks)
+
+	provider, err := newProvider(ctx, *providerName, &logger)
+	if err != nil {
+		panic(err)
+	}
+
+	var store vectorstorer.VectorStorer = memory
+	if *hybrid {
+		store, err = vectorstorer.NewHybridStore(&vectorstorer.HybridStoreOpts{
+			Store: memory,
+		})
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	if *rerankerName != "none" {
+		var reranker rerank.Reranker
+		switch *rerankerName {
+		case "lexical":
+			reranker = rerank.NewLexicalReranker()
+		case "llm":
+			reranker = rerank.NewLLMReranker(provider)
+		default:
+			panic(fmt.Sprintf("unknown reranker %s", *rerankerName))
+		}
+
+		store, err = rerank.NewStore(&rerank.StoreOpts{
+			Store:      store,
+			Reranker:   reranker,
+			Candidates: *candidates,
+			Limit:      *k,
+		})
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var judge eval.Judge
+	switch *judgeName {
+	case "llm":
+		judge = eval.NewLLMJudge(provider)
+	case "exact":
+		judge = &eval.MatchJudge{}
+	case "fuzzy":
+		judge = &eval.MatchJudge{Fuzzy: true}
+	default:
+		panic(fmt.Sprintf("unknown judge %s", *judgeName))
+	}
+
+	evaluatorOpts := &eval.EvaluatorOpts{
+		Store:  store,
+		Judge:  judge,
+		K:      *k,
+		Logger: &logger,
+	}
+
+	if !*retrievalOnly {
+		evaluatorOpts.NewAgent = func() (agentrun.Runner, error) {
+			return agent.NewAgent(
+				bootstrap.WithProvider(provider),
+				bootstrap.WithLogger(&logger),
+			)
+		}
+	}
+
+	evaluator, err := eval.NewEvaluator(evaluatorOpts)
+	if err != nil {
+		panic(err)
+	}
+
+	report, err := evaluator.Evaluate(ctx, cases)
+	if err != nil {
+		logger.Error(err, "evaluation stopped")
+	}
+
+	for _, c := range report.Cases {
+		fmt.Printf("%-8s recall@%d %.2f  rr %.2f", c.ID, *k, c.RecallAtK, c.ReciprocalRank)
+		if c.Judgement != nil {
+			fmt.Printf("  faithfulness %.2f  correctness %.2f", c.Judgement.Faithfulness, c.Judgement.Correctness)
+		}
+
+		if c.Error != "" {
+			fmt.Printf("  error: %s", c.Error)
+		}
+
+		fmt.Println()
+	}
+
+	s := report.Summary
+	fmt.Printf("\ncases: %d (errors %d)\n", s.Cases, s.Errors)
+	fmt.Printf("recall@%d: %.3f\n", s.K, s.RecallAtK)
+	fmt.Printf("MRR: %.3f\n", s.MRR)
+	if s.Judge != "" {
+		fmt.Printf("faithfulness (%s, %d answers): %.3f\n", s.Judge, s.Judged, s.Faithfulness)
+		fmt.Printf("correctness (%s, %d answers): %.3f\n", s.Judge, s.WithReference, s.Correctness)
+	}
+
+	if *out != "" {
+		b, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			panic(err)
+		}
+
+		err = os.WriteFile(*out, b, 0o644)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func newEmbedder(name string, logger *logr.Logger) (vectorstorer.Embedder, error) {
+	switch name {
+	case "hash":
+		return vectorstorer.NewHashEmbedder(&vectorstorer.HashEmbedderOpts{}), nil
+	case "ollama":
+		return vectorstorer.NewOllamaEmbedder(&vectorstorer.OllamaEmbedderOpts{
+			Logger: logger,
+		}), nil
+	case "openai":
+		return vectorstorer.NewOpenAIEmbedder(&vectorstorer.OpenAIEmbedderOpts{
+			Logger: logger,
+		}), nil
+	}
+
+	return nil, fmt.Errorf("unknown embedder %s", name)
+}
+
+func newChunker(name string, size int) (chunking.Chunker, error) {
+	switch name {
+	case "recursive":
+		return &chunking.RecursiveChunker{Size: size}, nil
+	case "token":
+		return &chunking.TokenChunker{Size: size, Overlap: size / 8}, nil
+	case "sentence":
+		return &chunking.SentenceChunker{Size: size, Overlap: 1}, nil
+	case "markdown":
+		return &chunking.MarkdownChunker{Size: size}, nil
+	}
+
+	return nil, fmt.Errorf("unknown chunker %s", name)
+}
+
+func newProvider(ctx context.Context, name string, logger *logr.Logger) (core.Provider, error) {
+	switch name {
+	case "openai":
+		provider := openai.NewProvider(&openai.ProviderOpts{
+			Logger: logger,
+		})
+		provider.UseModel(ctx, openaimodels.GPT4_O)
+
+		return provider, nil
+	case "ollama":
+		provider := ollama.NewProvider(&ollama.ProviderOpts{
+			Logger:  logger,
+			BaseURL: "http://localhost",
+			Port:    11434,
+		})
+		provider.UseModel(ctx, ollamamodels.QWEN2_5_LATEST)
+
+		return provider, nil
+	}
+
+	return nil, fmt.Errorf("unknown provider %s", name)
+}
@@ -0,0 +1,446 @@
+// Package handoff lets agents transfer control of a conversation to one another
+// mid-run. Unlike delegation (see the orchestration package), the receiving
+// agent takes over the conversation entirely: its own system prompt, tools and
+// provider are used for every following step.
+package handoff
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	"github.com/agent-api/core"
+)
+
+// Agent is a participant in a conversation that may receive handoffs.
+type Agent struct {
+	// Name uniquely identifies the agent
+	Name string
+
+	// Description tells other agents when to hand off to this agent
+	Description string
+
+	SystemPrompt string
+
+	// The core.Provider this agent generates with. Each agent may use a
+	// different provider and model.
+	Provider core.Provider
+
+	// Tools this agent may call
+	Tools []*core.Tool
+
+	// HandoffTo are the names of the agents this agent may transfer the
+	// conversation to
+	HandoffTo []string
+}
+
+// Handoff records a single transfer of control between agents.
+type Handoff struct {
+	From   string
+	To     string
+	Reason string
+
+	// Step is the index, in Result.Messages, of the last message before the
+	// receiving agent took over
+	Step int
+
+	// Summary is set when the history was summarized for the receiving agent
+	Summary string
+}
+
+// CoordinatorOpts configures a new Coordinator.
+type CoordinatorOpts struct {
+	Agents []*Agent
+
+	// Maximum number of messages in a run before forcing a stop
+	MaxSteps int
+
+	// SummarizeOnHandoff replaces the message history with a summary, generated
+	// by the handing off agent's provider, before the receiving agent takes over.
+	// When false the full history is carried over.
+	SummarizeOnHandoff bool
+
+	Logger *logr.Logger
+}
+
+// Coordinator runs a conversation across agents that can hand off to each other.
+type Coordinator struct {
+	agents             map[string]*Agent
+	maxSteps           int
+	summarizeOnHandoff bool
+
+	logger *logr.Logger
+}
+
+// Result is the outcome of a coordinated run.
+type Result struct {
+	// Messages are all messages of the run across every agent
+	Messages []*core.Message
+
+	// Handoffs is the chain of handoffs, in order
+	Handoffs []*Handoff
+
+	// FinalAgent is the name of the agent that produced the final message
+	FinalAgent string
+}
+
+// FinalMessage returns the last message of the run.
+func (r *Result) FinalMessage() *core.Message {
+	if len(r.Messages) == 0 {
+		return nil
+	}
+
+	return r.Messages[len(r.Messages)-1]
+}
+
+// Chain returns the names of every agent that held the conversation, in order.
+func (r *Result) Chain() []string {
+	if len(r.Handoffs) == 0 {
+		return []string{r.FinalAgent}
+	}
+
+	chain := []string{r.Handoffs[0].From}
+	for _, h := range r.Handoffs {
+		chain = append(chain, h.To)
+	}
+
+	return chain
+}
+
+const transferToolPrefix string = "transfer_to_"
+
+const transferSchema string = `{
+  "title": "transfer",
+  "description": "Transfer the conversation to another agent",
+  "type": "object",
+  "properties": {
+    "reason": {
+      "description": "Why the conversation is being transferred",
+      "type": "string"
+    }
+  },
+  "required": [
+    "reason"
+  ]
+}`
+
+type transferParams struct {
+	Reason string `json:"reason"`
+}
+
+// NewCoordinator creates a new Coordinator.
+func NewCoordinator(opts *CoordinatorOpts) (*Coordinator, error) {
+	if opts.MaxSteps == 0 {
+		opts.MaxSteps = 25
+	}
+
+	c := &Coordinator{
+		agents:             make(map[string]*Agent),
+		maxSteps:           opts.MaxSteps,
+		summarizeOnHandoff: opts.SummarizeOnHandoff,
+		logger:             opts.Logger,
+	}
+
+	for _, a := range opts.Agents {
+		if a.Name == "" {
+			return nil, errors.New("agent must have a name")
+		}
+
+		if a.Provider == nil {
+			return nil, fmt.Errorf("agent %s has no provider", a.Name)
+		}
+
+		if _, ok := c.agents[a.Name]; ok {
+			return nil, fmt.Errorf("agent %s already registered", a.Name)
+		}
+
+		c.agents[a.Name] = a
+	}
+
+	for _, a := range opts.Agents {
+		for _, to := range a.HandoffTo {
+			if _, ok := c.agents[to]; !ok {
+				return nil, fmt.Errorf("agent %s hands off to unknown agent %s", a.Name, to)
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// Run starts a conversation with the named agent and returns once an agent
+// produces a final answer.
+func (c *Coordinator) Run(ctx context.Context, start string, input string) (*Result, error) {
+	current, ok := c.agents[start]
+	if !ok {
+		return nil, fmt.Errorf("agent %s not found", start)
+	}
+
+	result := &Result{
+		FinalAgent: current.Name,
+	}
+
+	userMessage := &core.Message{
+		Role:    core.UserMessageRole,
+		Content: input,
+	}
+	result.Messages = append(result.Messages, userMessage)
+
+	// history is what the current agent sees: it may differ from the full
+	// result messages when summarized
+	history := []*core.Message{userMessage}
+
+	for {
+		if len(result.Messages) >= c.maxSteps {
+			return result, fmt.Errorf("exceeded maximum steps: %d - %d", len(result.Messages), c.maxSteps)
+		}
+
+		resp, err := current.Provider.Generate(ctx, &core.GenerateOptions{
+			Messages: c.withSystemPrompt(current, history),
+			Tools:    c.toolsFor(current),
+		})
+		if err != nil {
+			return result, fmt.Errorf("agent %s failed generating: %w", current.Name, err)
+		}
+
+		result.Messages = append(result.Messages, resp)
+		history = append(history, resp)
+
+		if len(resp.ToolCalls) == 0 {
+			return result, nil
+		}
+
+		toolMessages, next, reason := c.executeToolCalls(ctx, current, resp.ToolCalls)
+		result.Messages = append(result.Messages, toolMessages...)
+		history = append(history, toolMessages...)
+
+		if next == nil {
+			continue
+		}
+
+		h := &Handoff{
+			From:   current.Name,
+			To:     next.Name,
+			Reason: reason,
+			Step:   len(result.Messages) - 1,
+		}
+
+		if c.logger != nil {
+			c.logger.V(1).Info("handing off", "from", h.From, "to", h.To, "reason", h.Reason)
+		}
+
+		if c.summarizeOnHandoff {
+			summary, err := c.summarize(ctx, current, history)
+			if err != nil {
+				return result, err
+			}
+
+			h.Summary = summary
+			history = []*core.Message{
+				{
+					Role:    core.UserMessageRole,
+					Content: fmt.Sprintf("You are taking over a conversation from %s (%s). Summary of the conversation so far:\n\n%s\n\nThe original request was:\n\n%s", h.From, h.Reason, summary, input),
+				},
+			}
+		} else {
+			// tool calls are specific to the agent that made them: the receiving
+			// agent may not have those tools, so only plain text is carried over
+			history = append(textOnly(history), &core.Message{
+				Role:    core.UserMessageRole,
+				Content: fmt.Sprintf("You are taking over this conversation from %s (%s). Continue helping with the original request.", h.From, h.Reason),
+			})
+		}
+
+		result.Handoffs = append(result.Handoffs, h)
+		result.FinalAgent = next.Name
+		current = next
+	}
+}
+
+func (c *Coordinator) withSystemPrompt(a *Agent, history []*core.Message) []*core.Message {
+	if a.SystemPrompt == "" {
+		return history
+	}
+
+	return append([]*core.Message{
+		{
+			Role:    core.SystemMessageRole,
+			Content: a.SystemPrompt,
+		},
+	}, history...)
+}
+
+// toolsFor returns an agent's tools plus one transfer tool per agent it may
+// hand off to.
+func (c *Coordinator) toolsFor(a *Agent) []*core.Tool {
+	tools := append([]*core.Tool{}, a.Tools...)
+
+	for _, name := range a.HandoffTo {
+		to := c.agents[name]
+		tools = append(tools, &core.Tool{
+			Name:        transferToolPrefix + to.Name,
+			Description: fmt.Sprintf("Transfer the conversation to %s: %s", to.Name, to.Description),
+			JSONSchema:  []byte(transferSchema),
+
+			// transfers are intercepted in executeToolCalls and never called
+			WrappedToolFunction: func(ctx context.Context, args []byte) (interface{}, error) {
+				return nil, errors.New("transfer tools are handled by the coordinator")
+			},
+		})
+	}
+
+	return tools
+}
+
+// executeToolCalls runs every tool call for the given agent. If any call is a
+// transfer, the receiving agent and the transfer reason are returned. Only the
+// first transfer in a batch of calls is honored.
+func (c *Coordinator) executeToolCalls(ctx context.Context, a *Agent, calls []*core.ToolCall) ([]*core.Message, *Agent, string) {
+	messages := []*core.Message{}
+
+	var next *Agent
+	var reason string
+
+	for _, tc := range calls {
+		if !strings.HasPrefix(tc.Name, transferToolPrefix) {
+			messages = append(messages, callTool(ctx, a, tc))
+			continue
+		}
+
+		if next != nil {
+			messages = append(messages, toolError(tc, fmt.Sprintf("conversation is already being transferred to %s", next.Name)))
+			continue
+		}
+
+		m, to, r := c.transfer(a, tc)
+		messages = append(messages, m)
+		next, reason = to, r
+	}
+
+	return messages, next, reason
+}
+
+// transfer validates a transfer tool call and returns its tool result message
+// along with the receiving agent and reason. The agent is nil when the
+// transfer is not allowed or its arguments are malformed.
+func (c *Coordinator) transfer(a *Agent, tc *core.ToolCall) (*core.Message, *Agent, string) {
+	name := strings.TrimPrefix(tc.Name, transferToolPrefix)
+
+	to, ok := c.agents[name]
+	allowed := false
+	for _, n := range a.HandoffTo {
+		allowed = allowed || n == name
+	}
+
+	if !ok || !allowed {
+		return toolError(tc, fmt.Sprintf("agent %s cannot transfer to %s", a.Name, name)), nil, ""
+	}
+
+	// the reason is optional, so models may send no arguments at all
+	params := &transferParams{}
+	if len(tc.Arguments) > 0 {
+		if err := json.Unmarshal(tc.Arguments, params); err != nil {
+			return toolError(tc, fmt.Sprintf("error unmarshaling args: %s", err)), nil, ""
+		}
+	}
+
+	content := fmt.Sprintf("transferred to %s", to.Name)
+	return &core.Message{
+		Role:    core.ToolMessageRole,
+		Content: content,
+		ToolResult: []*core.ToolResult{
+			{
+				ToolCallID: tc.ID,
+				Content:    content,
+			},
+		},
+	}, to, params.Reason
+}
+
+func callTool(ctx context.Context, a *Agent, tc *core.ToolCall) *core.Message {
+	var tool *core.Tool
+	for _, t := range a.Tools {
+		if t.Name == tc.Name {
+			tool = t
+			break
+		}
+	}
+
+	if tool == nil {
+		return toolError(tc, fmt.Sprintf("tool %s not found", tc.Name))
+	}
+
+	result, err := tool.WrappedToolFunction(ctx, []byte(tc.Arguments))
+	if err != nil {
+		return toolError(tc, fmt.Sprintf("internal error executing tool %s: %v", tc.Name, err))
+	}
+
+	return &core.Message{
+		Role:    core.ToolMessageRole,
+		Content: fmt.Sprintf("%v", result),
+		ToolResult: []*core.ToolResult{
+			{
+				ToolCallID: tc.ID,
+				Content:    result,
+			},
+		},
+	}
+}
+
+func toolError(tc *core.ToolCall, msg string) *core.Message {
+	return &core.Message{
+		Role: core.ToolMessageRole,
+		ToolResult: []*core.ToolResult{
+			{
+				ToolCallID: tc.ID,
+				Error:      msg,
+			},
+		},
+	}
+}
+
+// textOnly returns the user and assistant messages of a history with any
+// tool calls stripped.
+func textOnly(history []*core.Message) []*core.Message {
+	out := []*core.Message{}
+
+	for _, m := range history {
+		if m.Role != core.UserMessageRole && m.Role != core.AssistantMessageRole {
+			continue
+		}
+
+		if m.Content == "" {
+			continue
+		}
+
+		out = append(out, &core.Message{
+			Role:    m.Role,
+			Content: m.Content,
+			Images:  m.Images,
+		})
+	}
+
+	return out
+}
+
+const summaryPrompt string = "Summarize the conversation above for another assistant who is taking it over. Include the user's goal, everything that has been established so far and what is left to do. Respond with the summary only."
+
+func (c *Coordinator) summarize(ctx context.Context, a *Agent, history []*core.Message) (string, error) {
+	messages := append(textOnly(history), &core.Message{
+		Role:    core.UserMessageRole,
+		Content: summaryPrompt,
+	})
+
+	resp, err := a.Provider.Generate(ctx, &core.GenerateOptions{
+		Messages: messages,
+	})
+	if err != nil {
+		return "", fmt.Errorf("agent %s failed summarizing history: %w", a.Name, err)
+	}
+
+	return resp.Content, nil
+}
@@ -0,0 +1,286 @@
+package handoff
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/agent-api/core"
+)
+
+// scriptedProvider returns its responses in order, one per Generate call,
+// and records the requests.
+type scriptedProvider struct {
+	responses []*core.Message
+	requests  []*core.GenerateOptions
+}
+
+func (p *scriptedProvider) GetCapabilities(ctx context.Context) (*core.Capabilities, error) {
+	return &core.Capabilities{}, nil
+}
+
+func (p *scriptedProvider) UseModel(ctx context.Context, model *core.Model) error {
+	return nil
+}
+
+func (p *scriptedProvider) Generate(ctx context.Context, opts *core.GenerateOptions) (*core.Message, error) {
+	p.requests = append(p.requests, opts)
+
+	msg := p.responses[0]
+	p.responses = p.responses[1:]
+
+	return msg, nil
+}
+
+func (p *scriptedProvider) GenerateStream(ctx context.Context, opts *core.GenerateOptions) (<-chan *core.Message, <-chan string, <-chan error) {
+	return nil, nil, nil
+}
+
+func answer(content string) *core.Message {
+	return &core.Message{Role: core.AssistantMessageRole, Content: content}
+}
+
+func toolCalls(calls ...*core.ToolCall) *core.Message {
+	return &core.Message{Role: core.AssistantMessageRole, ToolCalls: calls}
+}
+
+func call(id, name, args string) *core.ToolCall {
+	return &core.ToolCall{ID: id, Name: name, Arguments: json.RawMessage(args)}
+}
+
+func toolNames(tools []*core.Tool) []string {
+	names := []string{}
+	for _, t := range tools {
+		names = append(names, t.Name)
+	}
+
+	return names
+}
+
+// toolResult returns the tool result of the run's message answering a call.
+func toolResult(t *testing.T, result *Result, callID string) *core.ToolResult {
+	t.Helper()
+
+	for _, m := range result.Messages {
+		for _, tr := range m.ToolResult {
+			if tr.ToolCallID == callID {
+				return tr
+			}
+		}
+	}
+
+	t.Fatalf("no tool result for call %s", callID)
+	return nil
+}
+
+// newAgents returns a triage agent that may hand off to a math agent, which
+// has a calculator.
+func newAgents(triage, math *scriptedProvider) []*Agent {
+	return []*Agent{
+		{
+			Name:         "triage",
+			Description:  "routes requests",
+			SystemPrompt: "You route requests.",
+			Provider:     triage,
+			HandoffTo:    []string{"math"},
+		},
+		{
+			Name:         "math",
+			Description:  "solves arithmetic",
+			SystemPrompt: "You solve arithmetic.",
+			Provider:     math,
+			Tools: []*core.Tool{
+				{
+					Name: "calculator",
+					WrappedToolFunction: func(ctx context.Context, args []byte) (interface{}, error) {
+						return 5, nil
+					},
+				},
+			},
+		},
+	}
+}
+
+func newTestCoordinator(t *testing.T, opts *CoordinatorOpts) *Coordinator {
+	t.Helper()
+
+	c, err := NewCoordinator(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestHandoff(t *testing.T) {
+	triage := &scriptedProvider{responses: []*core.Message{
+		toolCalls(
+			call("call-1", "transfer_to_math", `{"reason":"arithmetic"}`),
+			call("call-2", "transfer_to_math", `{"reason":"twice"}`),
+		),
+	}}
+	math := &scriptedProvider{responses: []*core.Message{
+		toolCalls(call("call-3", "calculator", `{"a":2,"b":3}`)),
+		answer("2 + 3 = 5"),
+	}}
+
+	c := newTestCoordinator(t, &CoordinatorOpts{Agents: newAgents(triage, math)})
+
+	result, err := c.Run(context.Background(), "triage", "What is 2 + 3?")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(result.Chain(), []string{"triage", "math"}) || result.FinalAgent != "math" {
+		t.Errorf("got chain %v", result.Chain())
+	}
+
+	if h := result.Handoffs[0]; h.Reason != "arithmetic" || h.Step != 3 {
+		t.Errorf("got handoff %+v", h)
+	}
+
+	if result.FinalMessage().Content != "2 + 3 = 5" {
+		t.Errorf("got final message %q", result.FinalMessage().Content)
+	}
+
+	// only the first transfer of a batch is honored
+	if tr := toolResult(t, result, "call-2"); !strings.Contains(tr.Error, "already being transferred") {
+		t.Errorf("got second transfer result %+v", tr)
+	}
+
+	if got := toolNames(triage.requests[0].Tools); !slices.Equal(got, []string{"transfer_to_math"}) {
+		t.Errorf("triage got tools %v", got)
+	}
+
+	// the math agent takes over with its own prompt and tools, and only the
+	// conversation's text
+	first := math.requests[0]
+	if got := toolNames(first.Tools); !slices.Equal(got, []string{"calculator"}) {
+		t.Errorf("math got tools %v", got)
+	}
+
+	if len(first.Messages) != 3 ||
+		first.Messages[0].Content != "You solve arithmetic." ||
+		first.Messages[1].Content != "What is 2 + 3?" ||
+		!strings.Contains(first.Messages[2].Content, "taking over this conversation from triage (arithmetic)") {
+		t.Errorf("math started with %d messages: %+v", len(first.Messages), first.Messages)
+	}
+
+	if tr := toolResult(t, result, "call-3"); tr.Content != 5 {
+		t.Errorf("got calculator result %+v", tr)
+	}
+}
+
+func TestHandoffRejectedTransfers(t *testing.T) {
+	tests := []struct {
+		name    string
+		call    *core.ToolCall
+		wantErr string
+	}{
+		{
+			name:    "malformed arguments",
+			call:    call("call-1", "transfer_to_math", `{"reason":`),
+			wantErr: "error unmarshaling args",
+		},
+		{
+			name:    "not allowed",
+			call:    call("call-1", "transfer_to_billing", `{"reason":"refund"}`),
+			wantErr: "agent triage cannot transfer to billing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			triage := &scriptedProvider{responses: []*core.Message{
+				toolCalls(tt.call),
+				answer("I can't help with that"),
+			}}
+
+			c := newTestCoordinator(t, &CoordinatorOpts{Agents: newAgents(triage, &scriptedProvider{})})
+
+			result, err := c.Run(context.Background(), "triage", "What is 2 + 3?")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the model sees why the transfer failed and keeps the conversation
+			if tr := toolResult(t, result, "call-1"); !strings.Contains(tr.Error, tt.wantErr) {
+				t.Errorf("got tool result %+v, want error %q", tr, tt.wantErr)
+			}
+
+			if len(result.Handoffs) != 0 || result.FinalAgent != "triage" {
+				t.Errorf("got handoffs %+v", result.Handoffs)
+			}
+		})
+	}
+}
+
+func TestHandoffWithoutArguments(t *testing.T) {
+	triage := &scriptedProvider{responses: []*core.Message{
+		toolCalls(call("call-1", "transfer_to_math", "")),
+	}}
+	math := &scriptedProvider{responses: []*core.Message{answer("5")}}
+
+	c := newTestCoordinator(t, &CoordinatorOpts{Agents: newAgents(triage, math)})
+
+	result, err := c.Run(context.Background(), "triage", "What is 2 + 3?")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Handoffs) != 1 || result.Handoffs[0].Reason != "" {
+		t.Errorf("got handoffs %+v", result.Handoffs)
+	}
+}
+
+func TestHandoffSummarize(t *testing.T) {
+	triage := &scriptedProvider{responses: []*core.Message{
+		toolCalls(call("call-1", "transfer_to_math", `{"reason":"arithmetic"}`)),
+		answer("The user wants 2 + 3."),
+	}}
+	math := &scriptedProvider{responses: []*core.Message{answer("5")}}
+
+	c := newTestCoordinator(t, &CoordinatorOpts{
+		Agents:             newAgents(triage, math),
+		SummarizeOnHandoff: true,
+	})
+
+	result, err := c.Run(context.Background(), "triage", "What is 2 + 3?")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the handing off agent writes the summary
+	if len(triage.requests) != 2 || len(triage.requests[1].Tools) != 0 || result.Handoffs[0].Summary != "The user wants 2 + 3." {
+		t.Errorf("got handoff %+v", result.Handoffs[0])
+	}
+
+	// the summary replaces the history
+	messages := math.requests[0].Messages
+	if len(messages) != 2 || !strings.Contains(messages[1].Content, "The user wants 2 + 3.") || !strings.HasSuffix(messages[1].Content, "What is 2 + 3?") {
+		t.Errorf("math started with %+v", messages)
+	}
+}
+
+func TestHandoffMaxSteps(t *testing.T) {
+	loop := &scriptedProvider{}
+	for range 10 {
+		loop.responses = append(loop.responses, toolCalls(call("call", "calculator", `{}`)))
+	}
+
+	c := newTestCoordinator(t, &CoordinatorOpts{
+		Agents:   newAgents(&scriptedProvider{}, loop),
+		MaxSteps: 5,
+	})
+
+	result, err := c.Run(context.Background(), "math", "loop forever")
+	if err == nil || !strings.Contains(err.Error(), "exceeded maximum steps") {
+		t.Errorf("got error %v", err)
+	}
+
+	if len(result.Messages) != 5 {
+		t.Errorf("got %d messages, want 5", len(result.Messages))
+	}
+}
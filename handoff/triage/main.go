@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/examples/handoff"
+	"github.com/agent-api/ollama"
+	ollamamodels "github.com/agent-api/ollama/models"
+	"github.com/agent-api/openai"
+	openaimodels "github.com/agent-api/openai/models"
+)
+
+const calculatorSchema string = `{
+  "title": "calculator",
+  "description": "A simple calculator on ints",
+  "type": "object",
+  "properties": {
+    "a": {
+      "description": "The first operand",
+      "type": "number"
+    },
+    "b": {
+      "description": "The second operand",
+      "type": "number"
+    },
+    "operation": {
+      "description": "The operation to perform. One of [add, multiply]",
+      "type": "string"
+    }
+  },
+  "required": [
+    "operation",
+    "a",
+    "b"
+  ]
+}`
+
+type calculatorParams struct {
+	Operation string `json:"operation"`
+	A         int    `json:"a"`
+	B         int    `json:"b"`
+}
+
+func calculator(ctx context.Context, args *calculatorParams) (interface{}, error) {
+	switch args.Operation {
+	case "add":
+		return args.A + args.B, nil
+	case "multiply":
+		return args.A * args.B, nil
+	default:
+		return nil, fmt.Errorf("unsupported operation: %s", args.Operation)
+	}
+}
+
+func main() {
+	ctx := context.Background()
+
+	// Create a zap logger
+	config := zap.NewDevelopmentConfig()
+	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	zLogger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a logr.Logger using zapr adapter
+	logger := zapr.NewLogger(zLogger)
+
+	// The triage agent runs on a small local model
+	ollamaProvider := ollama.NewProvider(&ollama.ProviderOpts{
+		Logger:  &logger,
+		BaseURL: "http://localhost",
+		Port:    11434,
+	})
+	ollamaProvider.UseModel(ctx, ollamamodels.QWEN2_5_LATEST)
+
+	// The math specialist runs on OpenAI
+	openaiProvider := openai.NewProvider(&openai.ProviderOpts{
+		Logger: &logger,
+	})
+	openaiProvider.UseModel(ctx, openaimodels.GPT4_O)
+
+	wrappedCalc, err := core.WrapToolFunction(calculator)
+	if err != nil {
+		panic(err)
+	}
+
+	coordinator, err := handoff.NewCoordinator(&handoff.CoordinatorOpts{
+		Logger:             &logger,
+		SummarizeOnHandoff: true,
+		Agents: []*handoff.Agent{
+			{
+				Name:         "triage",
+				Description:  "Greets users and figures out what they need",
+				SystemPrompt: "You are a triage assistant. If the user needs any arithmetic done, transfer them to the math specialist.",
+				Provider:     ollamaProvider,
+				HandoffTo:    []string{"math_specialist"},
+			},
+			{
+				Name:         "math_specialist",
+				Description:  "Solves arithmetic problems using a calculator",
+				SystemPrompt: "You are a math specialist. Always use the calculator tool. When you are done, transfer back to triage.",
+				Provider:     openaiProvider,
+				HandoffTo:    []string{"triage"},
+				Tools: []*core.Tool{
+					{
+						Name:                "calculator",
+						Description:         "Performs basic arithmetic operations: supported operations are 'add' and 'multiply'",
+						WrappedToolFunction: wrappedCalc,
+						JSONSchema:          []byte(calculatorSchema),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	result, err := coordinator.Run(ctx, "triage", "Hi! I need to know what 987 * 123 is.")
+	if err != nil {
+		logger.Error(err, "coordinated run failed")
+		return
+	}
+
+	for _, h := range result.Handoffs {
+		logger.Info("handoff", "from", h.From, "to", h.To, "reason", h.Reason, "summary", h.Summary)
+	}
+
+	fmt.Println("Handoff chain:", strings.Join(result.Chain(), " -> "))
+	fmt.Println("Agent response:", result.FinalMessage().Content)
+}
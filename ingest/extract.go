@@ -0,0 +1,151 @@
+package ingest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Extractor extracts the plain text of a document.
+type Extractor func(data []byte) (string, error)
+
+// DefaultExtractors returns the extractors for Markdown, plain text, Go, HTML
+// and JSON documents, keyed by lower case file extension.
+func DefaultExtractors() map[string]Extractor {
+	return map[string]Extractor{
+		".md":       ExtractText,
+		".markdown": ExtractText,
+		".txt":      ExtractText,
+		".go":       ExtractText,
+		".html":     ExtractHTML,
+		".htm":      ExtractHTML,
+		".json":     ExtractJSON,
+	}
+}
+
+// extractorFor returns the extractor for a file path, if any.
+func extractorFor(extractors map[string]Extractor, path string) (Extractor, bool) {
+	extractor, ok := extractors[strings.ToLower(filepath.Ext(path))]
+	return extractor, ok
+}
+
+// ExtractText returns the document as is. Markdown is kept as is so that
+// heading aware chunkers can use its structure.
+func ExtractText(data []byte) (string, error) {
+	return string(data), nil
+}
+
+// ExtractHTML returns the text content of an HTML document, with block
+// elements on their own lines and scripts and styles dropped.
+func ExtractHTML(data []byte) (string, error) {
+	doc, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("error parsing html: %w", err)
+	}
+
+	var sb strings.Builder
+
+	// trailing newlines written so far, so that block breaks don't stack up
+	newlines := 2
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "script", "style", "noscript", "template", "head":
+				return
+			}
+		}
+
+		if n.Type == html.TextNode {
+			text := strings.Join(strings.Fields(n.Data), " ")
+			if text != "" {
+				if newlines == 0 {
+					sb.WriteString(" ")
+				}
+				sb.WriteString(text)
+				newlines = 0
+			}
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+
+		if n.Type == html.ElementNode && isBlockElement(n.Data) && newlines < 2 {
+			sb.WriteString(strings.Repeat("\n", 2-newlines))
+			newlines = 2
+		}
+	}
+	walk(doc)
+
+	return strings.TrimSpace(sb.String()), nil
+}
+
+func isBlockElement(tag string) bool {
+	switch tag {
+	case "p", "div", "section", "article", "header", "footer", "li", "ul", "ol",
+		"table", "tr", "br", "pre", "blockquote", "h1", "h2", "h3", "h4", "h5", "h6":
+		return true
+	default:
+		return false
+	}
+}
+
+// ExtractJSON returns the string, number and boolean values of a JSON
+// document, one per line, prefixed with their key path, i.e.,
+// "spells.0.name: Fire Bolt".
+func ExtractJSON(data []byte) (string, error) {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return "", fmt.Errorf("error parsing json: %w", err)
+	}
+
+	lines := []string{}
+
+	var walk func(path string, v any)
+	walk = func(path string, v any) {
+		switch val := v.(type) {
+		case map[string]any:
+			keys := make([]string, 0, len(val))
+			for k := range val {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			for _, k := range keys {
+				walk(joinPath(path, k), val[k])
+			}
+
+		case []any:
+			for i, item := range val {
+				walk(joinPath(path, fmt.Sprint(i)), item)
+			}
+
+		case nil:
+
+		default:
+			if path == "" {
+				lines = append(lines, fmt.Sprint(val))
+			} else {
+				lines = append(lines, fmt.Sprintf("%s: %v", path, val))
+			}
+		}
+	}
+	walk("", v)
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
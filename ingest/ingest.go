@@ -0,0 +1,399 @@
+// Package ingest walks a directory of documents, chunks their text, embeds
+// the chunks in batches and upserts them into a vector store. Chunks are
+// identified by their source path and index and carry a content hash, so
+// re-running an ingestion only embeds the chunks that changed.
+package ingest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	"github.com/agent-api/examples/chunking"
+	"github.com/agent-api/examples/vectorstorer"
+)
+
+// IngesterOpts configures a new Ingester.
+type IngesterOpts struct {
+	Store    vectorstorer.Store
+	Embedder vectorstorer.Embedder
+
+	// Namespace the chunks are stored in, i.e., a tenant
+	Namespace string
+
+	// Extractors by lower case file extension. Defaults to DefaultExtractors.
+	// Files with other extensions are skipped.
+	Extractors map[string]Extractor
+
+	// BatchSize is the number of chunks embedded per Embedder call. Defaults
+	// to 32.
+	BatchSize int
+
+	// Chunkers by lower case file extension. Defaults to DefaultChunkers.
+	// Files with other extensions are chunked by DefaultChunker.
+	Chunkers map[string]chunking.Chunker
+
+	// DefaultChunker defaults to a chunking.RecursiveChunker
+	DefaultChunker chunking.Chunker
+
+	Logger *logr.Logger
+}
+
+// Ingester ingests documents into a vector store.
+type Ingester struct {
+	store      vectorstorer.Store
+	embedder   vectorstorer.Embedder
+	namespace  string
+	extractors map[string]Extractor
+
+	chunkers       map[string]chunking.Chunker
+	defaultChunker chunking.Chunker
+
+	batchSize int
+
+	logger *logr.Logger
+}
+
+// Stats counts the work done by an ingestion.
+type Stats struct {
+	Files int
+
+	// Skipped files have no extractor for their extension
+	Skipped int
+
+	Chunks int
+
+	// Embedded chunks were new or changed, Unchanged chunks were already
+	// stored with the same content hash
+	Embedded  int
+	Unchanged int
+
+	// Deleted chunks were left over from a longer version of a document, or
+	// from a document removed from the ingested directory. They are only
+	// deleted from stores implementing vectorstorer.Manager.
+	Deleted int
+}
+
+// NewIngester creates a new Ingester.
+func NewIngester(opts *IngesterOpts) (*Ingester, error) {
+	if opts.Store == nil || opts.Embedder == nil {
+		return nil, errors.New("ingester requires a store and an embedder")
+	}
+
+	if opts.Extractors == nil {
+		opts.Extractors = DefaultExtractors()
+	}
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 32
+	}
+
+	if opts.Chunkers == nil {
+		opts.Chunkers = DefaultChunkers()
+	}
+
+	if opts.DefaultChunker == nil {
+		opts.DefaultChunker = &chunking.RecursiveChunker{}
+	}
+
+	return &Ingester{
+		store:          opts.Store,
+		embedder:       opts.Embedder,
+		namespace:      opts.Namespace,
+		extractors:     opts.Extractors,
+		chunkers:       opts.Chunkers,
+		defaultChunker: opts.DefaultChunker,
+		batchSize:      opts.BatchSize,
+		logger:         opts.Logger,
+	}, nil
+}
+
+// DefaultChunkers returns heading aware chunking for Markdown, declaration
+// aware chunking for Go and sentence boundary chunking for plain text, keyed
+// by lower case file extension.
+func DefaultChunkers() map[string]chunking.Chunker {
+	markdown := &chunking.MarkdownChunker{}
+	sentence := &chunking.SentenceChunker{Overlap: 1}
+
+	return map[string]chunking.Chunker{
+		".md":       markdown,
+		".markdown": markdown,
+		".txt":      sentence,
+		".go":       &chunking.GoChunker{},
+	}
+}
+
+// ChunkID returns the stable ID of the chunk at index of a source document
+// in a namespace. The namespace is separated by a NUL byte, which paths
+// cannot contain, so no two namespaces share IDs.
+func ChunkID(namespace, source string, index int) string {
+	key := namespace + "\x00" + source + "#" + strconv.Itoa(index)
+
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:16])
+}
+
+// IngestDir ingests every supported file under dir. Sources are recorded as
+// paths relative to dir, with forward slashes. The directory is taken to be
+// the whole namespace: chunks of sources no longer under dir are deleted
+// from stores implementing vectorstorer.Manager.
+func (i *Ingester) IngestDir(ctx context.Context, dir string) (*Stats, error) {
+	stats := &Stats{}
+	batch := &batch{}
+	seen := map[string]bool{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		source, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		source = filepath.ToSlash(source)
+		seen[source] = true
+
+		return i.ingestFile(ctx, path, source, stats, batch)
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	if err := i.flush(ctx, stats, batch); err != nil {
+		return stats, err
+	}
+
+	if m, ok := i.store.(vectorstorer.Manager); ok {
+		deleted, err := i.deleteMissing(ctx, m, seen)
+		if err != nil {
+			return stats, err
+		}
+
+		stats.Deleted += deleted
+	}
+
+	return stats, nil
+}
+
+// deleteMissing deletes the chunks of the namespace whose source was not
+// seen.
+func (i *Ingester) deleteMissing(ctx context.Context, m vectorstorer.Manager, seen map[string]bool) (int, error) {
+	missing := map[string]bool{}
+
+	params := &vectorstorer.ListParams{
+		Selector: vectorstorer.Selector{
+			Namespace: i.namespace,
+			Where:     []*vectorstorer.Predicate{vectorstorer.Exists(vectorstorer.MetadataSource)},
+		},
+	}
+
+	for {
+		page, err := m.List(ctx, params)
+		if err != nil {
+			return 0, fmt.Errorf("error listing stored chunks: %w", err)
+		}
+
+		for _, e := range page.Embeddings {
+			if source, ok := e.Metadata[vectorstorer.MetadataSource].(string); ok && !seen[source] {
+				missing[source] = true
+			}
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+
+		params.Cursor = page.NextCursor
+	}
+
+	if len(missing) == 0 {
+		return 0, nil
+	}
+
+	sources := make([]any, 0, len(missing))
+	for source := range missing {
+		sources = append(sources, source)
+	}
+
+	deleted, err := m.DeleteWhere(ctx, &vectorstorer.Selector{
+		Namespace: i.namespace,
+		Where:     []*vectorstorer.Predicate{vectorstorer.In(vectorstorer.MetadataSource, sources...)},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error deleting chunks of removed sources: %w", err)
+	}
+
+	if i.logger != nil {
+		i.logger.Info("deleted chunks of removed sources", "sources", len(missing), "chunks", deleted)
+	}
+
+	return deleted, nil
+}
+
+// IngestFile ingests a single file, recorded under the given source.
+func (i *Ingester) IngestFile(ctx context.Context, path, source string) (*Stats, error) {
+	stats := &Stats{}
+	batch := &batch{}
+
+	if err := i.ingestFile(ctx, path, source, stats, batch); err != nil {
+		return stats, err
+	}
+
+	if err := i.flush(ctx, stats, batch); err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
+
+// batch holds chunks waiting to be embedded.
+type batch struct {
+	chunks []*vectorstorer.Embedding
+}
+
+func (i *Ingester) ingestFile(ctx context.Context, path, source string, stats *Stats, b *batch) error {
+	extract, ok := extractorFor(i.extractors, path)
+	if !ok {
+		stats.Skipped++
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	text, err := extract(data)
+	if err != nil {
+		return fmt.Errorf("error extracting %s: %w", source, err)
+	}
+
+	stats.Files++
+
+	chunker, ok := i.chunkers[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		chunker = i.defaultChunker
+	}
+
+	chunks := chunker.Chunk(text)
+	stats.Chunks += len(chunks)
+
+	ids := make([]string, len(chunks))
+	for idx := range chunks {
+		ids[idx] = ChunkID(i.namespace, source, idx)
+	}
+
+	stored, err := i.store.Get(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("error getting stored chunks of %s: %w", source, err)
+	}
+
+	storedHashes := make(map[string]any, len(stored))
+	for _, e := range stored {
+		storedHashes[e.ID] = e.Metadata[vectorstorer.MetadataContentHash]
+	}
+
+	// a document that shrank leaves its chunks past the new end behind
+	if m, ok := i.store.(vectorstorer.Manager); ok {
+		deleted, err := m.DeleteWhere(ctx, &vectorstorer.Selector{
+			Namespace: i.namespace,
+			Where: []*vectorstorer.Predicate{
+				vectorstorer.Eq(vectorstorer.MetadataSource, source),
+				vectorstorer.Gte(vectorstorer.MetadataChunkIndex, len(chunks)),
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("error deleting stale chunks of %s: %w", source, err)
+		}
+
+		stats.Deleted += deleted
+	}
+
+	for idx, chunk := range chunks {
+		hash := vectorstorer.ContentHash(chunk.Content)
+		if storedHashes[ids[idx]] == hash {
+			stats.Unchanged++
+			continue
+		}
+
+		metadata := map[string]any{}
+		for k, v := range chunk.Metadata {
+			metadata[k] = v
+		}
+		metadata[vectorstorer.MetadataSource] = source
+		metadata[vectorstorer.MetadataChunkIndex] = idx
+		metadata[vectorstorer.MetadataContentHash] = hash
+
+		b.chunks = append(b.chunks, &vectorstorer.Embedding{
+			ID:        ids[idx],
+			Namespace: i.namespace,
+			Content:   chunk.Content,
+			Metadata:  metadata,
+		})
+
+		if len(b.chunks) >= i.batchSize {
+			if err := i.flush(ctx, stats, b); err != nil {
+				return err
+			}
+		}
+	}
+
+	if i.logger != nil {
+		i.logger.V(1).Info("ingested file", "source", source, "chunks", len(chunks))
+	}
+
+	return nil
+}
+
+// flush embeds and upserts the pending chunks of a batch.
+func (i *Ingester) flush(ctx context.Context, stats *Stats, b *batch) error {
+	if len(b.chunks) == 0 {
+		return nil
+	}
+
+	texts := make([]string, len(b.chunks))
+	for idx, c := range b.chunks {
+		texts[idx] = c.Content
+	}
+
+	vectors, err := i.embedder.Embed(ctx, texts)
+	if err != nil {
+		return fmt.Errorf("error embedding chunks: %w", err)
+	}
+
+	if len(vectors) != len(texts) {
+		return fmt.Errorf("embedder returned %d vectors for %d chunks", len(vectors), len(texts))
+	}
+
+	for idx, c := range b.chunks {
+		c.Vector = vectors[idx]
+	}
+
+	if err := i.store.Upsert(ctx, b.chunks); err != nil {
+		return fmt.Errorf("error upserting chunks: %w", err)
+	}
+
+	if i.logger != nil {
+		i.logger.Info("embedded chunks", "count", len(b.chunks))
+	}
+
+	stats.Embedded += len(b.chunks)
+	b.chunks = nil
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package ingest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/agent-api/examples/vectorstorer"
+)
+
+func TestChunkID(t *testing.T) {
+	tests := []struct {
+		name        string
+		a, b        [2]string
+		wantCollide bool
+	}{
+		{
+			name:        "same namespace and source",
+			a:           [2]string{"t1", "a.md"},
+			b:           [2]string{"t1", "a.md"},
+			wantCollide: true,
+		},
+		{
+			name: "source containing the namespace",
+			a:    [2]string{"", "t1:a.md"},
+			b:    [2]string{"t1", "a.md"},
+		},
+		{
+			name: "empty namespace",
+			a:    [2]string{"", "a.md"},
+			b:    [2]string{"t1", "a.md"},
+		},
+		{
+			name: "other tenant",
+			a:    [2]string{"t1", "a.md"},
+			b:    [2]string{"t2", "a.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := ChunkID(tt.a[0], tt.a[1], 0)
+			b := ChunkID(tt.b[0], tt.b[1], 0)
+
+			if (a == b) != tt.wantCollide {
+				t.Errorf("got IDs %s and %s", a, b)
+			}
+		})
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func storedSources(t *testing.T, store *vectorstorer.MemoryStore, namespace string) []string {
+	t.Helper()
+
+	page, err := store.List(context.Background(), &vectorstorer.ListParams{
+		Selector: vectorstorer.Selector{Namespace: namespace},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sources := []string{}
+	for _, e := range page.Embeddings {
+		source := e.Metadata[vectorstorer.MetadataSource].(string)
+		if !slices.Contains(sources, source) {
+			sources = append(sources, source)
+		}
+	}
+
+	slices.Sort(sources)
+
+	return sources
+}
+
+func TestIngestDirDeletesRemovedSources(t *testing.T) {
+	ctx := context.Background()
+	embedder := vectorstorer.NewHashEmbedder(&vectorstorer.HashEmbedderOpts{})
+
+	store, err := vectorstorer.NewMemoryStore(&vectorstorer.MemoryStoreOpts{Embedder: embedder})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newIngester := func(namespace string) *Ingester {
+		ingester, err := NewIngester(&IngesterOpts{
+			Store:     store,
+			Embedder:  embedder,
+			Namespace: namespace,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return ingester
+	}
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.md":        "# A\n\nThe first document.",
+		"docs/b.txt":  "The second document.",
+		"docs/c.md":   "# C\n\nThe third document.",
+		"ignored.bin": "not a document",
+	})
+
+	// another tenant's copy of the same files is left alone
+	otherDir := t.TempDir()
+	writeFiles(t, otherDir, map[string]string{
+		"a.md": "# A\n\nThe first document.",
+	})
+
+	if _, err := newIngester("other").IngestDir(ctx, otherDir); err != nil {
+		t.Fatal(err)
+	}
+
+	ingester := newIngester("docs")
+
+	stats, err := ingester.IngestDir(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.Files != 3 || stats.Skipped != 1 || stats.Deleted != 0 {
+		t.Errorf("got stats %+v", stats)
+	}
+
+	if got := storedSources(t, store, "docs"); !slices.Equal(got, []string{"a.md", "docs/b.txt", "docs/c.md"}) {
+		t.Fatalf("got sources %v", got)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "docs", "b.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "a.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err = ingester.IngestDir(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.Embedded != 0 || stats.Unchanged != 1 || stats.Deleted != 2 {
+		t.Errorf("got stats %+v", stats)
+	}
+
+	if got := storedSources(t, store, "docs"); !slices.Equal(got, []string{"docs/c.md"}) {
+		t.Errorf("got sources %v, want [docs/c.md]", got)
+	}
+
+	if got := storedSources(t, store, "other"); !slices.Equal(got, []string{"a.md"}) {
+		t.Errorf("got other namespace's sources %v, want [a.md]", got)
+	}
+}
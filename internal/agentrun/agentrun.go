@@ -0,0 +1,15 @@
+// Package agentrun defines the interface shared by the packages that run
+// agents.
+package agentrun
+
+import (
+	"context"
+
+	"github.com/agent-api/core/agent"
+)
+
+// Runner is anything that can execute an agent run. Both *agent.Agent and
+// agents that embed it (like *webscraper.WebScraperAgent) satisfy Runner.
+type Runner interface {
+	Run(ctx context.Context, opts ...agent.RunOptionFunc) (*agent.AgentRunAggregator, error)
+}
@@ -0,0 +1,19 @@
+// Package llmjson extracts JSON from model responses.
+package llmjson
+
+import "strings"
+
+// Extract returns the JSON value delimited by open and close in content,
+// i.e., '{' and '}' for an object or '[' and ']' for an array. Models often
+// wrap JSON in prose or code fences, so everything before the first open and
+// after the last close is dropped. It reports false when content has no such
+// value.
+func Extract(content string, open, close byte) (string, bool) {
+	start := strings.IndexByte(content, open)
+	end := strings.LastIndexByte(content, close)
+	if start == -1 || end < start {
+		return "", false
+	}
+
+	return content[start : end+1], true
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/core/agent/bootstrap"
+	"github.com/agent-api/examples/memory"
+	"github.com/agent-api/examples/vectorstorer"
+	"github.com/agent-api/ollama"
+	"github.com/agent-api/ollama/models"
+)
+
+func main() {
+	ctx := context.Background()
+
+	// Create a zap logger
+	config := zap.NewDevelopmentConfig()
+	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	zLogger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a logr.Logger using zapr adapter
+	logger := zapr.NewLogger(zLogger)
+
+	// Create an ollama provider
+	provider := ollama.NewProvider(&ollama.ProviderOpts{
+		Logger:  &logger,
+		BaseURL: "http://localhost",
+		Port:    11434,
+	})
+	provider.UseModel(ctx, models.QWEN2_5_LATEST)
+
+	embedder := vectorstorer.NewOllamaEmbedder(&vectorstorer.OllamaEmbedderOpts{
+		Logger: &logger,
+	})
+
+	// Memories are persisted to a file, so they outlive the process like
+	// they would in a pgvector table
+	store, err := vectorstorer.NewMemoryStore(&vectorstorer.MemoryStoreOpts{
+		Embedder: embedder,
+		Path:     "memories.json",
+		Logger:   &logger,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	longTerm, err := memory.NewLongTermMemory(&memory.LongTermMemoryOpts{
+		Store:    store,
+		Embedder: embedder,
+		Provider: provider,
+		Logger:   &logger,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	sessions := []struct {
+		session *memory.Session
+		input   string
+	}{
+		{
+			session: &memory.Session{UserID: "alice", SessionID: "monday"},
+			input:   "I'm vegetarian and I just moved to Lisbon. Can you recommend a good book about Portugal?",
+		},
+		{
+			session: &memory.Session{UserID: "alice", SessionID: "friday"},
+			input:   "Where should I go for dinner tonight?",
+		},
+	}
+
+	for _, s := range sessions {
+		// a new agent per session: nothing is carried over but the long
+		// term memories
+		myAgent, err := agent.NewAgent(
+			bootstrap.WithProvider(provider),
+			bootstrap.WithLogger(&logger),
+		)
+		if err != nil {
+			panic(err)
+		}
+
+		facts, err := longTerm.Recall(ctx, s.session.UserID, s.input)
+		if err != nil {
+			panic(err)
+		}
+
+		fmt.Printf("\n[%s] recalled %d memories\n", s.session.SessionID, len(facts))
+		for _, f := range facts {
+			fmt.Printf("  %.3f %s (from %s)\n", f.Score, f.Content, f.SessionID)
+		}
+
+		response, err := longTerm.Run(ctx, myAgent, s.session, s.input)
+		if err != nil {
+			logger.Error(err, "failed sending message to agent")
+			return
+		}
+
+		fmt.Println("Agent response:", response.Messages[len(response.Messages)-1].Content)
+	}
+}
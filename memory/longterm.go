@@ -0,0 +1,331 @@
+package memory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/examples/internal/agentrun"
+	"github.com/agent-api/examples/internal/llmjson"
+	"github.com/agent-api/examples/vectorstorer"
+)
+
+// Metadata keys of stored memories.
+const (
+	MetadataUserID       = "user_id"
+	MetadataSessionID    = "session_id"
+	MetadataRememberedAt = "remembered_at"
+)
+
+// Session identifies whose conversation memories come from and are recalled
+// for.
+type Session struct {
+	UserID    string
+	SessionID string
+}
+
+// Fact is a durable piece of knowledge about a user.
+type Fact struct {
+	ID        string
+	Content   string
+	UserID    string
+	SessionID string
+
+	// RememberedAt is when the fact was last extracted
+	RememberedAt time.Time
+
+	// Score is the fact's similarity to the query it was recalled for
+	Score float64
+}
+
+// LongTermMemoryOpts configures a new LongTermMemory.
+type LongTermMemoryOpts struct {
+	Store    vectorstorer.Store
+	Embedder vectorstorer.Embedder
+
+	// Provider extracts facts from conversations. It should already have a
+	// model set via UseModel.
+	Provider core.Provider
+
+	// Namespace the facts are stored in. Defaults to "memories".
+	Namespace string
+
+	// Limit is the number of facts recalled. Defaults to 5.
+	Limit int
+
+	// MinScore is the similarity below which facts are not recalled
+	MinScore float64
+
+	Logger *logr.Logger
+}
+
+// LongTermMemory extracts durable facts from conversations, stores them as
+// embeddings with their user and session, and recalls the ones relevant to
+// a user's later inputs.
+type LongTermMemory struct {
+	store     vectorstorer.Store
+	embedder  vectorstorer.Embedder
+	provider  core.Provider
+	namespace string
+	limit     int
+	minScore  float64
+
+	logger *logr.Logger
+}
+
+// NewLongTermMemory creates a new LongTermMemory.
+func NewLongTermMemory(opts *LongTermMemoryOpts) (*LongTermMemory, error) {
+	if opts.Store == nil || opts.Embedder == nil || opts.Provider == nil {
+		return nil, errors.New("long term memory requires a store, an embedder and a provider")
+	}
+
+	if opts.Namespace == "" {
+		opts.Namespace = "memories"
+	}
+
+	if opts.Limit <= 0 {
+		opts.Limit = 5
+	}
+
+	return &LongTermMemory{
+		store:     opts.Store,
+		embedder:  opts.Embedder,
+		provider:  opts.Provider,
+		namespace: opts.Namespace,
+		limit:     opts.Limit,
+		minScore:  opts.MinScore,
+		logger:    opts.Logger,
+	}, nil
+}
+
+const extractPrompt string = `Extract the durable facts about the user from the conversation below that are worth remembering in future conversations: their preferences, personal details, goals and decisions. Ignore one-off requests and anything that only matters to this conversation.
+
+Write each fact as a short standalone sentence about "the user", i.e., "The user is vegetarian.".
+
+Conversation:
+%s
+Respond ONLY with a JSON array of strings, or [] if there is nothing worth remembering.`
+
+// Extract asks the provider for the durable facts about the user in a
+// conversation. Only user and assistant text is considered.
+func (l *LongTermMemory) Extract(ctx context.Context, messages []*core.Message) ([]string, error) {
+	var conversation strings.Builder
+	for _, m := range messages {
+		if (m.Role == core.UserMessageRole || m.Role == core.AssistantMessageRole) && m.Content != "" {
+			fmt.Fprintf(&conversation, "%s: %s\n", m.Role, m.Content)
+		}
+	}
+
+	if conversation.Len() == 0 {
+		return []string{}, nil
+	}
+
+	resp, err := l.provider.Generate(ctx, &core.GenerateOptions{
+		Messages: []*core.Message{
+			{
+				Role:    core.UserMessageRole,
+				Content: fmt.Sprintf(extractPrompt, conversation.String()),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error extracting facts: %w", err)
+	}
+
+	data, ok := llmjson.Extract(resp.Content, '[', ']')
+	if !ok {
+		return nil, fmt.Errorf("extracted facts were not JSON: %s", resp.Content)
+	}
+
+	facts := []string{}
+	if err := json.Unmarshal([]byte(data), &facts); err != nil {
+		return nil, fmt.Errorf("error unmarshaling extracted facts: %w", err)
+	}
+
+	out := []string{}
+	for _, f := range facts {
+		if f = strings.TrimSpace(f); f != "" {
+			out = append(out, f)
+		}
+	}
+
+	return out, nil
+}
+
+// Save stores facts for the session's user. A fact is identified by its user
+// and text, so saving it again only updates its session and time.
+func (l *LongTermMemory) Save(ctx context.Context, session *Session, facts []string) ([]*Fact, error) {
+	if len(facts) == 0 {
+		return []*Fact{}, nil
+	}
+
+	now := time.Now()
+	embeddings := make([]*vectorstorer.Embedding, len(facts))
+	saved := make([]*Fact, len(facts))
+
+	for i, f := range facts {
+		id := factID(session.UserID, f)
+
+		embeddings[i] = &vectorstorer.Embedding{
+			ID:        id,
+			Namespace: l.namespace,
+			Content:   f,
+			Metadata: map[string]any{
+				MetadataUserID:       session.UserID,
+				MetadataSessionID:    session.SessionID,
+				MetadataRememberedAt: vectorstorer.FormatTimestamp(now),
+			},
+		}
+
+		saved[i] = &Fact{
+			ID:           id,
+			Content:      f,
+			UserID:       session.UserID,
+			SessionID:    session.SessionID,
+			RememberedAt: now,
+		}
+	}
+
+	if _, err := vectorstorer.UpsertByContentHash(ctx, l.store, l.embedder, embeddings); err != nil {
+		return nil, fmt.Errorf("error saving facts: %w", err)
+	}
+
+	return saved, nil
+}
+
+// Remember extracts the facts in a conversation and saves them for the
+// session's user.
+func (l *LongTermMemory) Remember(ctx context.Context, session *Session, messages []*core.Message) ([]*Fact, error) {
+	facts, err := l.Extract(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := l.Save(ctx, session, facts)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.logger != nil && len(saved) > 0 {
+		l.logger.V(1).Info("remembered facts", "user", session.UserID, "session", session.SessionID, "facts", len(saved))
+	}
+
+	return saved, nil
+}
+
+// Recall returns the user's facts most relevant to the query, across all of
+// their sessions.
+func (l *LongTermMemory) Recall(ctx context.Context, userID, query string) ([]*Fact, error) {
+	results, err := l.store.Search(ctx, &vectorstorer.SearchParams{
+		Query:     query,
+		Limit:     l.limit,
+		Namespace: l.namespace,
+		Where:     []*vectorstorer.Predicate{vectorstorer.Eq(MetadataUserID, userID)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error recalling facts: %w", err)
+	}
+
+	facts := []*Fact{}
+	for _, r := range results {
+		if r.Score < l.minScore {
+			continue
+		}
+
+		facts = append(facts, factOf(r.Embedding, r.Score))
+	}
+
+	return facts, nil
+}
+
+// Forget deletes every fact of a user. It requires a store implementing
+// vectorstorer.Manager.
+func (l *LongTermMemory) Forget(ctx context.Context, userID string) (int, error) {
+	m, ok := l.store.(vectorstorer.Manager)
+	if !ok {
+		return 0, errors.New("forgetting requires a store that can delete")
+	}
+
+	return m.DeleteWhere(ctx, &vectorstorer.Selector{
+		Namespace: l.namespace,
+		Where:     []*vectorstorer.Predicate{vectorstorer.Eq(MetadataUserID, userID)},
+	})
+}
+
+// Prompt prepends the facts to an input, for agents whose system prompt is
+// fixed. It returns the input unchanged when there are no facts.
+func Prompt(facts []*Fact, input string) string {
+	if len(facts) == 0 {
+		return input
+	}
+
+	var sb strings.Builder
+	sb.WriteString("What you remember about the user from earlier conversations:\n")
+	for _, f := range facts {
+		fmt.Fprintf(&sb, "- %s\n", f.Content)
+	}
+
+	fmt.Fprintf(&sb, "\n%s", input)
+
+	return sb.String()
+}
+
+// Run recalls the user's facts relevant to input, runs the agent with them
+// prepended to the input and then remembers the facts of the exchange: the
+// input and the agent's final message. Any opts are passed on to the run
+// after the input. Failing to remember is logged, not returned.
+func (l *LongTermMemory) Run(ctx context.Context, r agentrun.Runner, session *Session, input string, opts ...agent.RunOptionFunc) (*agent.AgentRunAggregator, error) {
+	facts, err := l.Recall(ctx, session.UserID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	runOpts := append([]agent.RunOptionFunc{agent.WithInput(Prompt(facts, input))}, opts...)
+	agg, err := r.Run(ctx, runOpts...)
+	if err != nil {
+		return agg, err
+	}
+
+	exchange := []*core.Message{{Role: core.UserMessageRole, Content: input}}
+	if final := agg.Pop(); final != nil {
+		exchange = append(exchange, final)
+	}
+
+	if _, err := l.Remember(ctx, session, exchange); err != nil && l.logger != nil {
+		l.logger.Error(err, "failed remembering facts", "user", session.UserID)
+	}
+
+	return agg, nil
+}
+
+// factID identifies a fact by its user and case insensitive text.
+func factID(userID, fact string) string {
+	hash := sha256.Sum256([]byte(userID + "\x00" + strings.ToLower(fact)))
+	return hex.EncodeToString(hash[:16])
+}
+
+func factOf(e *vectorstorer.Embedding, score float64) *Fact {
+	f := &Fact{
+		ID:      e.ID,
+		Content: e.Content,
+		Score:   score,
+	}
+
+	f.UserID, _ = e.Metadata[MetadataUserID].(string)
+	f.SessionID, _ = e.Metadata[MetadataSessionID].(string)
+
+	if t, ok := e.Metadata[MetadataRememberedAt].(string); ok {
+		f.RememberedAt, _ = time.Parse(vectorstorer.TimestampFormat, t)
+	}
+
+	return f
+}
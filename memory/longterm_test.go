@@ -0,0 +1,192 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/examples/vectorstorer"
+)
+
+// factsProvider answers extraction requests with a JSON array of facts.
+type factsProvider struct {
+	scriptedProvider
+}
+
+func (p *factsProvider) Generate(ctx context.Context, opts *core.GenerateOptions) (*core.Message, error) {
+	p.prompts = append(p.prompts, opts.Messages[len(opts.Messages)-1].Content)
+	return &core.Message{Role: core.AssistantMessageRole, Content: p.content}, nil
+}
+
+func newTestLongTermMemory(t *testing.T, facts string) (*LongTermMemory, *factsProvider) {
+	t.Helper()
+
+	embedder := vectorstorer.NewHashEmbedder(&vectorstorer.HashEmbedderOpts{})
+	store, err := vectorstorer.NewMemoryStore(&vectorstorer.MemoryStoreOpts{Embedder: embedder})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := &factsProvider{scriptedProvider{content: facts}}
+
+	l, err := NewLongTermMemory(&LongTermMemoryOpts{
+		Store:    store,
+		Embedder: embedder,
+		Provider: provider,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return l, provider
+}
+
+func factContents(facts []*Fact) []string {
+	out := []string{}
+	for _, f := range facts {
+		out = append(out, f.Content)
+	}
+
+	slices.Sort(out)
+
+	return out
+}
+
+func TestLongTermMemory(t *testing.T) {
+	ctx := context.Background()
+	l, provider := newTestLongTermMemory(t, "Here you go:\n[\"The user is vegetarian.\", \" \", \"The user lives in Lisbon.\"]")
+
+	alice := &Session{UserID: "alice", SessionID: "s1"}
+
+	saved, err := l.Remember(ctx, alice, []*core.Message{
+		user("I'm vegetarian and I live in Lisbon"),
+		{Role: core.ToolMessageRole, ToolResult: []*core.ToolResult{{ToolCallID: "call-1", Content: "secret"}}},
+		assistant("Noted!"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := factContents(saved); !slices.Equal(got, []string{"The user is vegetarian.", "The user lives in Lisbon."}) {
+		t.Errorf("got facts %q", got)
+	}
+
+	// only user and assistant text is sent for extraction
+	prompt := provider.prompts[0]
+	if !strings.Contains(prompt, "user: I'm vegetarian and I live in Lisbon\nassistant: Noted!\n") || strings.Contains(prompt, "secret") {
+		t.Errorf("got prompt %q", prompt)
+	}
+
+	// saving a fact again, in any case, updates it rather than duplicating it
+	resaved, err := l.Save(ctx, &Session{UserID: "alice", SessionID: "s2"}, []string{"the user is VEGETARIAN."})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resaved[0].ID != saved[0].ID {
+		t.Error("a fact saved again got a new ID")
+	}
+
+	if _, err := l.Save(ctx, &Session{UserID: "bob", SessionID: "s3"}, []string{"The user is vegan."}); err != nil {
+		t.Fatal(err)
+	}
+
+	recalled, err := l.Recall(ctx, "alice", "what should I cook for dinner?")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(recalled) != 2 {
+		t.Fatalf("recalled %q, want alice's 2 facts", factContents(recalled))
+	}
+
+	for _, f := range recalled {
+		if f.UserID != "alice" || f.RememberedAt.IsZero() {
+			t.Errorf("got fact %+v", f)
+		}
+
+		if f.ID == saved[0].ID && f.SessionID != "s2" {
+			t.Errorf("got session %s for the fact saved again, want s2", f.SessionID)
+		}
+	}
+
+	forgotten, err := l.Forget(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if forgotten != 2 {
+		t.Errorf("forgot %d facts, want 2", forgotten)
+	}
+
+	if recalled, _ := l.Recall(ctx, "bob", "dinner"); len(recalled) != 1 {
+		t.Errorf("recalled %q for bob after forgetting alice", factContents(recalled))
+	}
+}
+
+func TestLongTermMemoryExtractInvalid(t *testing.T) {
+	l, _ := newTestLongTermMemory(t, "Nothing to remember.")
+
+	if _, err := l.Extract(context.Background(), []*core.Message{user("hi")}); err == nil {
+		t.Error("expected an error for a response without JSON")
+	}
+
+	// a conversation without text needs no request
+	facts, err := l.Extract(context.Background(), []*core.Message{assistant("")})
+	if err != nil || len(facts) != 0 {
+		t.Errorf("got %q, %v", facts, err)
+	}
+}
+
+// inputRunner answers every run with its answer and records the input.
+type inputRunner struct {
+	answer string
+	input  string
+}
+
+func (r *inputRunner) Run(ctx context.Context, opts ...agent.RunOptionFunc) (*agent.AgentRunAggregator, error) {
+	runOpts := &agent.RunOptions{}
+	for _, opt := range opts {
+		opt(runOpts)
+	}
+
+	r.input = runOpts.Input
+
+	agg := agent.NewAgentRunAggregator()
+	agg.Messages = append(agg.Messages, assistant(r.answer))
+
+	return agg, nil
+}
+
+func TestLongTermMemoryRun(t *testing.T) {
+	ctx := context.Background()
+	l, provider := newTestLongTermMemory(t, `["The user is vegetarian."]`)
+	session := &Session{UserID: "alice", SessionID: "s1"}
+
+	runner := &inputRunner{answer: "Noted!"}
+
+	// nothing is remembered yet, so the input is passed as is
+	if _, err := l.Run(ctx, runner, session, "I'm vegetarian"); err != nil {
+		t.Fatal(err)
+	}
+
+	if runner.input != "I'm vegetarian" {
+		t.Errorf("got input %q", runner.input)
+	}
+
+	if !strings.Contains(provider.prompts[0], "user: I'm vegetarian\nassistant: Noted!\n") {
+		t.Errorf("the exchange was not remembered: %q", provider.prompts[0])
+	}
+
+	if _, err := l.Run(ctx, runner, session, "What should I cook?"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "What you remember about the user from earlier conversations:\n- The user is vegetarian.\n\nWhat should I cook?"
+	if runner.input != want {
+		t.Errorf("got input %q, want %q", runner.input, want)
+	}
+}
@@ -0,0 +1,347 @@
+// Package memory provides agent memory backends that keep long
+// conversations within a model's context window.
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/examples/modelinfo"
+)
+
+// TokenCounter counts the tokens of a message.
+type TokenCounter func(m *core.Message) int
+
+// MessageTokens estimates the tokens of a message for a model whose
+// tokenizer is unknown. Use the Info.MessageTokens of the model from
+// modelinfo for a closer estimate.
+func MessageTokens(m *core.Message) int {
+	return (&modelinfo.Info{}).MessageTokens(m)
+}
+
+// SummarizingMemoryOpts configures a new SummarizingMemory.
+type SummarizingMemoryOpts struct {
+	// Provider generates the summaries. It should already have a model set
+	// via UseModel.
+	Provider core.Provider
+
+	// SystemPrompt is sent first in every request and is never summarized
+	SystemPrompt string
+
+	// MaxTokens is the threshold, across the system prompt, summary and
+	// messages, above which older messages are summarized. Defaults to 4000.
+	MaxTokens int
+
+	// KeepTokens is roughly the number of tokens of recent messages kept
+	// verbatim when summarizing. Defaults to half of MaxTokens.
+	KeepTokens int
+
+	// Counter defaults to MessageTokens
+	Counter TokenCounter
+
+	// Timeout bounds a summarization triggered by Add. Defaults to 1 minute.
+	Timeout time.Duration
+
+	Logger *logr.Logger
+}
+
+// SummarizingMemory is a core.MemoryBackend that folds older turns into a
+// running summary once the conversation crosses a token threshold.
+//
+// The summary is appended to the system prompt, which is always the first
+// message. Older messages are summarized in whole turns, each starting with
+// a user message, so an assistant's tool calls are never separated from
+// their results.
+type SummarizingMemory struct {
+	provider     core.Provider
+	systemPrompt string
+	maxTokens    int
+	keepTokens   int
+	count        TokenCounter
+	timeout      time.Duration
+
+	mu       sync.Mutex
+	summary  string
+	messages []*core.Message
+
+	// summarized counts the messages folded into the summary
+	summarized int
+
+	logger *logr.Logger
+}
+
+// NewSummarizingMemory creates a new SummarizingMemory.
+func NewSummarizingMemory(opts *SummarizingMemoryOpts) (*SummarizingMemory, error) {
+	if opts.Provider == nil {
+		return nil, errors.New("summarizing memory requires a provider")
+	}
+
+	if opts.MaxTokens <= 0 {
+		opts.MaxTokens = 4000
+	}
+
+	if opts.KeepTokens <= 0 || opts.KeepTokens >= opts.MaxTokens {
+		opts.KeepTokens = opts.MaxTokens / 2
+	}
+
+	if opts.Counter == nil {
+		opts.Counter = MessageTokens
+	}
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = time.Minute
+	}
+
+	return &SummarizingMemory{
+		provider:     opts.Provider,
+		systemPrompt: opts.SystemPrompt,
+		maxTokens:    opts.MaxTokens,
+		keepTokens:   opts.KeepTokens,
+		count:        opts.Counter,
+		timeout:      opts.Timeout,
+		messages:     []*core.Message{},
+		logger:       opts.Logger,
+	}, nil
+}
+
+// Add adds messages and summarizes older turns if the conversation crossed
+// the threshold. A failed summarization is logged, not returned, and retried
+// on the next Add: the conversation is kept in full meanwhile.
+func (s *SummarizingMemory) Add(m ...*core.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = append(s.messages, m...)
+
+	if s.tokensLocked() <= s.maxTokens {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	if err := s.summarizeLocked(ctx, s.keepTokens); err != nil && s.logger != nil {
+		s.logger.Error(err, "failed summarizing conversation")
+	}
+
+	return nil
+}
+
+// GetMaxN returns the system message, with the summary, followed by the last
+// n messages. The window is extended back to the start of its first turn so
+// that it never begins with a tool result. n <= 0 returns every message.
+func (s *SummarizingMemory) GetMaxN(n int) ([]*core.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := 0
+	if n > 0 && n < len(s.messages) {
+		start = len(s.messages) - n
+		for start > 0 && s.messages[start].Role == core.ToolMessageRole {
+			start--
+		}
+	}
+
+	return s.contextLocked(s.messages[start:]), nil
+}
+
+// Dump returns the system message, with the summary, followed by every
+// message not yet summarized.
+func (s *SummarizingMemory) Dump() ([]*core.Message, error) {
+	return s.GetMaxN(0)
+}
+
+// Prune clears the summary and messages. The system prompt is kept.
+func (s *SummarizingMemory) Prune() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.summary = ""
+	s.messages = []*core.Message{}
+	s.summarized = 0
+}
+
+// Summary returns the running summary, empty until the first summarization.
+func (s *SummarizingMemory) Summary() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.summary
+}
+
+// Summarized returns the number of messages folded into the summary.
+func (s *SummarizingMemory) Summarized() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.summarized
+}
+
+// Tokens returns the counted tokens of the system message, with the
+// summary, and every message not yet summarized.
+func (s *SummarizingMemory) Tokens() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tokensLocked()
+}
+
+// Summarize folds every turn but the latest into the summary, regardless of
+// the threshold.
+func (s *SummarizingMemory) Summarize(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.summarizeLocked(ctx, 0)
+}
+
+func (s *SummarizingMemory) tokensLocked() int {
+	tokens := 0
+	if system := s.systemMessageLocked(); system != nil {
+		tokens += s.count(system)
+	}
+
+	for _, m := range s.messages {
+		tokens += s.count(m)
+	}
+
+	return tokens
+}
+
+func (s *SummarizingMemory) systemMessageLocked() *core.Message {
+	content := s.systemPrompt
+	if s.summary != "" {
+		content = strings.TrimSpace(content + "\n\nSummary of the conversation so far:\n" + s.summary)
+	}
+
+	if content == "" {
+		return nil
+	}
+
+	return &core.Message{
+		Role:    core.SystemMessageRole,
+		Content: content,
+	}
+}
+
+func (s *SummarizingMemory) contextLocked(messages []*core.Message) []*core.Message {
+	out := make([]*core.Message, 0, len(messages)+1)
+	if system := s.systemMessageLocked(); system != nil {
+		out = append(out, system)
+	}
+
+	return append(out, messages...)
+}
+
+// summarizeLocked folds the oldest turns into the summary, keeping about
+// keep tokens of the latest turns, and at least the latest turn, verbatim.
+func (s *SummarizingMemory) summarizeLocked(ctx context.Context, keep int) error {
+	cut := s.cutLocked(keep)
+	if cut == 0 {
+		return nil
+	}
+
+	older := s.messages[:cut]
+
+	resp, err := s.provider.Generate(ctx, &core.GenerateOptions{
+		Messages: []*core.Message{
+			{
+				Role:    core.UserMessageRole,
+				Content: fmt.Sprintf(summarizePrompt, s.summaryOrNone(), transcript(older)),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error generating summary: %w", err)
+	}
+
+	before := s.tokensLocked()
+
+	s.summary = strings.TrimSpace(resp.Content)
+	s.messages = append([]*core.Message{}, s.messages[cut:]...)
+	s.summarized += cut
+
+	if s.logger != nil {
+		s.logger.V(1).Info("summarized conversation",
+			"messages", cut,
+			"tokensBefore", before,
+			"tokensAfter", s.tokensLocked(),
+		)
+	}
+
+	return nil
+}
+
+// cutLocked returns the index of the first message kept verbatim: the start
+// of the oldest turn within keep tokens of the end, or of the latest turn.
+// It is 0 when there is no complete turn to summarize.
+func (s *SummarizingMemory) cutLocked(keep int) int {
+	kept := 0
+	cut := len(s.messages)
+
+	for i := len(s.messages) - 1; i >= 0; i-- {
+		kept += s.count(s.messages[i])
+		if kept > keep && cut < len(s.messages) {
+			break
+		}
+
+		if s.messages[i].Role == core.UserMessageRole {
+			cut = i
+		}
+	}
+
+	if cut == len(s.messages) {
+		return 0
+	}
+
+	return cut
+}
+
+func (s *SummarizingMemory) summaryOrNone() string {
+	if s.summary == "" {
+		return "(none)"
+	}
+
+	return s.summary
+}
+
+const summarizePrompt string = `You maintain the running summary of a conversation between a user and an assistant, which replaces the older messages in the assistant's context.
+
+Current summary:
+%s
+
+Messages to add to the summary:
+%s
+Write the updated summary. Keep every fact, preference, decision, tool result and open task that later turns may need, and drop small talk. Respond with the summary only.`
+
+// transcript renders messages as plain text, including tool calls and their
+// results.
+func transcript(messages []*core.Message) string {
+	var sb strings.Builder
+
+	for _, m := range messages {
+		switch m.Role {
+		case core.ToolMessageRole:
+			for _, tr := range m.ToolResult {
+				fmt.Fprintf(&sb, "tool result (%s): %s\n", tr.ToolCallID, modelinfo.ToolResultText(tr))
+			}
+		default:
+			if m.Content != "" {
+				fmt.Fprintf(&sb, "%s: %s\n", m.Role, m.Content)
+			}
+
+			for _, tc := range m.ToolCalls {
+				fmt.Fprintf(&sb, "%s called tool %s (%s): %s\n", m.Role, tc.Name, tc.ID, tc.Arguments)
+			}
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/core/agent/bootstrap"
+	"github.com/agent-api/examples/memory"
+	"github.com/agent-api/ollama"
+	"github.com/agent-api/ollama/models"
+)
+
+func main() {
+	ctx := context.Background()
+
+	// Create a zap logger
+	config := zap.NewDevelopmentConfig()
+	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	zLogger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a logr.Logger using zapr adapter
+	logger := zapr.NewLogger(zLogger)
+
+	// Create an ollama provider
+	provider := ollama.NewProvider(&ollama.ProviderOpts{
+		Logger:  &logger,
+		BaseURL: "http://localhost",
+		Port:    11434,
+	})
+	provider.UseModel(ctx, models.QWEN2_5_LATEST)
+
+	// A low threshold so summaries happen after a few turns. The system
+	// prompt is kept out of the summary and always sent first.
+	mem, err := memory.NewSummarizingMemory(&memory.SummarizingMemoryOpts{
+		Provider:     provider,
+		SystemPrompt: "You are a helpful assistant planning a trip with the user. Keep answers short.",
+		MaxTokens:    600,
+		Logger:       &logger,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a new agent
+	myAgent, err := agent.NewAgent(
+		bootstrap.WithProvider(provider),
+		bootstrap.WithLogger(&logger),
+		bootstrap.WithMemory(mem),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("Chat with the agent, Ctrl-D to quit.")
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print("> ")
+		if !scanner.Scan() {
+			break
+		}
+
+		input := scanner.Text()
+		if input == "" {
+			continue
+		}
+
+		summarized := mem.Summarized()
+
+		response, err := myAgent.Run(
+			ctx,
+			agent.WithInput(input),
+		)
+		if err != nil {
+			logger.Error(err, "failed sending message to agent")
+			continue
+		}
+
+		fmt.Println(response.Messages[len(response.Messages)-1].Content)
+
+		if mem.Summarized() > summarized {
+			fmt.Printf("\n[summarized %d older messages, context is now ~%d tokens]\n%s\n\n",
+				mem.Summarized()-summarized, mem.Tokens(), mem.Summary())
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/agent-api/core"
+)
+
+// scriptedProvider answers every request with its content, or fails with
+// err, and records the prompts.
+type scriptedProvider struct {
+	content string
+	err     error
+	prompts []string
+}
+
+func (p *scriptedProvider) GetCapabilities(ctx context.Context) (*core.Capabilities, error) {
+	return &core.Capabilities{}, nil
+}
+
+func (p *scriptedProvider) UseModel(ctx context.Context, model *core.Model) error {
+	return nil
+}
+
+func (p *scriptedProvider) Generate(ctx context.Context, opts *core.GenerateOptions) (*core.Message, error) {
+	p.prompts = append(p.prompts, opts.Messages[len(opts.Messages)-1].Content)
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return &core.Message{
+		Role:    core.AssistantMessageRole,
+		Content: fmt.Sprintf("%s %d", p.content, len(p.prompts)),
+	}, nil
+}
+
+func (p *scriptedProvider) GenerateStream(ctx context.Context, opts *core.GenerateOptions) (<-chan *core.Message, <-chan string, <-chan error) {
+	return nil, nil, nil
+}
+
+// tenTokens counts every message as 10 tokens.
+func tenTokens(m *core.Message) int {
+	return 10
+}
+
+func user(content string) *core.Message {
+	return &core.Message{Role: core.UserMessageRole, Content: content}
+}
+
+func assistant(content string) *core.Message {
+	return &core.Message{Role: core.AssistantMessageRole, Content: content}
+}
+
+func contents(messages []*core.Message) []string {
+	out := []string{}
+	for _, m := range messages {
+		out = append(out, m.Content)
+	}
+
+	return out
+}
+
+func newTestMemory(t *testing.T, provider *scriptedProvider) *SummarizingMemory {
+	t.Helper()
+
+	s, err := NewSummarizingMemory(&SummarizingMemoryOpts{
+		Provider:     provider,
+		SystemPrompt: "You are helpful.",
+		MaxTokens:    50,
+		KeepTokens:   20,
+		Counter:      tenTokens,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func TestSummarizingMemoryAdd(t *testing.T) {
+	provider := &scriptedProvider{content: "summary"}
+	s := newTestMemory(t, provider)
+
+	// the system prompt and 4 messages are exactly at the threshold
+	if err := s.Add(user("u1"), assistant("a1"), user("u2"), assistant("a2")); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(provider.prompts) != 0 || s.Tokens() != 50 {
+		t.Fatalf("summarized at %d tokens", s.Tokens())
+	}
+
+	// crossing it folds the turns beyond the 20 kept tokens into the summary
+	if err := s.Add(user("u3")); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Summary() != "summary 1" || s.Summarized() != 4 {
+		t.Errorf("got summary %q of %d messages", s.Summary(), s.Summarized())
+	}
+
+	if prompt := provider.prompts[0]; !strings.Contains(prompt, "user: u1\nassistant: a1\nuser: u2\nassistant: a2\n") || !strings.Contains(prompt, "(none)") {
+		t.Errorf("got prompt %q", prompt)
+	}
+
+	dump, err := s.Dump()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dump) != 2 || dump[0].Role != core.SystemMessageRole || dump[1].Content != "u3" {
+		t.Fatalf("got context %q", contents(dump))
+	}
+
+	if !strings.HasPrefix(dump[0].Content, "You are helpful.") || !strings.HasSuffix(dump[0].Content, "summary 1") {
+		t.Errorf("got system message %q", dump[0].Content)
+	}
+
+	// the next summary builds on the current one
+	if err := s.Summarize(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(provider.prompts) != 1 {
+		t.Error("summarized the latest turn")
+	}
+
+	s.Add(assistant("a3"), user("u4"))
+	if err := s.Summarize(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(provider.prompts[1], "summary 1") || s.Summarized() != 6 {
+		t.Errorf("got prompt %q after summarizing %d messages", provider.prompts[1], s.Summarized())
+	}
+
+	s.Prune()
+	if dump, _ := s.Dump(); len(dump) != 1 || dump[0].Content != "You are helpful." || s.Summary() != "" {
+		t.Errorf("got context %q after pruning", contents(dump))
+	}
+}
+
+func TestSummarizingMemoryFailedSummary(t *testing.T) {
+	provider := &scriptedProvider{err: errors.New("unavailable")}
+	s := newTestMemory(t, provider)
+
+	for _, m := range []*core.Message{user("u1"), assistant("a1"), user("u2"), assistant("a2"), user("u3")} {
+		if err := s.Add(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the conversation is kept in full and summarizing is retried
+	s.Add(assistant("a3"))
+
+	dump, _ := s.Dump()
+	if len(dump) != 7 || s.Summary() != "" || len(provider.prompts) != 2 {
+		t.Errorf("got context %q after %d attempts", contents(dump), len(provider.prompts))
+	}
+}
+
+func TestSummarizingMemoryToolTurns(t *testing.T) {
+	provider := &scriptedProvider{content: "summary"}
+	s := newTestMemory(t, provider)
+	s.maxTokens = 1000
+
+	s.Add(
+		user("what is 2 + 3?"),
+		&core.Message{
+			Role:      core.AssistantMessageRole,
+			ToolCalls: []*core.ToolCall{{ID: "call-1", Name: "calculator", Arguments: []byte(`{"a":2,"b":3}`)}},
+		},
+		&core.Message{
+			Role:       core.ToolMessageRole,
+			ToolResult: []*core.ToolResult{{ToolCallID: "call-1", Content: 5}},
+		},
+		assistant("5"),
+		user("thanks"),
+	)
+
+	// a window starting at a tool result is extended back to its tool call
+	window, err := s.GetMaxN(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roles := []core.MessageRole{}
+	for _, m := range window {
+		roles = append(roles, m.Role)
+	}
+
+	want := []core.MessageRole{core.SystemMessageRole, core.AssistantMessageRole, core.ToolMessageRole, core.AssistantMessageRole, core.UserMessageRole}
+	if !slices.Equal(roles, want) {
+		t.Errorf("got roles %v, want %v", roles, want)
+	}
+
+	// tool calls and their results are summarized with their turn
+	if err := s.Summarize(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Summarized() != 4 {
+		t.Errorf("summarized %d messages, want 4", s.Summarized())
+	}
+
+	prompt := provider.prompts[0]
+	if !strings.Contains(prompt, `assistant called tool calculator (call-1): {"a":2,"b":3}`) || !strings.Contains(prompt, "tool result (call-1): 5") {
+		t.Errorf("got prompt %q", prompt)
+	}
+}
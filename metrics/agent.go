@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/agent-api/core/agent"
+)
+
+// Agent wraps an *agent.Agent so that its runs are counted as active while
+// they are in progress.
+type Agent struct {
+	*agent.Agent
+
+	name    string
+	metrics *Metrics
+}
+
+// NewAgent creates a new instrumented Agent. The name is the "agent" label of
+// the active runs gauge.
+func (m *Metrics) NewAgent(a *agent.Agent, name string) *Agent {
+	return &Agent{
+		Agent:   a,
+		name:    name,
+		metrics: m,
+	}
+}
+
+// Run runs the agent as an active run.
+func (a *Agent) Run(ctx context.Context, opts ...agent.RunOptionFunc) (*agent.AgentRunAggregator, error) {
+	done := a.metrics.TrackRun(a.name)
+	defer done()
+
+	return a.Agent.Run(ctx, opts...)
+}
+
+// RunStream runs the agent as an active run until the run's channels are all
+// closed.
+func (a *Agent) RunStream(ctx context.Context, opts ...agent.RunOptionFunc) *agent.StreamRunnerResults {
+	done := a.metrics.TrackRun(a.name)
+
+	results := a.Agent.RunStream(ctx, opts...)
+
+	// buffered, non-blocking channels
+	outAggChan := make(chan agent.AgentRunAggregator, 10)
+	outDeltaChan := make(chan string, 10)
+	outErrChan := make(chan error, 10)
+
+	go func() {
+		defer done()
+		defer close(outAggChan)
+		defer close(outDeltaChan)
+		defer close(outErrChan)
+
+		aggChan, deltaChan, errChan := results.AggChan, results.DeltaChan, results.ErrChan
+
+		for aggChan != nil || deltaChan != nil || errChan != nil {
+			// forward like the agent does: skip if no one is listening
+			select {
+			case agg, ok := <-aggChan:
+				if !ok {
+					aggChan = nil
+					continue
+				}
+
+				select {
+				case outAggChan <- agg:
+				default:
+				}
+
+			case delta, ok := <-deltaChan:
+				if !ok {
+					deltaChan = nil
+					continue
+				}
+
+				select {
+				case outDeltaChan <- delta:
+				default:
+				}
+
+			case err, ok := <-errChan:
+				if !ok {
+					errChan = nil
+					continue
+				}
+
+				select {
+				case outErrChan <- err:
+				default:
+				}
+			}
+		}
+	}()
+
+	return &agent.StreamRunnerResults{
+		AggChan:   outAggChan,
+		DeltaChan: outDeltaChan,
+		ErrChan:   outErrChan,
+	}
+}
@@ -0,0 +1,172 @@
+// Package metrics exposes Prometheus metrics for provider requests, token
+// usage, tool invocations and agent runs, along with an HTTP handler serving
+// them.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DefaultNamespace prefixes every metric name unless MetricsOpts.Namespace
+// is set.
+const DefaultNamespace string = "agent_api"
+
+// Label values for the status of a provider request and the type of tokens.
+const (
+	StatusSuccess string = "success"
+	StatusError   string = "error"
+
+	TokenTypePrompt     string = "prompt"
+	TokenTypeCompletion string = "completion"
+)
+
+// MetricsOpts configures a new Metrics.
+type MetricsOpts struct {
+	// Namespace prefixes every metric name. Defaults to DefaultNamespace.
+	Namespace string
+
+	// Registry the metrics are registered with. Defaults to a new registry
+	// that also includes the Go runtime and process collectors.
+	Registry *prometheus.Registry
+
+	// LatencyBuckets are the histogram buckets, in seconds, for provider
+	// request latency and time to first token. Defaults to buckets from
+	// 100ms to 2 minutes.
+	LatencyBuckets []float64
+}
+
+// Metrics holds the collectors recorded by the wrapped providers, tools and
+// agents of this package.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	providerRequests *prometheus.CounterVec
+	providerLatency  *prometheus.HistogramVec
+	tokens           *prometheus.CounterVec
+	timeToFirstToken *prometheus.HistogramVec
+	toolInvocations  *prometheus.CounterVec
+	toolErrors       *prometheus.CounterVec
+	toolLatency      *prometheus.HistogramVec
+	activeRuns       *prometheus.GaugeVec
+}
+
+// NewMetrics creates the collectors and registers them.
+func NewMetrics(opts *MetricsOpts) (*Metrics, error) {
+	if opts.Namespace == "" {
+		opts.Namespace = DefaultNamespace
+	}
+
+	if opts.Registry == nil {
+		opts.Registry = prometheus.NewRegistry()
+		opts.Registry.MustRegister(
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		)
+	}
+
+	if len(opts.LatencyBuckets) == 0 {
+		opts.LatencyBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20, 30, 60, 120}
+	}
+
+	m := &Metrics{
+		registry: opts.Registry,
+
+		providerRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: opts.Namespace,
+			Name:      "provider_requests_total",
+			Help:      "Total number of provider generate requests.",
+		}, []string{"provider", "model", "status"}),
+
+		providerLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: opts.Namespace,
+			Name:      "provider_request_duration_seconds",
+			Help:      "Duration of provider generate requests, through the end of the stream for streaming requests.",
+			Buckets:   opts.LatencyBuckets,
+		}, []string{"provider", "model"}),
+
+		tokens: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: opts.Namespace,
+			Name:      "tokens_total",
+			Help:      "Total number of tokens used, estimated when not reported by the provider.",
+		}, []string{"provider", "model", "type"}),
+
+		timeToFirstToken: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: opts.Namespace,
+			Name:      "stream_time_to_first_token_seconds",
+			Help:      "Time from a streaming request until its first delta.",
+			Buckets:   opts.LatencyBuckets,
+		}, []string{"provider", "model"}),
+
+		toolInvocations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: opts.Namespace,
+			Name:      "tool_invocations_total",
+			Help:      "Total number of tool invocations.",
+		}, []string{"tool"}),
+
+		toolErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: opts.Namespace,
+			Name:      "tool_errors_total",
+			Help:      "Total number of tool invocations that returned an error.",
+		}, []string{"tool"}),
+
+		toolLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: opts.Namespace,
+			Name:      "tool_duration_seconds",
+			Help:      "Duration of tool invocations.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"tool"}),
+
+		activeRuns: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: opts.Namespace,
+			Name:      "active_runs",
+			Help:      "Number of agent runs in progress.",
+		}, []string{"agent"}),
+	}
+
+	for _, c := range []prometheus.Collector{
+		m.providerRequests,
+		m.providerLatency,
+		m.tokens,
+		m.timeToFirstToken,
+		m.toolInvocations,
+		m.toolErrors,
+		m.toolLatency,
+		m.activeRuns,
+	} {
+		if err := m.registry.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// Registry returns the registry the metrics are registered with, i.e., for
+// registering application specific collectors alongside them.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler returns an http.Handler serving the metrics in the Prometheus
+// exposition format. Mount it at "/metrics":
+//
+//	http.Handle("/metrics", m.Handler())
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		Registry: m.registry,
+	})
+}
+
+// TrackRun increments the active runs of an agent and returns a function that
+// decrements them again. It is used by Agent and may be called directly for
+// runs that are not wrapped.
+func (m *Metrics) TrackRun(agentName string) func() {
+	gauge := m.activeRuns.WithLabelValues(agentName)
+	gauge.Inc()
+
+	return gauge.Dec
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/core/agent/bootstrap"
+	"github.com/agent-api/examples/metrics"
+	"github.com/agent-api/ollama"
+	"github.com/agent-api/ollama/models"
+)
+
+const jsonSchema string = `{
+  "title": "calculator",
+  "description": "A simple calculator on ints",
+  "type": "object",
+  "properties": {
+    "a": {
+      "description": "The first operand",
+      "type": "number"
+    },
+    "b": {
+      "description": "The second operand",
+      "type": "number"
+    },
+    "operation": {
+      "description": "The operation to perform. One of [add, multiply]",
+      "type": "string"
+    }
+  },
+  "required": [
+    "operation",
+    "a",
+    "b"
+  ]
+}`
+
+type calculatorParams struct {
+	Operation string `json:"operation"`
+	A         int    `json:"a"`
+	B         int    `json:"b"`
+}
+
+func calculator(ctx context.Context, args *calculatorParams) (interface{}, error) {
+	switch args.Operation {
+	case "add":
+		return args.A + args.B, nil
+	case "multiply":
+		return args.A * args.B, nil
+	default:
+		return nil, fmt.Errorf("unsupported operation: %s", args.Operation)
+	}
+}
+
+func main() {
+	ctx := context.Background()
+
+	// Create a zap logger
+	config := zap.NewDevelopmentConfig()
+	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	zLogger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a logr.Logger using zapr adapter
+	logger := zapr.NewLogger(zLogger)
+
+	m, err := metrics.NewMetrics(&metrics.MetricsOpts{})
+	if err != nil {
+		panic(err)
+	}
+
+	// Create an instrumented ollama provider
+	provider := metrics.NewProvider(&metrics.ProviderOpts{
+		Provider: ollama.NewProvider(&ollama.ProviderOpts{
+			Logger:  &logger,
+			BaseURL: "http://localhost",
+			Port:    11434,
+		}),
+		Name:    "ollama",
+		Metrics: m,
+	})
+	provider.UseModel(ctx, models.QWEN2_5_LATEST)
+
+	wrappedCalc, err := core.WrapToolFunction(calculator)
+	if err != nil {
+		panic(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+
+	// Ask the agent with: curl 'localhost:8080/ask?q=What+is+987+*+123%3F'
+	mux.HandleFunc("/ask", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		if q == "" {
+			http.Error(w, "missing q parameter", http.StatusBadRequest)
+			return
+		}
+
+		// Agents are not safe for concurrent runs and remember their
+		// messages: create a new one for every request
+		baseAgent, err := agent.NewAgent(
+			bootstrap.WithProvider(provider),
+			bootstrap.WithLogger(&logger),
+			bootstrap.WithTools(m.WrapTools(&core.Tool{
+				Name:                "calculator",
+				Description:         "Performs basic arithmetic operations: supported operations are 'add' and 'multiply'",
+				WrappedToolFunction: wrappedCalc,
+				JSONSchema:          []byte(jsonSchema),
+			})...),
+		)
+		if err != nil {
+			logger.Error(err, "failed creating agent")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		myAgent := m.NewAgent(baseAgent, "calculator_agent")
+
+		response, err := myAgent.Run(r.Context(), agent.WithInput(q))
+		if err != nil {
+			logger.Error(err, "failed sending message to agent")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"response": response.Messages[len(response.Messages)-1].Content,
+		})
+	})
+
+	logger.Info("serving metrics at http://localhost:8080/metrics")
+	if err := http.ListenAndServe(":8080", mux); err != nil {
+		logger.Error(err, "server stopped")
+	}
+}
@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/examples/usage"
+)
+
+// ProviderOpts configures a new instrumented Provider.
+type ProviderOpts struct {
+	// The wrapped core.Provider
+	Provider core.Provider
+
+	// Name is the "provider" label, i.e., "openai"
+	Name string
+
+	// Model is the "model" label. It only needs to be set when UseModel was
+	// called on the wrapped provider rather than this one.
+	Model *core.Model
+
+	Metrics *Metrics
+}
+
+// Provider is a core.Provider that records request counts, latency, token
+// usage and stream time to first token.
+type Provider struct {
+	provider core.Provider
+	name     string
+	model    *core.Model
+	metrics  *Metrics
+}
+
+// NewProvider creates a new instrumented Provider.
+func NewProvider(opts *ProviderOpts) *Provider {
+	return &Provider{
+		provider: opts.Provider,
+		name:     opts.Name,
+		model:    opts.Model,
+		metrics:  opts.Metrics,
+	}
+}
+
+func (p *Provider) GetCapabilities(ctx context.Context) (*core.Capabilities, error) {
+	return p.provider.GetCapabilities(ctx)
+}
+
+func (p *Provider) UseModel(ctx context.Context, model *core.Model) error {
+	p.model = model
+	return p.provider.UseModel(ctx, model)
+}
+
+func (p *Provider) modelID() string {
+	if p.model == nil {
+		return ""
+	}
+
+	return p.model.ID
+}
+
+// Generate calls the wrapped provider and records the request.
+func (p *Provider) Generate(ctx context.Context, opts *core.GenerateOptions) (*core.Message, error) {
+	start := time.Now()
+
+	msg, err := p.provider.Generate(ctx, opts)
+	p.metrics.providerLatency.WithLabelValues(p.name, p.modelID()).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		p.metrics.providerRequests.WithLabelValues(p.name, p.modelID(), StatusError).Inc()
+		return nil, err
+	}
+
+	p.metrics.providerRequests.WithLabelValues(p.name, p.modelID(), StatusSuccess).Inc()
+	p.recordTokens(opts, msg)
+
+	return msg, nil
+}
+
+// GenerateStream calls the wrapped provider and records the request once the
+// stream is complete, along with the time until its first delta.
+func (p *Provider) GenerateStream(ctx context.Context, opts *core.GenerateOptions) (<-chan *core.Message, <-chan string, <-chan error) {
+	start := time.Now()
+
+	msgChan, deltaChan, errChan := p.provider.GenerateStream(ctx, opts)
+	if msgChan == nil && deltaChan == nil && errChan == nil {
+		return nil, nil, nil
+	}
+
+	// buffered, non-blocking channels
+	outMsgChan := make(chan *core.Message, 10)
+	outDeltaChan := make(chan string, 10)
+	outErrChan := make(chan error, 10)
+
+	go func() {
+		defer close(outMsgChan)
+		defer close(outDeltaChan)
+		defer close(outErrChan)
+
+		modelID := p.modelID()
+		firstToken := true
+		failed := false
+
+		for msgChan != nil || deltaChan != nil || errChan != nil {
+			select {
+			case msg, ok := <-msgChan:
+				if !ok {
+					msgChan = nil
+					continue
+				}
+
+				if msg != nil {
+					p.recordTokens(opts, msg)
+				}
+				outMsgChan <- msg
+
+			case delta, ok := <-deltaChan:
+				if !ok {
+					deltaChan = nil
+					continue
+				}
+
+				if firstToken && delta != "" {
+					firstToken = false
+					p.metrics.timeToFirstToken.WithLabelValues(p.name, modelID).Observe(time.Since(start).Seconds())
+				}
+				outDeltaChan <- delta
+
+			case err, ok := <-errChan:
+				if !ok {
+					errChan = nil
+					continue
+				}
+
+				if err != nil {
+					failed = true
+				}
+				outErrChan <- err
+			}
+		}
+
+		status := StatusSuccess
+		if failed {
+			status = StatusError
+		}
+
+		p.metrics.providerRequests.WithLabelValues(p.name, modelID, status).Inc()
+		p.metrics.providerLatency.WithLabelValues(p.name, modelID).Observe(time.Since(start).Seconds())
+	}()
+
+	return outMsgChan, outDeltaChan, outErrChan
+}
+
+func (p *Provider) recordTokens(opts *core.GenerateOptions, msg *core.Message) {
+	u := usage.FromResponse(opts, msg)
+
+	p.metrics.tokens.WithLabelValues(p.name, p.modelID(), TokenTypePrompt).Add(float64(u.PromptTokens))
+	p.metrics.tokens.WithLabelValues(p.name, p.modelID(), TokenTypeCompletion).Add(float64(u.CompletionTokens))
+}
@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/agent-api/core"
+)
+
+// WrapTool returns a copy of the tool whose invocations, errors and duration
+// are recorded.
+func (m *Metrics) WrapTool(tool *core.Tool) *core.Tool {
+	fn := tool.WrappedToolFunction
+
+	invocations := m.toolInvocations.WithLabelValues(tool.Name)
+	errs := m.toolErrors.WithLabelValues(tool.Name)
+	latency := m.toolLatency.WithLabelValues(tool.Name)
+
+	wrapped := *tool
+	wrapped.WrappedToolFunction = func(ctx context.Context, args []byte) (interface{}, error) {
+		start := time.Now()
+		invocations.Inc()
+
+		result, err := fn(ctx, args)
+		latency.Observe(time.Since(start).Seconds())
+
+		if err != nil {
+			errs.Inc()
+		}
+
+		return result, err
+	}
+
+	return &wrapped
+}
+
+// WrapTools wraps every tool with WrapTool.
+func (m *Metrics) WrapTools(tools ...*core.Tool) []*core.Tool {
+	wrapped := make([]*core.Tool, len(tools))
+	for i, t := range tools {
+		wrapped[i] = m.WrapTool(t)
+	}
+
+	return wrapped
+}
@@ -0,0 +1,172 @@
+package modelinfo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/agent-api/core"
+)
+
+var (
+	// ErrLimitExceeded is matched by every *ExceededError through errors.Is.
+	ErrLimitExceeded = errors.New("model limit exceeded")
+
+	// ErrUnsupported is matched by every *UnsupportedError through
+	// errors.Is.
+	ErrUnsupported = errors.New("unsupported by model")
+)
+
+// Limit names the model limit a request exceeded.
+type Limit string
+
+const (
+	LimitContext Limit = "context_window"
+	LimitOutput  Limit = "max_output_tokens"
+)
+
+// ExceededError is returned for requests that would exceed a model's limits.
+type ExceededError struct {
+	Model string
+	Limit Limit
+
+	// Tokens is the estimated prompt tokens plus requested output tokens for
+	// LimitContext, and the requested output tokens for LimitOutput
+	Tokens int
+	Max    int
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("model limit exceeded: %s %s is %d tokens, request needs ~%d", e.Model, e.Limit, e.Max, e.Tokens)
+}
+
+func (e *ExceededError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// Feature names a model capability.
+type Feature string
+
+const (
+	FeatureTools  Feature = "tools"
+	FeatureVision Feature = "vision"
+)
+
+// UnsupportedError is returned for requests using a feature a model lacks.
+type UnsupportedError struct {
+	Model   string
+	Feature Feature
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("unsupported by model: %s does not support %s", e.Model, e.Feature)
+}
+
+func (e *UnsupportedError) Is(target error) bool {
+	return target == ErrUnsupported
+}
+
+// Check returns an *UnsupportedError if a request uses tools or images the
+// model does not support, or an *ExceededError if it asks for more output
+// than the model generates or its estimated prompt plus requested output
+// exceed the context window. A request without MaxTokens reserves no output.
+func (i *Info) Check(opts *core.GenerateOptions) error {
+	if len(opts.Tools) > 0 && !i.Tools {
+		return &UnsupportedError{Model: i.ID, Feature: FeatureTools}
+	}
+
+	if !i.Vision {
+		for _, m := range opts.Messages {
+			if len(m.Images) > 0 {
+				return &UnsupportedError{Model: i.ID, Feature: FeatureVision}
+			}
+		}
+	}
+
+	if i.MaxOutputTokens > 0 && opts.MaxTokens > i.MaxOutputTokens {
+		return &ExceededError{Model: i.ID, Limit: LimitOutput, Tokens: opts.MaxTokens, Max: i.MaxOutputTokens}
+	}
+
+	if i.ContextWindow > 0 {
+		if tokens := i.PromptTokens(opts) + opts.MaxTokens; tokens > i.ContextWindow {
+			return &ExceededError{Model: i.ID, Limit: LimitContext, Tokens: tokens, Max: i.ContextWindow}
+		}
+	}
+
+	return nil
+}
+
+// Trim drops the oldest messages until the estimated tokens of the rest fit
+// in budget. Leading system messages and the latest turn, from the last user
+// message on, are always kept. Whole turns are dropped, so tool results are
+// never separated from the calls that produced them. It returns the kept
+// messages and the number dropped; the kept messages may still exceed budget
+// when the system messages and latest turn alone do.
+func (i *Info) Trim(messages []*core.Message, budget int) ([]*core.Message, int) {
+	system := 0
+	for system < len(messages) && messages[system].Role == core.SystemMessageRole {
+		system++
+	}
+
+	// suffix[j] is the tokens of messages[j:]
+	suffix := make([]int, len(messages)+1)
+	for j := len(messages) - 1; j >= 0; j-- {
+		suffix[j] = suffix[j+1] + i.MessageTokens(messages[j])
+	}
+
+	systemTokens := suffix[0] - suffix[system]
+	if suffix[0] <= budget {
+		return messages, 0
+	}
+
+	// turns start at user messages: cut before the earliest one that fits,
+	// or the latest one if none do
+	cut := system
+	for j := system + 1; j < len(messages); j++ {
+		if messages[j].Role != core.UserMessageRole {
+			continue
+		}
+
+		cut = j
+		if systemTokens+suffix[j] <= budget {
+			break
+		}
+	}
+
+	if cut == system {
+		return messages, 0
+	}
+
+	kept := make([]*core.Message, 0, system+len(messages)-cut)
+	kept = append(kept, messages[:system]...)
+	kept = append(kept, messages[cut:]...)
+
+	return kept, cut - system
+}
+
+// TrimRequest returns a copy of the request with its messages trimmed to
+// fit, with its tools, in the context window less its MaxTokens, or less
+// reserve when it has none. The request itself is not modified.
+func (i *Info) TrimRequest(opts *core.GenerateOptions, reserve int) (*core.GenerateOptions, int) {
+	if i.ContextWindow <= 0 {
+		return opts, 0
+	}
+
+	if opts.MaxTokens > 0 {
+		reserve = opts.MaxTokens
+	}
+
+	budget := i.ContextWindow - reserve
+	for _, t := range opts.Tools {
+		budget -= i.ToolTokens(t)
+	}
+
+	messages, dropped := i.Trim(opts.Messages, budget)
+	if dropped == 0 {
+		return opts, 0
+	}
+
+	trimmed := *opts
+	trimmed.Messages = messages
+
+	return &trimmed, dropped
+}
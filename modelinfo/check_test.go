@@ -0,0 +1,139 @@
+package modelinfo
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/agent-api/core"
+)
+
+// message returns a message of 8 estimated tokens, 4 of overhead and 4 of
+// content, identified by its content.
+func message(id string, role core.MessageRole) *core.Message {
+	return &core.Message{Role: role, Content: fmt.Sprintf("%-16s", id)}
+}
+
+func messageIDs(messages []*core.Message) []string {
+	ids := []string{}
+	for _, m := range messages {
+		ids = append(ids, strings.TrimSpace(m.Content))
+	}
+
+	return ids
+}
+
+func TestInfoTrim(t *testing.T) {
+	conversation := []*core.Message{
+		message("system", core.SystemMessageRole),
+		message("user1", core.UserMessageRole),
+		message("assistant1", core.AssistantMessageRole),
+		message("user2", core.UserMessageRole),
+		message("call", core.AssistantMessageRole),
+		message("result", core.ToolMessageRole),
+		message("assistant2", core.AssistantMessageRole),
+		message("user3", core.UserMessageRole),
+		message("assistant3", core.AssistantMessageRole),
+	}
+
+	noSystem := []*core.Message{
+		message("user1", core.UserMessageRole),
+		message("assistant1", core.AssistantMessageRole),
+		message("user2", core.UserMessageRole),
+		message("assistant2", core.AssistantMessageRole),
+	}
+
+	tests := []struct {
+		name     string
+		messages []*core.Message
+		budget   int
+		want     []string
+		dropped  int
+	}{
+		{
+			name:     "fits",
+			messages: conversation,
+			budget:   72,
+			want:     messageIDs(conversation),
+		},
+		{
+			name:     "drops the oldest turn",
+			messages: conversation,
+			budget:   71,
+			want:     []string{"system", "user2", "call", "result", "assistant2", "user3", "assistant3"},
+			dropped:  2,
+		},
+		{
+			name:     "drops tool results with their turn",
+			messages: conversation,
+			budget:   55,
+			want:     []string{"system", "user3", "assistant3"},
+			dropped:  6,
+		},
+		{
+			name:     "keeps the latest turn over budget",
+			messages: conversation,
+			budget:   10,
+			want:     []string{"system", "user3", "assistant3"},
+			dropped:  6,
+		},
+		{
+			name:     "without system messages",
+			messages: noSystem,
+			budget:   16,
+			want:     []string{"user2", "assistant2"},
+			dropped:  2,
+		},
+		{
+			name:     "a single turn is kept",
+			messages: conversation[:3],
+			budget:   1,
+			want:     []string{"system", "user1", "assistant1"},
+		},
+		{
+			name:     "empty",
+			messages: []*core.Message{},
+			budget:   0,
+			want:     []string{},
+		},
+	}
+
+	info := &Info{CharsPerToken: 4}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, dropped := info.Trim(tt.messages, tt.budget)
+
+			if ids := messageIDs(kept); !slices.Equal(ids, tt.want) {
+				t.Errorf("kept %v, want %v", ids, tt.want)
+			}
+
+			if dropped != tt.dropped {
+				t.Errorf("dropped %d, want %d", dropped, tt.dropped)
+			}
+		})
+	}
+}
+
+func TestInfoTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		info *Info
+		text string
+		want int
+	}{
+		{name: "empty", info: &Info{}, text: "", want: 0},
+		{name: "rounds up", info: &Info{}, text: "hello", want: 2},
+		{name: "chars per token", info: &Info{CharsPerToken: 2.5}, text: "hello", want: 2},
+		{name: "non ASCII count a token each", info: &Info{}, text: "héllo", want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.info.Tokens(tt.text); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
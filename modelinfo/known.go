@@ -0,0 +1,183 @@
+package modelinfo
+
+import (
+	anthropicmodels "github.com/agent-api/anthropic/models"
+	"github.com/agent-api/core"
+	googlegenaimodels "github.com/agent-api/googlegenai/models"
+	ollamamodels "github.com/agent-api/ollama/models"
+	openaimodels "github.com/agent-api/openai/models"
+)
+
+// Average characters per token of English text by tokenizer.
+const (
+	openAICharsPerToken    = 4.0
+	anthropicCharsPerToken = 3.5
+	googleCharsPerToken    = 4.0
+	ollamaCharsPerToken    = 3.7
+)
+
+// Known returns the infos of the models of the openai, anthropic,
+// googlegenai and ollama models packages. Ollama context windows are those
+// of the models: the Ollama server truncates prompts to its num_ctx setting,
+// which may be lower.
+func Known() []*Info {
+	infos := []*Info{}
+
+	openAI := func(contextWindow, maxOutput int, tools, vision, jsonMode bool, models ...*core.Model) {
+		for _, model := range models {
+			infos = append(infos, &Info{
+				ID:              model.ID,
+				Provider:        OpenAI,
+				ContextWindow:   contextWindow,
+				MaxOutputTokens: maxOutput,
+				Tools:           tools,
+				Vision:          vision,
+				JSONMode:        jsonMode,
+				CharsPerToken:   openAICharsPerToken,
+				ImageTokens:     openAIImageTokens,
+			})
+		}
+	}
+
+	openAI(16385, 4096, true, false, true, openaimodels.GPT3_5_TURBO, openaimodels.GPT3_5_TURBO_1106, openaimodels.GPT3_5_TURBO_0125)
+	openAI(16385, 4096, true, false, false, openaimodels.GPT3_5_TURBO_16K, openaimodels.GPT3_5_TURBO_16K_0613)
+	openAI(4096, 4096, true, false, false, openaimodels.GPT3_5_TURBO_0613)
+	openAI(4096, 4096, false, false, false, openaimodels.GPT3_5_TURBO_0301)
+
+	openAI(8192, 8192, true, false, false, openaimodels.GPT4, openaimodels.GPT4_0613)
+	openAI(8192, 8192, false, false, false, openaimodels.GPT4_0314)
+	openAI(32768, 8192, true, false, false, openaimodels.GPT4_32K, openaimodels.GPT4_32K_0613)
+	openAI(32768, 8192, false, false, false, openaimodels.GPT4_32K_0314)
+	openAI(128000, 4096, true, true, true, openaimodels.GPT4_TURBO, openaimodels.GPT4_TURBO_2024_04_09)
+	openAI(128000, 4096, true, false, true,
+		openaimodels.GPT4_TURBO_PREVIEW, openaimodels.GPT4_0125_PREVIEW, openaimodels.GPT4_1106_PREVIEW,
+	)
+	openAI(128000, 4096, false, true, false, openaimodels.GPT4_VISION_PREVIEW)
+
+	openAI(128000, 16384, true, true, true,
+		openaimodels.GPT4_O, openaimodels.GPT4_O_2024_11_20, openaimodels.GPT4_O_2024_08_06,
+		openaimodels.GPT4_O_MINI, openaimodels.GPT4_O_MINI_2024_07_18,
+	)
+	openAI(128000, 4096, true, true, true, openaimodels.GPT4_O_2024_05_13)
+	openAI(128000, 16384, false, true, true, openaimodels.CHATGPT_4O_LATEST)
+	openAI(128000, 16384, true, false, false,
+		openaimodels.GPT4_O_AUDIO_PREVIEW, openaimodels.GPT4_O_AUDIO_PREVIEW_2024_10_01, openaimodels.GPT4_O_AUDIO_PREVIEW_2024_12_17,
+		openaimodels.GPT4_O_MINI_AUDIO_PREVIEW, openaimodels.GPT4_O_MINI_AUDIO_PREVIEW_2024_12_17,
+	)
+
+	openAI(200000, 100000, true, true, true, openaimodels.O1, openaimodels.O1_2024_12_17)
+	openAI(128000, 32768, false, false, false, openaimodels.O1_PREVIEW, openaimodels.O1_PREVIEW_2024_09_12)
+	openAI(128000, 65536, false, false, false, openaimodels.O1_MINI, openaimodels.O1_MINI_2024_09_12)
+	openAI(200000, 100000, true, false, true, openaimodels.O3_MINI, openaimodels.O3_MINI_2025_01_31)
+
+	// Claude has no JSON mode: JSON output is prompted or forced through a
+	// tool
+	anthropic := func(maxOutput int, models ...*core.Model) {
+		for _, model := range models {
+			infos = append(infos, &Info{
+				ID:              model.ID,
+				Provider:        Anthropic,
+				ContextWindow:   200000,
+				MaxOutputTokens: maxOutput,
+				Tools:           true,
+				Vision:          true,
+				CharsPerToken:   anthropicCharsPerToken,
+				ImageTokens:     anthropicImageTokens,
+			})
+		}
+	}
+
+	anthropic(8192, anthropicmodels.CLAUDE_3_7_SONNET, anthropicmodels.CLAUDE_3_5_SONNET_V2)
+	anthropic(4096, anthropicmodels.CLAUDE_3_5_SONNET)
+
+	google := func(contextWindow int, models ...*core.Model) {
+		for _, model := range models {
+			infos = append(infos, &Info{
+				ID:              model.ID,
+				Provider:        Google,
+				ContextWindow:   contextWindow,
+				MaxOutputTokens: 8192,
+				Tools:           true,
+				Vision:          true,
+				JSONMode:        true,
+				CharsPerToken:   googleCharsPerToken,
+				ImageTokens:     googleImageTokens,
+			})
+		}
+	}
+
+	google(1048576, googlegenaimodels.GEMINI_1_5_FLASH)
+
+	// Ollama only limits output by the context window, and supports JSON
+	// mode for every model through its format parameter
+	ollama := func(model *core.Model, contextWindow int, tools, vision bool) {
+		infos = append(infos, &Info{
+			ID:              model.ID,
+			Provider:        Ollama,
+			ContextWindow:   contextWindow,
+			MaxOutputTokens: contextWindow,
+			Tools:           tools,
+			Vision:          vision,
+			JSONMode:        true,
+			CharsPerToken:   ollamaCharsPerToken,
+			ImageTokens:     gemmaImageTokens,
+		})
+	}
+
+	ollama(ollamamodels.DEEPSEEK_R1_7B, 131072, false, false)
+	ollama(ollamamodels.QWEN2_5_LATEST, 32768, true, false)
+	ollama(ollamamodels.GEMMA3_LATEST, 131072, false, true)
+
+	return infos
+}
+
+// openAIImageTokens is the cost of a high detail image: 85 tokens plus 170
+// per 512px tile, after fitting it in 2048x2048 then scaling its shortest
+// side to 768px.
+func openAIImageTokens(width, height int) int {
+	w, h := float64(width), float64(height)
+
+	if longest := max(w, h); longest > 2048 {
+		w, h = w*2048/longest, h*2048/longest
+	}
+
+	if shortest := min(w, h); shortest > 768 {
+		w, h = w*768/shortest, h*768/shortest
+	}
+
+	tiles := ceilDiv(int(w), 512) * ceilDiv(int(h), 512)
+
+	return 85 + 170*tiles
+}
+
+// anthropicImageTokens is width*height/750, after fitting the image in
+// 1568px on its longest side.
+func anthropicImageTokens(width, height int) int {
+	w, h := float64(width), float64(height)
+
+	if longest := max(w, h); longest > 1568 {
+		w, h = w*1568/longest, h*1568/longest
+	}
+
+	return int(w*h/750) + 1
+}
+
+// googleImageTokens is 258 tokens for images up to 384px, and 258 per 768px
+// tile of larger images.
+func googleImageTokens(width, height int) int {
+	if width <= 384 && height <= 384 {
+		return 258
+	}
+
+	return 258 * ceilDiv(width, 768) * ceilDiv(height, 768)
+}
+
+// gemmaImageTokens is the fixed cost of an image for Gemma 3, which encodes
+// every image at 896x896.
+func gemmaImageTokens(width, height int) int {
+	return 256
+}
+
+func ceilDiv(a, b int) int {
+	return max((a+b-1)/b, 1)
+}
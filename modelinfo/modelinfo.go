@@ -0,0 +1,153 @@
+// Package modelinfo is a registry of what models can do: their context
+// window, maximum output, and support for tools, images and JSON mode. It
+// also estimates tokens, so requests can be checked against a model's limits
+// and trimmed before calling Generate.
+//
+// Models are registered by the ID of their core.Model, i.e., the constants
+// of the openai, anthropic, googlegenai and ollama models packages.
+package modelinfo
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/agent-api/core"
+)
+
+// Provider names.
+const (
+	OpenAI    = "openai"
+	Anthropic = "anthropic"
+	Google    = "googlegenai"
+	Ollama    = "ollama"
+)
+
+// Info describes a model's limits and capabilities.
+type Info struct {
+	// ID is the model's core.Model ID
+	ID string
+
+	Provider string
+
+	// ContextWindow is the maximum number of input and output tokens
+	ContextWindow int
+
+	// MaxOutputTokens is the maximum number of tokens generated per request
+	MaxOutputTokens int
+
+	Tools    bool
+	Vision   bool
+	JSONMode bool
+
+	// CharsPerToken is the average number of characters of English text per
+	// token of the model's tokenizer, used to estimate tokens
+	CharsPerToken float64
+
+	// ImageTokens estimates the tokens of an image of the given size. When
+	// nil, images count DefaultImageTokens.
+	ImageTokens func(width, height int) int
+}
+
+// Registry holds model infos by ID. It is safe for concurrent use.
+type Registry struct {
+	mu     sync.RWMutex
+	models map[string]*Info
+}
+
+// NewRegistry creates a Registry holding the given infos.
+func NewRegistry(infos ...*Info) *Registry {
+	r := &Registry{
+		models: make(map[string]*Info),
+	}
+
+	r.Register(infos...)
+
+	return r
+}
+
+// Register adds infos to the registry, replacing any with the same ID.
+func (r *Registry) Register(infos ...*Info) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, info := range infos {
+		r.models[info.ID] = info
+	}
+}
+
+// Lookup returns the info of a model ID. IDs that are not registered fall
+// back to their Ollama ":latest" tag, i.e., "qwen2.5:7b" to "qwen2.5:latest",
+// then to the longest registered ID they extend with a "-" suffix, i.e.,
+// "gpt-4o-2025-01-01" to "gpt-4o".
+func (r *Registry) Lookup(id string) (*Info, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if info, ok := r.models[id]; ok {
+		return info, true
+	}
+
+	if name, _, ok := strings.Cut(id, ":"); ok {
+		if info, ok := r.models[name+":latest"]; ok {
+			return info, true
+		}
+	}
+
+	var match *Info
+	for registered, info := range r.models {
+		if strings.HasPrefix(id, registered+"-") && (match == nil || len(registered) > len(match.ID)) {
+			match = info
+		}
+	}
+
+	return match, match != nil
+}
+
+// LookupModel returns the info of a core.Model.
+func (r *Registry) LookupModel(model *core.Model) (*Info, bool) {
+	if model == nil {
+		return nil, false
+	}
+
+	return r.Lookup(model.ID)
+}
+
+// Models returns every registered info, sorted by provider and ID.
+func (r *Registry) Models() []*Info {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	infos := make([]*Info, 0, len(r.models))
+	for _, info := range r.models {
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Provider != infos[j].Provider {
+			return infos[i].Provider < infos[j].Provider
+		}
+
+		return infos[i].ID < infos[j].ID
+	})
+
+	return infos
+}
+
+var defaultRegistry = NewRegistry(Known()...)
+
+// Default returns the registry of Known models shared by the package level
+// functions. Models registered with it are seen by every user of Default.
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Lookup returns the info of a model ID from the Default registry.
+func Lookup(id string) (*Info, bool) {
+	return defaultRegistry.Lookup(id)
+}
+
+// LookupModel returns the info of a core.Model from the Default registry.
+func LookupModel(model *core.Model) (*Info, bool) {
+	return defaultRegistry.LookupModel(model)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/core/agent/bootstrap"
+	"github.com/agent-api/examples/modelinfo"
+	"github.com/agent-api/ollama"
+	"github.com/agent-api/ollama/models"
+)
+
+func main() {
+	ctx := context.Background()
+
+	// Create a zap logger
+	config := zap.NewDevelopmentConfig()
+	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	zLogger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a logr.Logger using zapr adapter
+	logger := zapr.NewLogger(zLogger)
+
+	fmt.Printf("%-12s %-36s %10s %10s %6s %6s %5s\n", "provider", "model", "context", "output", "tools", "vision", "json")
+	for _, info := range modelinfo.Default().Models() {
+		fmt.Printf("%-12s %-36s %10d %10d %6t %6t %5t\n",
+			info.Provider, info.ID, info.ContextWindow, info.MaxOutputTokens, info.Tools, info.Vision, info.JSONMode)
+	}
+
+	// Requests can be checked without calling a provider: a long document
+	// does not fit gpt-4's 8k context window but fits gpt-4o's
+	document := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 2000)
+	request := &core.GenerateOptions{
+		Messages:  []*core.Message{{Role: core.UserMessageRole, Content: "Summarize:\n" + document}},
+		MaxTokens: 1000,
+	}
+
+	for _, id := range []string{"gpt-4", "gpt-4o"} {
+		info, _ := modelinfo.Lookup(id)
+		fmt.Printf("\n%s: ~%d prompt tokens, check: %v\n", id, info.PromptTokens(request), info.Check(request))
+	}
+
+	// Create an ollama provider
+	provider := ollama.NewProvider(&ollama.ProviderOpts{
+		Logger:  &logger,
+		BaseURL: "http://localhost",
+		Port:    11434,
+	})
+
+	// Wrap it so requests are checked against the model's info, and long
+	// conversations are trimmed to its context window rather than failing
+	checked, err := modelinfo.NewProvider(&modelinfo.ProviderOpts{
+		Provider:      provider,
+		Trim:          true,
+		ReserveTokens: 1024,
+		Logger:        &logger,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// deepseek-r1 does not support tools: the check fails before any
+	// request is sent to ollama
+	checked.UseModel(ctx, models.DEEPSEEK_R1_7B)
+
+	_, err = checked.Generate(ctx, &core.GenerateOptions{
+		Messages: []*core.Message{{Role: core.UserMessageRole, Content: "What's the weather in Lisbon?"}},
+		Tools:    []*core.Tool{{Name: "get_weather", Description: "Gets the weather of a city"}},
+	})
+	if errors.Is(err, modelinfo.ErrUnsupported) {
+		fmt.Println("\nrequest failed the check:", err)
+	}
+
+	checked.UseModel(ctx, models.QWEN2_5_LATEST)
+
+	// Create a new agent
+	myAgent, err := agent.NewAgent(
+		bootstrap.WithProvider(checked),
+		bootstrap.WithLogger(&logger),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	response, err := myAgent.Run(
+		ctx,
+		agent.WithInput("Why is the sky blue? Answer in one sentence."),
+	)
+	if err != nil {
+		logger.Error(err, "failed sending message to agent")
+		return
+	}
+
+	fmt.Println("\nAgent response:", response.Messages[len(response.Messages)-1].Content)
+}
@@ -0,0 +1,128 @@
+package modelinfo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-logr/logr"
+
+	"github.com/agent-api/core"
+)
+
+// ProviderOpts configures a new checking Provider.
+type ProviderOpts struct {
+	// The wrapped core.Provider
+	Provider core.Provider
+
+	// Model is the model the wrapped provider uses. It only needs to be set
+	// when UseModel was called on the wrapped provider rather than this one.
+	Model *core.Model
+
+	// Registry the model's info is looked up in. Defaults to Default().
+	Registry *Registry
+
+	// Trim drops the oldest turns of requests that would exceed the context
+	// window, instead of failing them
+	Trim bool
+
+	// ReserveTokens is the output reserved in the context window when
+	// trimming requests without MaxTokens
+	ReserveTokens int
+
+	Logger *logr.Logger
+}
+
+// Provider is a core.Provider that checks requests against its model's info
+// before calling the wrapped provider, failing them with an *ExceededError or
+// *UnsupportedError, or trimming them to fit. Requests for models that are
+// not in the registry are passed through unchecked.
+type Provider struct {
+	provider core.Provider
+	model    *core.Model
+	registry *Registry
+	trim     bool
+	reserve  int
+
+	logger *logr.Logger
+}
+
+// NewProvider creates a new checking Provider.
+func NewProvider(opts *ProviderOpts) (*Provider, error) {
+	if opts.Provider == nil {
+		return nil, errors.New("checking provider requires a provider")
+	}
+
+	if opts.Registry == nil {
+		opts.Registry = Default()
+	}
+
+	return &Provider{
+		provider: opts.Provider,
+		model:    opts.Model,
+		registry: opts.Registry,
+		trim:     opts.Trim,
+		reserve:  opts.ReserveTokens,
+		logger:   opts.Logger,
+	}, nil
+}
+
+func (p *Provider) GetCapabilities(ctx context.Context) (*core.Capabilities, error) {
+	return p.provider.GetCapabilities(ctx)
+}
+
+func (p *Provider) UseModel(ctx context.Context, model *core.Model) error {
+	p.model = model
+	return p.provider.UseModel(ctx, model)
+}
+
+// Generate checks, and trims if enabled, the request before calling the
+// wrapped provider.
+func (p *Provider) Generate(ctx context.Context, opts *core.GenerateOptions) (*core.Message, error) {
+	opts, err := p.prepare(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.provider.Generate(ctx, opts)
+}
+
+// GenerateStream checks, and trims if enabled, the request before calling
+// the wrapped provider. A failed check is sent on the error channel.
+func (p *Provider) GenerateStream(ctx context.Context, opts *core.GenerateOptions) (<-chan *core.Message, <-chan string, <-chan error) {
+	opts, err := p.prepare(opts)
+	if err != nil {
+		errChan := make(chan error, 1)
+		errChan <- err
+		close(errChan)
+
+		return nil, nil, errChan
+	}
+
+	return p.provider.GenerateStream(ctx, opts)
+}
+
+func (p *Provider) prepare(opts *core.GenerateOptions) (*core.GenerateOptions, error) {
+	info, ok := p.registry.LookupModel(p.model)
+	if !ok {
+		if p.logger != nil && p.model != nil {
+			p.logger.V(1).Info("no model info, request not checked", "model", p.model.ID)
+		}
+
+		return opts, nil
+	}
+
+	if p.trim {
+		var dropped int
+		opts, dropped = info.TrimRequest(opts, p.reserve)
+
+		if p.logger != nil && dropped > 0 {
+			p.logger.Info("trimmed request to fit context window", "model", info.ID, "dropped", dropped, "tokens", info.PromptTokens(opts))
+		}
+	}
+
+	if err := info.Check(opts); err != nil {
+		return nil, err
+	}
+
+	return opts, nil
+}
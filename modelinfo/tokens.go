@@ -0,0 +1,141 @@
+package modelinfo
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/agent-api/core"
+)
+
+const (
+	// DefaultCharsPerToken is used for infos without a CharsPerToken
+	DefaultCharsPerToken = 4.0
+
+	// DefaultImageTokens is counted for images whose size cannot be decoded
+	// and for infos without an ImageTokens func
+	DefaultImageTokens = 1000
+
+	// messageOverhead approximates the tokens providers add around each
+	// message for its role and delimiters
+	messageOverhead = 4
+
+	// toolOverhead approximates the tokens providers add around each tool
+	// definition
+	toolOverhead = 8
+)
+
+// EstimateTokens estimates the tokens of text for a model whose tokenizer is
+// unknown, at DefaultCharsPerToken characters per token.
+func EstimateTokens(text string) int {
+	return (&Info{}).Tokens(text)
+}
+
+// Tokens estimates the tokens of text. ASCII counts CharsPerToken characters
+// per token, while other characters, which tokenizers split more finely,
+// count a token each.
+func (i *Info) Tokens(text string) int {
+	if text == "" {
+		return 0
+	}
+
+	charsPerToken := i.CharsPerToken
+	if charsPerToken <= 0 {
+		charsPerToken = DefaultCharsPerToken
+	}
+
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+
+	return int(math.Ceil(float64(ascii)/charsPerToken)) + other
+}
+
+// ImageTokensOf estimates the tokens of an image from its decoded size.
+// JPEG, PNG and GIF sizes are decoded, other formats count
+// DefaultImageTokens.
+func (i *Info) ImageTokensOf(img *core.Image) int {
+	if i.ImageTokens == nil {
+		return DefaultImageTokens
+	}
+
+	decoder := base64.NewDecoder(base64.StdEncoding, strings.NewReader(img.Base64Encoding))
+	config, _, err := image.DecodeConfig(decoder)
+	if err != nil {
+		return DefaultImageTokens
+	}
+
+	return i.ImageTokens(config.Width, config.Height)
+}
+
+// MessageTokens estimates the tokens of a message's content, images, tool
+// calls and tool results. It can be used as a memory.TokenCounter.
+func (i *Info) MessageTokens(m *core.Message) int {
+	tokens := messageOverhead + i.Tokens(m.Content)
+
+	for _, img := range m.Images {
+		tokens += i.ImageTokensOf(img)
+	}
+
+	for _, tc := range m.ToolCalls {
+		tokens += i.Tokens(tc.Name) + i.Tokens(string(tc.Arguments))
+	}
+
+	for _, tr := range m.ToolResult {
+		tokens += i.Tokens(ToolResultText(tr))
+	}
+
+	return tokens
+}
+
+// ToolTokens estimates the tokens of a tool definition.
+func (i *Info) ToolTokens(t *core.Tool) int {
+	return toolOverhead + i.Tokens(t.Name) + i.Tokens(t.Description) + i.Tokens(string(t.JSONSchema))
+}
+
+// PromptTokens estimates the prompt tokens of a request: its messages and
+// tool definitions.
+func (i *Info) PromptTokens(opts *core.GenerateOptions) int {
+	tokens := 0
+
+	for _, m := range opts.Messages {
+		tokens += i.MessageTokens(m)
+	}
+
+	for _, t := range opts.Tools {
+		tokens += i.ToolTokens(t)
+	}
+
+	return tokens
+}
+
+// ToolResultText returns a tool result as the text sent to models: its
+// content, JSON encoded unless it is a string, or its error if it failed.
+func ToolResultText(tr *core.ToolResult) string {
+	if tr.Error != "" {
+		return "error: " + tr.Error
+	}
+
+	if s, ok := tr.Content.(string); ok {
+		return s
+	}
+
+	b, err := json.Marshal(tr.Content)
+	if err != nil {
+		return fmt.Sprint(tr.Content)
+	}
+
+	return string(b)
+}
@@ -0,0 +1,321 @@
+// Package orchestration wraps agents as core.Tools so a "supervisor" agent can
+// delegate subtasks to any number of specialized "subagents" (like the web scraper
+// agent) during a single run.
+package orchestration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/go-logr/logr"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/core/agent/bootstrap"
+	"github.com/agent-api/examples/internal/agentrun"
+)
+
+// SubAgent describes an agent the supervisor may delegate to.
+type SubAgent struct {
+	// Name is the tool name the supervisor uses to call this subagent.
+	Name string
+
+	// Description tells the supervisor what this subagent is good at.
+	Description string
+
+	// New creates the runner for a single delegated run. Every delegation
+	// gets a fresh agent, so delegations never share memory: the supervisor
+	// may delegate several tasks in parallel, and an agent's memory would
+	// otherwise fill up with earlier tasks.
+	New func() (agentrun.Runner, error)
+
+	// MaxSteps caps the number of messages a single delegated run may produce.
+	// A value of 0 leaves the subagent's own configured limit in place.
+	MaxSteps int
+}
+
+// OrchestratorOpts configures a new Orchestrator.
+type OrchestratorOpts struct {
+	// The core.Provider used by the supervisor agent
+	Provider core.Provider
+
+	// Maximum number of steps for the supervisor agent
+	MaxSteps int
+
+	// The agents the supervisor can delegate to
+	SubAgents []*SubAgent
+
+	Logger *logr.Logger
+}
+
+// Orchestrator runs a supervisor agent that has every configured subagent
+// available to it as a tool. Every Run creates a new supervisor, so runs never
+// share the supervisor's memory.
+type Orchestrator struct {
+	provider  core.Provider
+	maxSteps  int
+	subAgents map[string]*SubAgent
+
+	// order is the order subagents were added in, which their tools are
+	// given to the supervisor in
+	order []*SubAgent
+
+	// mu guards subRuns: subagent tools are executed in parallel by the
+	// supervisor agent
+	mu      sync.Mutex
+	subRuns map[string][]*SubRun
+
+	logger *logr.Logger
+}
+
+// SubRun records a single delegated run of a subagent.
+type SubRun struct {
+	TraceID string
+	Agent   string
+	Task    string
+	Output  string
+
+	// StepLimitReached is true when the subagent was stopped by its
+	// SubAgent.MaxSteps limit before producing a final answer.
+	StepLimitReached bool
+
+	Messages []*core.Message
+	Usage    Usage
+	Err      error
+}
+
+// Result is the outcome of a full orchestrated run.
+type Result struct {
+	TraceID string
+
+	// Messages are the supervisor agent's messages
+	Messages []*core.Message
+
+	// SubRuns are all delegated subagent runs in the order they completed
+	SubRuns []*SubRun
+
+	// Usage is the aggregated token usage of the supervisor and every subagent
+	Usage Usage
+}
+
+// FinalMessage returns the last message produced by the supervisor.
+func (r *Result) FinalMessage() *core.Message {
+	if len(r.Messages) == 0 {
+		return nil
+	}
+
+	return r.Messages[len(r.Messages)-1]
+}
+
+const delegateSchema string = `{
+  "title": "delegate",
+  "description": "Delegate a task to a specialized agent",
+  "type": "object",
+  "properties": {
+    "task": {
+      "description": "A complete, self-contained description of the task for the agent",
+      "type": "string"
+    }
+  },
+  "required": [
+    "task"
+  ]
+}`
+
+type delegateParams struct {
+	Task string `json:"task"`
+}
+
+// NewOrchestrator creates an orchestrator with each subagent registered as a
+// tool of the supervisor.
+func NewOrchestrator(opts *OrchestratorOpts) (*Orchestrator, error) {
+	if opts.Provider == nil {
+		return nil, errors.New("no supervisor provider set")
+	}
+
+	if opts.MaxSteps == 0 {
+		opts.MaxSteps = 25
+	}
+
+	o := &Orchestrator{
+		provider:  opts.Provider,
+		maxSteps:  opts.MaxSteps,
+		subAgents: make(map[string]*SubAgent),
+		subRuns:   make(map[string][]*SubRun),
+		logger:    opts.Logger,
+	}
+
+	for _, sub := range opts.SubAgents {
+		if err := o.AddSubAgent(sub); err != nil {
+			return nil, err
+		}
+	}
+
+	return o, nil
+}
+
+// AddSubAgent registers a subagent as a tool of the supervisor. It must not be
+// called concurrently with Run.
+func (o *Orchestrator) AddSubAgent(sub *SubAgent) error {
+	if sub.Name == "" {
+		return errors.New("subagent must have a name")
+	}
+
+	if sub.New == nil {
+		return fmt.Errorf("subagent %s has no agent constructor", sub.Name)
+	}
+
+	if _, ok := o.subAgents[sub.Name]; ok {
+		return fmt.Errorf("subagent %s already registered", sub.Name)
+	}
+
+	o.subAgents[sub.Name] = sub
+	o.order = append(o.order, sub)
+
+	return nil
+}
+
+// newSupervisor creates the supervisor agent for a single run, with a tool
+// per subagent.
+func (o *Orchestrator) newSupervisor() (*agent.Agent, error) {
+	tools := make([]*core.Tool, len(o.order))
+	for i, sub := range o.order {
+		tools[i] = o.AsTool(sub)
+	}
+
+	conf := []bootstrap.NewAgentConfigFunc{
+		bootstrap.WithProvider(o.provider),
+		bootstrap.WithMaxSteps(o.maxSteps),
+		bootstrap.WithTools(tools...),
+	}
+	if o.logger != nil {
+		conf = append(conf, bootstrap.WithLogger(o.logger))
+	}
+
+	return agent.NewAgent(conf...)
+}
+
+// AsTool wraps a subagent as a core.Tool. Every delegated run is recorded
+// against the trace ID found in the calling context.
+func (o *Orchestrator) AsTool(sub *SubAgent) *core.Tool {
+	return &core.Tool{
+		Name:        sub.Name,
+		Description: sub.Description,
+		JSONSchema:  []byte(delegateSchema),
+		WrappedToolFunction: func(ctx context.Context, args []byte) (interface{}, error) {
+			params := &delegateParams{}
+			if err := json.Unmarshal(args, params); err != nil {
+				return nil, fmt.Errorf("error unmarshaling args: %w", err)
+			}
+
+			run := o.runSubAgent(ctx, sub, params.Task)
+			if run.Err != nil {
+				return nil, run.Err
+			}
+
+			return run.Output, nil
+		},
+	}
+}
+
+func (o *Orchestrator) runSubAgent(ctx context.Context, sub *SubAgent, task string) *SubRun {
+	traceID := TraceIDFromContext(ctx)
+	run := &SubRun{
+		TraceID: traceID,
+		Agent:   sub.Name,
+		Task:    task,
+	}
+
+	if o.logger != nil {
+		o.logger.V(1).Info("delegating to subagent", "agent", sub.Name, "trace_id", traceID)
+	}
+
+	runOpts := []agent.RunOptionFunc{agent.WithInput(task)}
+	if sub.MaxSteps > 0 {
+		runOpts = append(runOpts, agent.WithStopCondition(stepLimitStopCondition(sub.MaxSteps)))
+	}
+
+	var agg *agent.AgentRunAggregator
+	runner, err := sub.New()
+	if err == nil {
+		agg, err = runner.Run(ctx, runOpts...)
+	}
+
+	if agg != nil {
+		run.Messages = agg.Messages
+		run.Usage = EstimateUsage(agg.Messages)
+
+		if last := agg.Pop(); last != nil {
+			run.Output = last.Content
+		}
+
+		if sub.MaxSteps > 0 && !agent.DefaultStopCondition(agg) && len(agg.Messages) >= sub.MaxSteps {
+			run.StepLimitReached = true
+		}
+	}
+
+	if err != nil {
+		run.Err = fmt.Errorf("subagent %s failed: %w", sub.Name, err)
+	} else if run.StepLimitReached {
+		run.Output = fmt.Sprintf("subagent %s reached its step limit of %d without a final answer. Last output: %s", sub.Name, sub.MaxSteps, run.Output)
+	}
+
+	o.mu.Lock()
+	o.subRuns[traceID] = append(o.subRuns[traceID], run)
+	o.mu.Unlock()
+
+	return run
+}
+
+// Run executes the supervisor agent with the given input. A new trace ID is
+// generated unless ctx already carries one (see WithTraceID).
+func (o *Orchestrator) Run(ctx context.Context, opts ...agent.RunOptionFunc) (*Result, error) {
+	traceID := TraceIDFromContext(ctx)
+	if traceID == "" {
+		traceID = NewTraceID()
+		ctx = WithTraceID(ctx, traceID)
+	}
+
+	supervisor, err := o.newSupervisor()
+	if err != nil {
+		return nil, err
+	}
+
+	agg, err := supervisor.Run(ctx, opts...)
+
+	o.mu.Lock()
+	subRuns := o.subRuns[traceID]
+	delete(o.subRuns, traceID)
+	o.mu.Unlock()
+
+	result := &Result{
+		TraceID: traceID,
+		SubRuns: subRuns,
+	}
+
+	if agg != nil {
+		result.Messages = agg.Messages
+		result.Usage = EstimateUsage(agg.Messages)
+	}
+
+	for _, run := range subRuns {
+		result.Usage = result.Usage.Add(run.Usage)
+	}
+
+	return result, err
+}
+
+// stepLimitStopCondition stops a run on the default stop condition or once
+// the run has produced maxSteps messages.
+func stepLimitStopCondition(maxSteps int) agent.AgentStopCondition {
+	return func(agg *agent.AgentRunAggregator) bool {
+		if agent.DefaultStopCondition(agg) {
+			return true
+		}
+
+		return len(agg.Messages) >= maxSteps
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/core/agent/bootstrap"
+	"github.com/agent-api/examples/internal/agentrun"
+	"github.com/agent-api/examples/orchestration"
+	"github.com/agent-api/ollama"
+	"github.com/agent-api/ollama/models"
+	"github.com/agent-api/webscraper-agent"
+)
+
+const PROMPT string = "Scrape https://johncodes.com/archive/2025/01-11-whats-an-ai-agent/ and summarize it. Then multiply the number of words in your summary by 42."
+
+const calculatorSchema string = `{
+  "title": "calculator",
+  "description": "A simple calculator on ints",
+  "type": "object",
+  "properties": {
+    "a": {
+      "description": "The first operand",
+      "type": "number"
+    },
+    "b": {
+      "description": "The second operand",
+      "type": "number"
+    },
+    "operation": {
+      "description": "The operation to perform. One of [add, multiply]",
+      "type": "string"
+    }
+  },
+  "required": [
+    "operation",
+    "a",
+    "b"
+  ]
+}`
+
+type calculatorParams struct {
+	Operation string `json:"operation"`
+	A         int    `json:"a"`
+	B         int    `json:"b"`
+}
+
+func calculator(ctx context.Context, args *calculatorParams) (interface{}, error) {
+	switch args.Operation {
+	case "add":
+		return args.A + args.B, nil
+	case "multiply":
+		return args.A * args.B, nil
+	default:
+		return nil, fmt.Errorf("unsupported operation: %s", args.Operation)
+	}
+}
+
+func main() {
+	ctx := context.Background()
+
+	// Create a zap logger
+	config := zap.NewDevelopmentConfig()
+	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	zLogger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a logr.Logger using zapr adapter
+	logger := zapr.NewLogger(zLogger)
+
+	// Create an Ollama provider
+	provider := ollama.NewProvider(&ollama.ProviderOpts{
+		Logger:  &logger,
+		BaseURL: "http://localhost",
+		Port:    11434,
+	})
+	provider.UseModel(ctx, models.QWEN2_5_LATEST)
+
+	// The web scraper agent is used, as is, as a subagent. Each delegation
+	// creates a new agent so parallel delegations don't share memory.
+	newScraper := func() (agentrun.Runner, error) {
+		return webscraper.NewWebScraperAgent(&webscraper.WebScraperConfig{
+			Provider: provider,
+			Logger:   &logger,
+			MaxSteps: 15,
+		})
+	}
+
+	// A small calculator agent
+	wrappedCalc, err := core.WrapToolFunction(calculator)
+	if err != nil {
+		panic(err)
+	}
+
+	newCalcAgent := func() (agentrun.Runner, error) {
+		return agent.NewAgent(
+			bootstrap.WithProvider(provider),
+			bootstrap.WithLogger(&logger),
+			bootstrap.WithTools(&core.Tool{
+				Name:                "calculator",
+				Description:         "Performs basic arithmetic operations: supported operations are 'add' and 'multiply'",
+				WrappedToolFunction: wrappedCalc,
+				JSONSchema:          []byte(calculatorSchema),
+			}),
+		)
+	}
+
+	// Create the supervisor with both agents available as tools
+	orchestrator, err := orchestration.NewOrchestrator(&orchestration.OrchestratorOpts{
+		Provider: provider,
+		Logger:   &logger,
+		SubAgents: []*orchestration.SubAgent{
+			{
+				Name:        "web_scraper",
+				Description: "An agent that can fetch web pages and summarize their content",
+				New:         newScraper,
+				MaxSteps:    10,
+			},
+			{
+				Name:        "calculator_agent",
+				Description: "An agent that can add and multiply integers",
+				New:         newCalcAgent,
+				MaxSteps:    5,
+			},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	result, err := orchestrator.Run(
+		ctx,
+		agent.WithInput(PROMPT),
+	)
+	if err != nil {
+		logger.Error(err, "orchestrated run failed", "trace_id", result.TraceID)
+		return
+	}
+
+	for _, run := range result.SubRuns {
+		logger.Info("subagent run",
+			"trace_id", run.TraceID,
+			"agent", run.Agent,
+			"step_limit_reached", run.StepLimitReached,
+			"total_tokens", run.Usage.TotalTokens(),
+		)
+	}
+
+	logger.Info("orchestrated run finished",
+		"trace_id", result.TraceID,
+		"prompt_tokens", result.Usage.PromptTokens,
+		"completion_tokens", result.Usage.CompletionTokens,
+	)
+
+	fmt.Println("Supervisor response:", result.FinalMessage().Content)
+}
@@ -0,0 +1,28 @@
+package orchestration
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+type traceIDKey struct{}
+
+// WithTraceID returns a copy of ctx carrying the given trace ID. Every
+// subagent run started from that context shares the same trace ID.
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceIDFromContext returns the trace ID carried by ctx, or an empty string.
+func TraceIDFromContext(ctx context.Context) string {
+	traceID, _ := ctx.Value(traceIDKey{}).(string)
+	return traceID
+}
+
+// NewTraceID returns a random, 16 byte, hex encoded trace ID.
+func NewTraceID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,80 @@
+package orchestration
+
+import (
+	"strconv"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/examples/modelinfo"
+	"github.com/agent-api/examples/usage"
+)
+
+// Usage is a count of tokens consumed by one or more agent runs.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// TotalTokens returns the sum of prompt and completion tokens.
+func (u Usage) TotalTokens() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// Add returns the sum of two usages.
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		PromptTokens:     u.PromptTokens + other.PromptTokens,
+		CompletionTokens: u.CompletionTokens + other.CompletionTokens,
+	}
+}
+
+// EstimateUsage computes the token usage of a run from its messages.
+//
+// Providers that report usage do so through the usage.PromptTokensProperty
+// and usage.CompletionTokensProperty message metadata provider properties.
+// Otherwise, tokens are estimated with modelinfo.EstimateTokens: every
+// assistant message counts as completion tokens and everything before it as
+// its prompt.
+func EstimateUsage(messages []*core.Message) Usage {
+	total := Usage{}
+	seen := 0
+
+	for _, m := range messages {
+		if m == nil {
+			continue
+		}
+
+		tokens := modelinfo.EstimateTokens(m.Content)
+
+		if m.Role == core.AssistantMessageRole {
+			prompt, completion, ok := reportedUsage(m)
+			if !ok {
+				prompt, completion = seen, tokens
+			}
+
+			total.PromptTokens += prompt
+			total.CompletionTokens += completion
+		}
+
+		seen += tokens
+	}
+
+	return total
+}
+
+func reportedUsage(m *core.Message) (int, int, bool) {
+	if m.Metadata == nil || m.Metadata.ProviderProperties == nil {
+		return 0, 0, false
+	}
+
+	prompt, err := strconv.Atoi(m.Metadata.ProviderProperties[usage.PromptTokensProperty])
+	if err != nil {
+		return 0, 0, false
+	}
+
+	completion, err := strconv.Atoi(m.Metadata.ProviderProperties[usage.CompletionTokensProperty])
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return prompt, completion, true
+}
@@ -0,0 +1,111 @@
+package rag
+
+import (
+	"regexp"
+	"strings"
+)
+
+// citationPattern matches a bracketed citation group, i.e., [1a2b3c4d] or
+// [1a2b3c4d, 5e6f7a8b]. A bracket followed by "(" is a markdown link, not a
+// citation, and is excluded by ParseCitations.
+var citationPattern = regexp.MustCompile(`\[([^\[\]\n]+)\]`)
+
+// ParseCitations returns the distinct IDs cited in text, in the order they
+// are first cited. Groups may separate IDs with commas or semicolons.
+func ParseCitations(text string) []string {
+	seen := map[string]bool{}
+	ids := []string{}
+
+	for _, loc := range citationPattern.FindAllStringSubmatchIndex(text, -1) {
+		if end := loc[1]; end < len(text) && text[end] == '(' {
+			continue
+		}
+
+		group := text[loc[2]:loc[3]]
+		for _, id := range strings.FieldsFunc(group, func(r rune) bool { return r == ',' || r == ';' }) {
+			id = strings.TrimSpace(id)
+			id = strings.TrimPrefix(id, "source:")
+			id = strings.TrimSpace(id)
+
+			// prose in brackets is not a citation
+			if id == "" || strings.ContainsAny(id, " \t") || seen[id] {
+				continue
+			}
+
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// ResolveCitations parses the citations in text and matches them to
+// sources. IDs are matched case insensitively, and a cited ID that is a
+// prefix of exactly one source's ID resolves to it. Cited IDs matching no
+// source are returned as unknown.
+func ResolveCitations(text string, sources []*Source) ([]*Source, []string) {
+	cited := []*Source{}
+	unknown := []string{}
+	seen := map[*Source]bool{}
+
+	for _, id := range ParseCitations(text) {
+		source := findSource(id, sources)
+		if source == nil {
+			unknown = append(unknown, id)
+			continue
+		}
+
+		if !seen[source] {
+			seen[source] = true
+			cited = append(cited, source)
+		}
+	}
+
+	return cited, unknown
+}
+
+func findSource(id string, sources []*Source) *Source {
+	for _, s := range sources {
+		if strings.EqualFold(s.ID, id) {
+			return s
+		}
+	}
+
+	var match *Source
+	for _, s := range sources {
+		if len(id) >= 4 && len(id) < len(s.ID) && strings.EqualFold(s.ID[:len(id)], id) {
+			if match != nil {
+				return nil
+			}
+
+			match = s
+		}
+	}
+
+	return match
+}
+
+// StripCitations removes the citation groups from text, i.e., to compare an
+// answer with a reference answer.
+func StripCitations(text string) string {
+	var sb strings.Builder
+	last := 0
+
+	for _, loc := range citationPattern.FindAllStringIndex(text, -1) {
+		if loc[1] < len(text) && text[loc[1]] == '(' {
+			continue
+		}
+
+		if len(ParseCitations(text[loc[0]:loc[1]])) == 0 {
+			continue
+		}
+
+		sb.WriteString(text[last:loc[0]])
+		last = loc[1]
+	}
+
+	sb.WriteString(text[last:])
+
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
@@ -0,0 +1,153 @@
+package rag
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/agent-api/examples/vectorstorer"
+)
+
+func TestParseCitations(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "single",
+			text: "Bolts take 10 Nm [1a2b3c4d].",
+			want: []string{"1a2b3c4d"},
+		},
+		{
+			name: "groups",
+			text: "See [1a2b3c4d, 5e6f7a8b] and [9c0d1e2f; source:3a4b5c6d].",
+			want: []string{"1a2b3c4d", "5e6f7a8b", "9c0d1e2f", "3a4b5c6d"},
+		},
+		{
+			name: "distinct in order of first citation",
+			text: "[b2] then [a1] then [b2, a1]",
+			want: []string{"b2", "a1"},
+		},
+		{
+			name: "markdown links are not citations",
+			text: "Read [the docs](https://example.com) [1a2b3c4d]",
+			want: []string{"1a2b3c4d"},
+		},
+		{
+			name: "prose in brackets is not a citation",
+			text: "The value [in newtons] is [ ] unknown",
+			want: []string{},
+		},
+		{
+			name: "no citations",
+			text: "No sources were needed.",
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseCitations(tt.text); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveCitations(t *testing.T) {
+	sources := []*Source{
+		{ID: "1a2b3c4d", Embedding: &vectorstorer.Embedding{ID: "first"}},
+		{ID: "1a2b9999", Embedding: &vectorstorer.Embedding{ID: "second"}},
+		{ID: "5e6f7a8b", Embedding: &vectorstorer.Embedding{ID: "third"}},
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		cited   []string
+		unknown []string
+	}{
+		{
+			name:    "exact",
+			text:    "[5e6f7a8b] and [1a2b3c4d]",
+			cited:   []string{"5e6f7a8b", "1a2b3c4d"},
+			unknown: []string{},
+		},
+		{
+			name:    "case insensitive",
+			text:    "[5E6F7A8B]",
+			cited:   []string{"5e6f7a8b"},
+			unknown: []string{},
+		},
+		{
+			name:    "unique prefix",
+			text:    "[5e6f]",
+			cited:   []string{"5e6f7a8b"},
+			unknown: []string{},
+		},
+		{
+			name:    "ambiguous prefix",
+			text:    "[1a2b]",
+			cited:   []string{},
+			unknown: []string{"1a2b"},
+		},
+		{
+			name:    "short prefix",
+			text:    "[5e6]",
+			cited:   []string{},
+			unknown: []string{"5e6"},
+		},
+		{
+			name:    "a source is cited once",
+			text:    "[5e6f7a8b] [5e6f] [ffffffff]",
+			cited:   []string{"5e6f7a8b"},
+			unknown: []string{"ffffffff"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cited, unknown := ResolveCitations(tt.text, sources)
+
+			ids := []string{}
+			for _, s := range cited {
+				ids = append(ids, s.ID)
+			}
+
+			if !slices.Equal(ids, tt.cited) {
+				t.Errorf("cited %v, want %v", ids, tt.cited)
+			}
+
+			if !slices.Equal(unknown, tt.unknown) {
+				t.Errorf("unknown %v, want %v", unknown, tt.unknown)
+			}
+		})
+	}
+}
+
+func TestStripCitations(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "citations",
+			text: "Bolts take 10 Nm [1a2b3c4d]. Chains need oil [5e6f7a8b, 9c0d1e2f].",
+			want: "Bolts take 10 Nm . Chains need oil .",
+		},
+		{
+			name: "links and prose are kept",
+			text: "Read [the docs](https://example.com) [in full]",
+			want: "Read [the docs](https://example.com) [in full]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripCitations(tt.text); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/core/agent/bootstrap"
+	"github.com/agent-api/examples/internal/agentrun"
+	"github.com/agent-api/examples/rag"
+	"github.com/agent-api/examples/vectorstorer"
+	"github.com/agent-api/openai"
+	"github.com/agent-api/openai/models"
+)
+
+// spells are the documents answered from, keyed by their source
+var spells = map[string]string{
+	"spells/fire-bolt.md":     "Fire Bolt - Cantrip - 120ft - You hurl a mote of fire at a creature or an object within range. Make a ranged spell attack against the target. On a hit, the target takes 1d10 Fire damage. A flammable object hit by this spell starts burning if it isn’t being worn or carried.",
+	"spells/fireball.md":      "Fireball - Level 3 - 150ft - A bright streak flashes from your pointing finger to a point you choose and then blossoms into an explosion of flame. Each creature in a 20-foot-radius sphere makes a Dexterity saving throw, taking 8d6 Fire damage on a failed save.",
+	"spells/ray-of-frost.md":  "Ray of Frost - Cantrip - 60ft - A frigid beam of blue-white light streaks toward a creature within range. On a hit, it takes 1d8 Cold damage, and its speed is reduced by 10 feet until the start of your next turn.",
+	"rules/spell-attacks.md":  "Spell Attacks - Some spells require the caster to make an attack roll. Your attack bonus with a spell attack equals your spellcasting ability modifier plus your proficiency bonus.",
+	"rules/cantrip-damage.md": "Cantrip Damage - A damaging cantrip's damage increases by one die when the caster reaches 5th level, 11th level and 17th level.",
+}
+
+func main() {
+	ctx := context.Background()
+
+	// Create a zap logger
+	config := zap.NewDevelopmentConfig()
+	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	zLogger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a logr.Logger using zapr adapter
+	logger := zapr.NewLogger(zLogger)
+
+	// Create an openai provider
+	provider := openai.NewProvider(&openai.ProviderOpts{
+		Logger: &logger,
+	})
+	provider.UseModel(ctx, models.GPT4_O)
+
+	embedder := vectorstorer.NewOpenAIEmbedder(&vectorstorer.OpenAIEmbedderOpts{
+		Logger: &logger,
+	})
+
+	store, err := vectorstorer.NewMemoryStore(&vectorstorer.MemoryStoreOpts{
+		Embedder: embedder,
+		Logger:   &logger,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// Store each document under its content hash, with its source, so it is
+	// cited by the same ID on every run
+	sources := []string{}
+	contents := []string{}
+	for source, content := range spells {
+		sources = append(sources, source)
+		contents = append(contents, content)
+	}
+
+	vectors, err := embedder.Embed(ctx, contents)
+	if err != nil {
+		panic(err)
+	}
+
+	embeddings := make([]*vectorstorer.Embedding, len(contents))
+	for i, content := range contents {
+		embeddings[i] = &vectorstorer.Embedding{
+			ID:      vectorstorer.ContentHash(content),
+			Content: content,
+			Vector:  vectors[i],
+			Metadata: map[string]any{
+				vectorstorer.MetadataSource: sources[i],
+			},
+		}
+	}
+
+	err = store.Upsert(ctx, embeddings)
+	if err != nil {
+		panic(err)
+	}
+
+	// Every question is answered by a new agent
+	newAgent := func() (agentrun.Runner, error) {
+		return agent.NewAgent(
+			bootstrap.WithProvider(provider),
+			bootstrap.WithLogger(&logger),
+		)
+	}
+
+	pipeline, err := rag.NewPipeline(&rag.PipelineOpts{
+		Store:    store,
+		NewAgent: newAgent,
+		Limit:    3,
+		Logger:   &logger,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	answer, err := pipeline.Answer(ctx, "How does the Fire Bolt spell work?")
+	if err != nil {
+		logger.Error(err, "failed answering question")
+		return
+	}
+
+	fmt.Println("Agent response:", answer.Content)
+
+	fmt.Println("Sources:")
+	for _, source := range answer.Citations {
+		fmt.Printf("  [%s] %s (score %.3f)\n", source.ID, source.Document(), source.Score)
+	}
+
+	for _, id := range answer.UnknownCitations {
+		fmt.Printf("  [%s] does not match a retrieved source\n", id)
+	}
+}
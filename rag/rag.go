@@ -0,0 +1,251 @@
+// Package rag answers questions from retrieved chunks and reports which of
+// them the answer cites.
+//
+// Each retrieved chunk is injected into the prompt under a short ID derived
+// from its stored embedding ID, so the same chunk is cited the same way on
+// every run. The model is instructed to cite the IDs in square brackets and
+// the citations are parsed back into structured sources.
+package rag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/examples/internal/agentrun"
+	"github.com/agent-api/examples/vectorstorer"
+)
+
+// Source is a retrieved chunk as it was shown to the model.
+type Source struct {
+	// ID is the citation ID the model was given for the chunk
+	ID string
+
+	Embedding *vectorstorer.Embedding
+
+	// Score is the search score the chunk was retrieved with
+	Score float64
+}
+
+// Document returns the document the chunk was ingested from, i.e., its
+// vectorstorer.MetadataSource, or an empty string if unknown.
+func (s *Source) Document() string {
+	document, _ := s.Embedding.Metadata[vectorstorer.MetadataSource].(string)
+	return document
+}
+
+// Answer is the outcome of a grounded run.
+type Answer struct {
+	// Content is the final message's content, citations included
+	Content string
+
+	// Citations are the retrieved sources cited by the answer, in the order
+	// they are first cited
+	Citations []*Source
+
+	// Retrieved are all sources injected into the prompt, in search order
+	Retrieved []*Source
+
+	// UnknownCitations are cited IDs that match no retrieved source
+	UnknownCitations []string
+
+	// Messages are the agent's messages for the run
+	Messages []*core.Message
+}
+
+// FinalMessage returns the last message produced by the agent.
+func (a *Answer) FinalMessage() *core.Message {
+	if len(a.Messages) == 0 {
+		return nil
+	}
+
+	return a.Messages[len(a.Messages)-1]
+}
+
+// PipelineOpts configures a new Pipeline.
+type PipelineOpts struct {
+	// Store is searched for the chunks to answer from
+	Store vectorstorer.VectorStorer
+
+	// NewAgent creates the agent that generates an answer. A new agent is
+	// created per question, so no question sees the sources of another.
+	NewAgent func() (agentrun.Runner, error)
+
+	// Limit is the number of chunks retrieved. Defaults to 5.
+	Limit int
+
+	// Namespace restricts retrieval to a single namespace when set
+	Namespace string
+
+	Logger *logr.Logger
+}
+
+// Pipeline retrieves chunks for a question and runs an agent to answer it
+// with citations.
+type Pipeline struct {
+	store     vectorstorer.VectorStorer
+	newAgent  func() (agentrun.Runner, error)
+	limit     int
+	namespace string
+
+	logger *logr.Logger
+}
+
+// NewPipeline creates a new Pipeline.
+func NewPipeline(opts *PipelineOpts) (*Pipeline, error) {
+	if opts.Store == nil || opts.NewAgent == nil {
+		return nil, errors.New("rag pipeline requires a store and an agent constructor")
+	}
+
+	if opts.Limit <= 0 {
+		opts.Limit = 5
+	}
+
+	return &Pipeline{
+		store:     opts.Store,
+		newAgent:  opts.NewAgent,
+		limit:     opts.Limit,
+		namespace: opts.Namespace,
+		logger:    opts.Logger,
+	}, nil
+}
+
+// Retrieve searches the store for the question and assigns each result its
+// citation ID.
+func (p *Pipeline) Retrieve(ctx context.Context, question string) ([]*Source, error) {
+	results, err := p.store.Search(ctx, &vectorstorer.SearchParams{
+		Query:     question,
+		Limit:     p.limit,
+		Namespace: p.namespace,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving sources: %w", err)
+	}
+
+	return NewSources(results), nil
+}
+
+// Answer retrieves sources for the question, runs the agent with them and
+// parses the citations out of its final message. Any opts are passed on to
+// the agent's run before the grounded input, which replaces any input they
+// set.
+func (p *Pipeline) Answer(ctx context.Context, question string, opts ...agent.RunOptionFunc) (*Answer, error) {
+	answer, _, err := p.answer(ctx, question, opts)
+	return answer, err
+}
+
+// Run answers the input of the run options, making the Pipeline an
+// agentrun.Runner, i.e., a router route. The final message of the
+// returned aggregator is the answer, citations included.
+func (p *Pipeline) Run(ctx context.Context, opts ...agent.RunOptionFunc) (*agent.AgentRunAggregator, error) {
+	runOpts := &agent.RunOptions{}
+	for _, opt := range opts {
+		opt(runOpts)
+	}
+
+	_, agg, err := p.answer(ctx, runOpts.Input, opts)
+	return agg, err
+}
+
+func (p *Pipeline) answer(ctx context.Context, question string, opts []agent.RunOptionFunc) (*Answer, *agent.AgentRunAggregator, error) {
+	sources, err := p.Retrieve(ctx, question)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if p.logger != nil {
+		p.logger.V(1).Info("retrieved sources", "question", question, "sources", len(sources))
+	}
+
+	runner, err := p.newAgent()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating agent: %w", err)
+	}
+
+	runOpts := append(append([]agent.RunOptionFunc{}, opts...), agent.WithInput(Prompt(question, sources)))
+	agg, err := runner.Run(ctx, runOpts...)
+
+	answer := &Answer{
+		Retrieved: sources,
+	}
+
+	if agg != nil {
+		answer.Messages = agg.Messages
+	}
+
+	if final := answer.FinalMessage(); final != nil {
+		answer.Content = final.Content
+		answer.Citations, answer.UnknownCitations = ResolveCitations(final.Content, sources)
+	}
+
+	return answer, agg, err
+}
+
+// sourceIDLength is the length citation IDs are shortened to. Short IDs are
+// copied by models more reliably than full content hashes.
+const sourceIDLength = 8
+
+// NewSources assigns citation IDs to search results: the first 8 characters
+// of each embedding's ID, or the full ID where that would be ambiguous.
+func NewSources(results []*vectorstorer.SearchResult) []*Source {
+	short := make(map[string]int, len(results))
+	for _, r := range results {
+		short[shortID(r.Embedding.ID)]++
+	}
+
+	sources := make([]*Source, len(results))
+	for i, r := range results {
+		id := shortID(r.Embedding.ID)
+		if short[id] > 1 {
+			id = r.Embedding.ID
+		}
+
+		sources[i] = &Source{
+			ID:        id,
+			Embedding: r.Embedding,
+			Score:     r.Score,
+		}
+	}
+
+	return sources
+}
+
+func shortID(id string) string {
+	if len(id) <= sourceIDLength {
+		return id
+	}
+
+	return id[:sourceIDLength]
+}
+
+const groundedPrompt string = `Answer the question using only the sources below. After every sentence that uses a source, cite the source's ID in square brackets, i.e., [%s]. Cite several sources as [id1][id2]. If the sources do not contain the answer, say so and cite nothing.
+
+Sources:
+%s
+Question: %s`
+
+// Prompt builds the grounded input for a question: the citation
+// instructions, then each source under its ID.
+func Prompt(question string, sources []*Source) string {
+	example := "1a2b3c4d"
+	if len(sources) > 0 {
+		example = sources[0].ID
+	}
+
+	var sb strings.Builder
+	for _, s := range sources {
+		fmt.Fprintf(&sb, "[%s]", s.ID)
+		if document := s.Document(); document != "" {
+			fmt.Fprintf(&sb, " (%s)", document)
+		}
+
+		fmt.Fprintf(&sb, "\n%s\n\n", strings.TrimSpace(s.Embedding.Content))
+	}
+
+	return fmt.Sprintf(groundedPrompt, example, sb.String(), question)
+}
@@ -0,0 +1,101 @@
+package rag
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/examples/internal/agentrun"
+	"github.com/agent-api/examples/vectorstorer"
+)
+
+// echoRunner answers every run by citing the first source of its input.
+type echoRunner struct {
+	input string
+}
+
+func (r *echoRunner) Run(ctx context.Context, opts ...agent.RunOptionFunc) (*agent.AgentRunAggregator, error) {
+	runOpts := &agent.RunOptions{}
+	for _, opt := range opts {
+		opt(runOpts)
+	}
+
+	r.input = runOpts.Input
+
+	// the first source's ID follows "Sources:\n["
+	_, sources, _ := strings.Cut(runOpts.Input, "Sources:\n[")
+	id, _, _ := strings.Cut(sources, "]")
+
+	agg := agent.NewAgentRunAggregator()
+	agg.Messages = append(agg.Messages, &core.Message{
+		Role:    core.AssistantMessageRole,
+		Content: "It is a cantrip [" + id + "].",
+	})
+
+	return agg, nil
+}
+
+func TestPipelineRun(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := vectorstorer.NewMemoryStore(&vectorstorer.MemoryStoreOpts{
+		Embedder: vectorstorer.NewHashEmbedder(&vectorstorer.HashEmbedderOpts{}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Add(ctx, []string{
+		"Fire Bolt is a cantrip with a range of 120 feet",
+		"Fireball is a third level spell",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	runners := []*echoRunner{}
+	newAgent := func() (agentrun.Runner, error) {
+		runners = append(runners, &echoRunner{})
+		return runners[len(runners)-1], nil
+	}
+
+	pipeline, err := NewPipeline(&PipelineOpts{Store: store, NewAgent: newAgent, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a router passes the question as the run's input
+	agg, err := pipeline.Run(ctx, agent.WithInput("What is Fire Bolt?"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := runners[0].input
+	if !strings.Contains(input, "Fire Bolt is a cantrip") || !strings.HasSuffix(input, "Question: What is Fire Bolt?") {
+		t.Errorf("agent was not run with the grounded input: %q", input)
+	}
+
+	answer, err := pipeline.Answer(ctx, "What is Fire Bolt?")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(runners) != 2 {
+		t.Errorf("created %d agents for 2 questions, want 2", len(runners))
+	}
+
+	if got := agg.Messages[len(agg.Messages)-1].Content; got != answer.Content {
+		t.Errorf("run answered %q, want %q", got, answer.Content)
+	}
+
+	cited := []string{}
+	for _, s := range answer.Citations {
+		cited = append(cited, s.Embedding.Content)
+	}
+
+	if !slices.Equal(cited, []string{"Fire Bolt is a cantrip with a range of 120 feet"}) {
+		t.Errorf("got citations %v", cited)
+	}
+}
@@ -0,0 +1,108 @@
+package rerank
+
+import (
+	"context"
+
+	"github.com/agent-api/examples/vectorstorer"
+)
+
+// LexicalReranker scores results by the overlap of their content with the
+// query's terms, with a bonus for query bigrams that appear as is. It runs
+// locally and needs no model.
+type LexicalReranker struct{}
+
+// NewLexicalReranker returns a new LexicalReranker.
+func NewLexicalReranker() *LexicalReranker {
+	return &LexicalReranker{}
+}
+
+// Rerank scores each result in [0, 1]: the fraction of distinct query terms,
+// other than stop words, in its content, weighted 3 to 1 against the
+// fraction of query bigrams.
+func (l *LexicalReranker) Rerank(ctx context.Context, query string, results []*vectorstorer.SearchResult) ([]*vectorstorer.SearchResult, error) {
+	queryTerms := vectorstorer.Tokenize(query)
+	terms := unique(withoutStopWords(queryTerms))
+	bigrams := unique(bigramsOf(queryTerms))
+
+	scores := make([]float64, len(results))
+	for i, r := range results {
+		contentTerms := vectorstorer.Tokenize(r.Embedding.Content)
+		scores[i] = overlap(terms, contentTerms)
+
+		if len(bigrams) > 0 {
+			scores[i] = 0.75*scores[i] + 0.25*overlap(bigrams, bigramsOf(contentTerms))
+		}
+	}
+
+	return rescore(results, scores), nil
+}
+
+// overlap returns the fraction of want found in have.
+func overlap(want, have []string) float64 {
+	if len(want) == 0 {
+		return 0
+	}
+
+	present := make(map[string]bool, len(have))
+	for _, h := range have {
+		present[h] = true
+	}
+
+	found := 0
+	for _, w := range want {
+		if present[w] {
+			found++
+		}
+	}
+
+	return float64(found) / float64(len(want))
+}
+
+func bigramsOf(terms []string) []string {
+	bigrams := []string{}
+	for i := 0; i+1 < len(terms); i++ {
+		bigrams = append(bigrams, terms[i]+" "+terms[i+1])
+	}
+
+	return bigrams
+}
+
+func unique(items []string) []string {
+	seen := map[string]bool{}
+	out := []string{}
+
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			out = append(out, item)
+		}
+	}
+
+	return out
+}
+
+// stopWords are common English words that carry no relevance signal.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "do": true, "does": true, "for": true, "from": true,
+	"how": true, "i": true, "in": true, "is": true, "it": true, "of": true,
+	"on": true, "or": true, "that": true, "the": true, "to": true, "was": true,
+	"what": true, "when": true, "where": true, "which": true, "who": true,
+	"why": true, "with": true,
+}
+
+func withoutStopWords(terms []string) []string {
+	out := []string{}
+	for _, t := range terms {
+		if !stopWords[t] {
+			out = append(out, t)
+		}
+	}
+
+	// a query of only stop words still needs something to match
+	if len(out) == 0 {
+		return terms
+	}
+
+	return out
+}
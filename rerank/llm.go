@@ -0,0 +1,88 @@
+package rerank
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/examples/internal/llmjson"
+	"github.com/agent-api/examples/vectorstorer"
+)
+
+// LLMReranker asks a model, as a judge, to rate how relevant each result is
+// to the query. All results are rated in a single request.
+type LLMReranker struct {
+	provider core.Provider
+}
+
+// NewLLMReranker returns a reranker that uses the given provider. The
+// provider should already have a model set via UseModel.
+func NewLLMReranker(provider core.Provider) *LLMReranker {
+	return &LLMReranker{
+		provider: provider,
+	}
+}
+
+const llmRerankerPrompt string = `You are a search relevance judge. Rate how relevant each passage below is to the query, from 0 (unrelated) to 10 (fully answers the query).
+
+Query:
+%s
+
+Passages:
+%s
+Respond ONLY with a JSON object mapping each passage number to its rating, i.e., {"1": 7, "2": 0}.`
+
+// Rerank scores each result with the model's rating scaled to [0, 1].
+// Passages the model did not rate score 0.
+func (l *LLMReranker) Rerank(ctx context.Context, query string, results []*vectorstorer.SearchResult) ([]*vectorstorer.SearchResult, error) {
+	if len(results) == 0 {
+		return results, nil
+	}
+
+	var passages strings.Builder
+	for i, r := range results {
+		fmt.Fprintf(&passages, "[%d] %s\n\n", i+1, strings.TrimSpace(r.Embedding.Content))
+	}
+
+	resp, err := l.provider.Generate(ctx, &core.GenerateOptions{
+		Messages: []*core.Message{
+			{
+				Role:    core.UserMessageRole,
+				Content: fmt.Sprintf(llmRerankerPrompt, query, passages.String()),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error generating relevance ratings: %w", err)
+	}
+
+	data, ok := llmjson.Extract(resp.Content, '{', '}')
+	if !ok {
+		return nil, fmt.Errorf("relevance ratings were not JSON: %s", resp.Content)
+	}
+
+	ratings := map[string]json.Number{}
+	if err := json.Unmarshal([]byte(data), &ratings); err != nil {
+		return nil, fmt.Errorf("error unmarshaling relevance ratings: %w", err)
+	}
+
+	scores := make([]float64, len(results))
+	for key, rating := range ratings {
+		i, err := strconv.Atoi(strings.Trim(key, "[] "))
+		if err != nil || i < 1 || i > len(results) {
+			continue
+		}
+
+		value, err := rating.Float64()
+		if err != nil {
+			continue
+		}
+
+		scores[i-1] = min(max(value, 0), 10) / 10
+	}
+
+	return rescore(results, scores), nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/core/agent/bootstrap"
+	"github.com/agent-api/examples/rerank"
+	"github.com/agent-api/examples/vectorstorer"
+	"github.com/agent-api/ollama"
+	"github.com/agent-api/ollama/models"
+)
+
+func main() {
+	ctx := context.Background()
+
+	// Create a zap logger
+	config := zap.NewDevelopmentConfig()
+	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	zLogger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a logr.Logger using zapr adapter
+	logger := zapr.NewLogger(zLogger)
+
+	// Create an ollama provider, used both as the relevance judge and to
+	// answer
+	provider := ollama.NewProvider(&ollama.ProviderOpts{
+		Logger:  &logger,
+		BaseURL: "http://localhost",
+		Port:    11434,
+	})
+	provider.UseModel(ctx, models.QWEN2_5_LATEST)
+
+	memory, err := vectorstorer.NewMemoryStore(&vectorstorer.MemoryStoreOpts{
+		Embedder: vectorstorer.NewOllamaEmbedder(&vectorstorer.OllamaEmbedderOpts{
+			Logger: &logger,
+		}),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = memory.Add(ctx, []string{
+		"Fire Bolt - Cantrip - 120ft - You hurl a mote of fire at a creature or an object within range. Make a ranged spell attack against the target. On a hit, the target takes 1d10 Fire damage.",
+		"Produce Flame - Cantrip - Self - A flickering flame appears in your hand. You can hurl the flame at a creature within 60 feet, dealing 1d8 Fire damage.",
+		"Fireball - Level 3 - 150ft - A bright streak flashes from your pointing finger to a point you choose and then blossoms into an explosion of flame, dealing 8d6 Fire damage.",
+		"Ray of Frost - Cantrip - 60ft - A frigid beam of blue-white light streaks toward a creature within range. On a hit, it takes 1d8 Cold damage.",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// Retrieve the top 20 by similarity, let the model judge them and keep
+	// the top 2. Use rerank.NewLexicalReranker() to rerank without a model.
+	store, err := rerank.NewStore(&rerank.StoreOpts{
+		Store:      memory,
+		Reranker:   rerank.NewLLMReranker(provider),
+		Candidates: 20,
+		Limit:      2,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	question := "How does the Fire Bolt spell work?"
+
+	results, err := store.Search(ctx, &vectorstorer.SearchParams{
+		Query: question,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	var retrieved strings.Builder
+	for _, r := range results {
+		fmt.Printf("rerank %.2f (retrieval %.3f): %s\n", r.Score, r.RetrievalScore, r.Embedding.Content[:40])
+		fmt.Fprintf(&retrieved, "- %s\n", r.Embedding.Content)
+	}
+
+	myAgent, err := agent.NewAgent(
+		bootstrap.WithProvider(provider),
+		bootstrap.WithLogger(&logger),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	// The agent only sees its input, so the reranked context goes there
+	response, err := myAgent.Run(
+		ctx,
+		agent.WithInput("Answer the question using only the following context:\n"+retrieved.String()+"\nQuestion: "+question),
+	)
+	if err != nil {
+		logger.Error(err, "failed sending message to agent")
+		return
+	}
+
+	fmt.Println("Agent response:", response.Messages[len(response.Messages)-1].Content)
+}
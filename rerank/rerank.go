@@ -0,0 +1,113 @@
+// Package rerank rescores the results of a vector search with a second,
+// more precise stage before they are handed to a model.
+package rerank
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/agent-api/examples/vectorstorer"
+)
+
+// Reranker rescores search results against a query. It returns the results
+// sorted by their new Score, with the retrieval score kept as
+// RetrievalScore.
+type Reranker interface {
+	Rerank(ctx context.Context, query string, results []*vectorstorer.SearchResult) ([]*vectorstorer.SearchResult, error)
+}
+
+// StoreOpts configures a new reranking Store.
+type StoreOpts struct {
+	// Store is searched for candidates
+	Store vectorstorer.VectorStorer
+
+	Reranker Reranker
+
+	// Candidates is the number of results retrieved and reranked, the top N.
+	// Defaults to 20.
+	Candidates int
+
+	// Limit is the number of reranked results returned, the top K, when a
+	// search does not set its own limit. Defaults to 5.
+	Limit int
+}
+
+// Store is a vectorstorer.VectorStorer whose searches retrieve the top N
+// candidates from the wrapped store, rerank them and return the top K.
+type Store struct {
+	vectorstorer.VectorStorer
+
+	reranker   Reranker
+	candidates int
+	limit      int
+}
+
+// NewStore creates a new reranking Store.
+func NewStore(opts *StoreOpts) (*Store, error) {
+	if opts.Store == nil || opts.Reranker == nil {
+		return nil, errors.New("reranking store requires a store and a reranker")
+	}
+
+	if opts.Candidates <= 0 {
+		opts.Candidates = 20
+	}
+
+	if opts.Limit <= 0 {
+		opts.Limit = 5
+	}
+
+	return &Store{
+		VectorStorer: opts.Store,
+		reranker:     opts.Reranker,
+		candidates:   opts.Candidates,
+		limit:        opts.Limit,
+	}, nil
+}
+
+// Search retrieves candidates, reranks them and truncates them to the
+// search's limit.
+func (s *Store) Search(ctx context.Context, params *vectorstorer.SearchParams) ([]*vectorstorer.SearchResult, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = s.limit
+	}
+
+	candidateParams := *params
+	candidateParams.Limit = max(s.candidates, limit)
+
+	candidates, err := s.VectorStorer.Search(ctx, &candidateParams)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.reranker.Rerank(ctx, params.Query, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// rescore returns copies of the results with their new scores, sorted by
+// descending score. Ties keep their retrieval order.
+func rescore(results []*vectorstorer.SearchResult, scores []float64) []*vectorstorer.SearchResult {
+	out := make([]*vectorstorer.SearchResult, len(results))
+	for i, r := range results {
+		out[i] = &vectorstorer.SearchResult{
+			Embedding:      r.Embedding,
+			Score:          scores[i],
+			RetrievalScore: r.Score,
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Score > out[j].Score
+	})
+
+	return out
+}
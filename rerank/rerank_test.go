@@ -0,0 +1,266 @@
+package rerank
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/examples/vectorstorer"
+)
+
+// candidates are search results in retrieval order, best first.
+func candidates(contents ...string) []*vectorstorer.SearchResult {
+	results := make([]*vectorstorer.SearchResult, len(contents))
+	for i, c := range contents {
+		results[i] = &vectorstorer.SearchResult{
+			Embedding: &vectorstorer.Embedding{ID: c, Content: c},
+			Score:     1 - float64(i)/10,
+		}
+	}
+
+	return results
+}
+
+func ids(results []*vectorstorer.SearchResult) []string {
+	out := []string{}
+	for _, r := range results {
+		out = append(out, r.Embedding.ID)
+	}
+
+	return out
+}
+
+func TestLexicalReranker(t *testing.T) {
+	results := candidates(
+		"Fireball explodes in a bright streak of flame",
+		"Ray of Frost is a frigid beam of light",
+		"Fire Bolt hurls a mote of fire",
+		"A bolt of fire",
+	)
+
+	reranked, err := NewLexicalReranker().Rerank(context.Background(), "How does the Fire Bolt spell work?", results)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// both bolt passages match "fire" and "bolt", but only the first matches
+	// the bigram "fire bolt"; ties keep their retrieval order
+	want := []string{results[2].Embedding.ID, results[3].Embedding.ID, results[0].Embedding.ID, results[1].Embedding.ID}
+	if got := ids(reranked); !slices.Equal(got, want) {
+		t.Errorf("got order %q, want %q", got, want)
+	}
+
+	for _, r := range reranked {
+		if r.Score < 0 || r.Score > 1 {
+			t.Errorf("%q has score %f out of [0, 1]", r.Embedding.ID, r.Score)
+		}
+	}
+
+	if reranked[0].RetrievalScore != results[2].Score {
+		t.Errorf("got retrieval score %f, want %f", reranked[0].RetrievalScore, results[2].Score)
+	}
+
+	if results[0].Score != 1 {
+		t.Error("reranking modified the candidates")
+	}
+}
+
+// judgeProvider answers every request with its content.
+type judgeProvider struct {
+	content string
+	err     error
+	prompt  string
+}
+
+func (p *judgeProvider) GetCapabilities(ctx context.Context) (*core.Capabilities, error) {
+	return &core.Capabilities{}, nil
+}
+
+func (p *judgeProvider) UseModel(ctx context.Context, model *core.Model) error {
+	return nil
+}
+
+func (p *judgeProvider) Generate(ctx context.Context, opts *core.GenerateOptions) (*core.Message, error) {
+	p.prompt = opts.Messages[len(opts.Messages)-1].Content
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return &core.Message{Role: core.AssistantMessageRole, Content: p.content}, nil
+}
+
+func (p *judgeProvider) GenerateStream(ctx context.Context, opts *core.GenerateOptions) (<-chan *core.Message, <-chan string, <-chan error) {
+	return nil, nil, nil
+}
+
+func TestLLMReranker(t *testing.T) {
+	results := candidates("first", "second", "third", "fourth")
+
+	tests := []struct {
+		name       string
+		provider   *judgeProvider
+		wantOrder  []string
+		wantScores []float64
+		wantErr    bool
+	}{
+		{
+			name: "ratings in a code fence",
+			provider: &judgeProvider{
+				content: "Here are the ratings:\n```json\n{\"1\": 2, \"[2]\": 9, \"3\": 15, \"9\": 10}\n```",
+			},
+			// "3" is clamped to 10, "9" is not a passage and "4" is unrated
+			wantOrder:  []string{"third", "second", "first", "fourth"},
+			wantScores: []float64{1, 0.9, 0.2, 0},
+		},
+		{
+			name:     "no JSON",
+			provider: &judgeProvider{content: "They are all relevant."},
+			wantErr:  true,
+		},
+		{
+			name:     "provider error",
+			provider: &judgeProvider{err: errors.New("unavailable")},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reranked, err := NewLLMReranker(tt.provider).Rerank(context.Background(), "which passage?", results)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(tt.provider.prompt, "which passage?") || !strings.Contains(tt.provider.prompt, "[4] fourth") {
+				t.Errorf("prompt is missing the query or passages: %q", tt.provider.prompt)
+			}
+
+			if got := ids(reranked); !slices.Equal(got, tt.wantOrder) {
+				t.Errorf("got order %q, want %q", got, tt.wantOrder)
+			}
+
+			for i, r := range reranked {
+				if r.Score != tt.wantScores[i] {
+					t.Errorf("%q has score %f, want %f", r.Embedding.ID, r.Score, tt.wantScores[i])
+				}
+			}
+		})
+	}
+}
+
+// recordingStore returns its results and records the searches' params.
+type recordingStore struct {
+	vectorstorer.VectorStorer
+
+	results []*vectorstorer.SearchResult
+	params  []*vectorstorer.SearchParams
+}
+
+func (s *recordingStore) Search(ctx context.Context, params *vectorstorer.SearchParams) ([]*vectorstorer.SearchResult, error) {
+	s.params = append(s.params, params)
+	return s.results[:min(params.Limit, len(s.results))], nil
+}
+
+// reverseReranker reverses the retrieval order.
+type reverseReranker struct{}
+
+func (reverseReranker) Rerank(ctx context.Context, query string, results []*vectorstorer.SearchResult) ([]*vectorstorer.SearchResult, error) {
+	scores := make([]float64, len(results))
+	for i := range results {
+		scores[i] = float64(i)
+	}
+
+	return rescore(results, scores), nil
+}
+
+func TestStoreSearch(t *testing.T) {
+	inner := &recordingStore{results: candidates("a", "b", "c", "d", "e")}
+
+	store, err := NewStore(&StoreOpts{
+		Store:      inner,
+		Reranker:   reverseReranker{},
+		Candidates: 4,
+		Limit:      2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		params        *vectorstorer.SearchParams
+		want          []string
+		wantRetrieved int
+	}{
+		{
+			name:          "default limit",
+			params:        &vectorstorer.SearchParams{Query: "q", Namespace: "tenant"},
+			want:          []string{"d", "c"},
+			wantRetrieved: 4,
+		},
+		{
+			name:          "limit above the candidates",
+			params:        &vectorstorer.SearchParams{Query: "q", Limit: 5},
+			want:          []string{"e", "d", "c", "b", "a"},
+			wantRetrieved: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := store.Search(context.Background(), tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := ids(results); !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+
+			params := inner.params[len(inner.params)-1]
+			if params.Limit != tt.wantRetrieved || params.Namespace != tt.params.Namespace {
+				t.Errorf("retrieved with %+v", params)
+			}
+		})
+	}
+
+	if _, err := NewStore(&StoreOpts{Store: inner}); err == nil {
+		t.Error("expected an error for a store without a reranker")
+	}
+}
+
+func TestStoreSearchTool(t *testing.T) {
+	store, err := NewStore(&StoreOpts{
+		Store:    &recordingStore{results: candidates("a", "b")},
+		Reranker: reverseReranker{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tool, err := vectorstorer.NewSearchTool(&vectorstorer.SearchToolOpts{Store: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := tool.WrappedToolFunction(context.Background(), []byte(`{"query":"q"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the model sees both the reranked and the retrieval scores
+	found := out.([]*vectorstorer.SearchToolResult)
+	if len(found) != 2 || found[0].ID != "b" || found[0].Score != 1 || found[0].RetrievalScore != 0.9 {
+		t.Errorf("got results %+v", found)
+	}
+}
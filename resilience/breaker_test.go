@@ -0,0 +1,83 @@
+package resilience
+
+import (
+	"testing"
+	"time"
+)
+
+// expire backdates an open breaker so that its cooldown has passed.
+func (b *breaker) expire() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.openedAt = b.openedAt.Add(-b.cooldown)
+}
+
+func checkState(t *testing.T, b *breaker, want BreakerState, wantFailures int) {
+	t.Helper()
+
+	state, failures, _ := b.snapshot()
+	if state != want || failures != wantFailures {
+		t.Errorf("got state %s with %d failures, want %s with %d", state, failures, want, wantFailures)
+	}
+}
+
+func TestBreakerTransitions(t *testing.T) {
+	b := newBreaker(2, time.Minute)
+
+	if !b.allow() {
+		t.Fatal("closed breaker rejected a request")
+	}
+
+	b.failure()
+	checkState(t, b, BreakerClosed, 1)
+
+	// a success resets the consecutive failures
+	b.success()
+	checkState(t, b, BreakerClosed, 0)
+
+	b.failure()
+	b.failure()
+	checkState(t, b, BreakerOpen, 2)
+
+	if b.allow() {
+		t.Fatal("open breaker allowed a request before its cooldown")
+	}
+
+	b.expire()
+
+	if !b.allow() {
+		t.Fatal("open breaker rejected the trial request after its cooldown")
+	}
+	checkState(t, b, BreakerHalfOpen, 2)
+
+	if b.allow() {
+		t.Fatal("half open breaker allowed a second trial request")
+	}
+
+	// a single failed trial re-opens the breaker
+	b.failure()
+	checkState(t, b, BreakerOpen, 3)
+
+	if b.allow() {
+		t.Fatal("re-opened breaker allowed a request before its cooldown")
+	}
+
+	b.expire()
+	b.allow()
+
+	// a released trial lets the next request through as the trial
+	b.release()
+	checkState(t, b, BreakerHalfOpen, 3)
+
+	if !b.allow() {
+		t.Fatal("half open breaker rejected a trial after a release")
+	}
+
+	b.success()
+	checkState(t, b, BreakerClosed, 0)
+
+	if !b.allow() {
+		t.Fatal("closed breaker rejected a request")
+	}
+}
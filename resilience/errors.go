@@ -0,0 +1,178 @@
+// Package resilience provides core.Provider wrappers that keep agents running
+// when an LLM provider is unavailable, overloaded or rate limited.
+package resilience
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/openai/openai-go"
+)
+
+// ErrorClass categorizes provider errors by whether they are worth retrying
+// or failing over.
+type ErrorClass int
+
+const (
+	// ErrorClassPermanent errors will fail again if retried as is (bad
+	// requests, authentication errors, canceled contexts, etc.)
+	ErrorClassPermanent ErrorClass = iota
+
+	// ErrorClassConnection errors happen when the provider could not be reached
+	ErrorClassConnection
+
+	// ErrorClassServer errors are 5xx responses from the provider
+	ErrorClassServer
+
+	// ErrorClassRateLimit errors are 429 responses from the provider
+	ErrorClassRateLimit
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorClassConnection:
+		return "connection"
+	case ErrorClassServer:
+		return "server"
+	case ErrorClassRateLimit:
+		return "rate_limit"
+	default:
+		return "permanent"
+	}
+}
+
+// ollama and other plain HTTP clients only surface the status code in their
+// error strings, i.e., "request failed with status 503: ..."
+var statusPattern = regexp.MustCompile(`status(?: code)?:? (\d{3})\b`)
+
+// ClassifyError returns the ErrorClass of an error returned by a provider.
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ErrorClassPermanent
+	}
+
+	// the caller gave up: retrying or failing over won't help
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return ErrorClassPermanent
+	}
+
+	if code, ok := StatusCode(err); ok {
+		switch {
+		case code == 429:
+			return ErrorClassRateLimit
+		case code >= 500:
+			return ErrorClassServer
+		default:
+			return ErrorClassPermanent
+		}
+	}
+
+	if isConnectionError(err) {
+		return ErrorClassConnection
+	}
+
+	return ErrorClassPermanent
+}
+
+// IsTransient returns true when an error is a connection error, a 5xx or a
+// rate limit.
+func IsTransient(err error) bool {
+	return ClassifyError(err) != ErrorClassPermanent
+}
+
+// StatusCode extracts the HTTP status code from a provider error.
+func StatusCode(err error) (int, bool) {
+	var openaiErr *openai.Error
+	if errors.As(err, &openaiErr) {
+		return openaiErr.StatusCode, true
+	}
+
+	var anthropicErr *anthropic.Error
+	if errors.As(err, &anthropicErr) {
+		return anthropicErr.StatusCode, true
+	}
+
+	match := statusPattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0, false
+	}
+
+	code, convErr := strconv.Atoi(match[1])
+	if convErr != nil {
+		return 0, false
+	}
+
+	return code, true
+}
+
+// RetryAfter extracts how long the provider asked clients to wait before
+// retrying from the Retry-After (or retry-after-ms) response headers.
+func RetryAfter(err error) (time.Duration, bool) {
+	var resp *http.Response
+
+	var openaiErr *openai.Error
+	var anthropicErr *anthropic.Error
+	switch {
+	case errors.As(err, &openaiErr):
+		resp = openaiErr.Response
+	case errors.As(err, &anthropicErr):
+		resp = anthropicErr.Response
+	}
+
+	if resp == nil {
+		return 0, false
+	}
+
+	if ms, convErr := strconv.Atoi(resp.Header.Get("retry-after-ms")); convErr == nil && ms >= 0 {
+		return time.Duration(ms) * time.Millisecond, true
+	}
+
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+
+	// Retry-After is either a number of seconds or an HTTP date
+	if seconds, convErr := strconv.Atoi(header); convErr == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, parseErr := http.ParseTime(header); parseErr == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+
+		return wait, true
+	}
+
+	return 0, false
+}
+
+func isConnectionError(err error) bool {
+	if errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// some clients format, rather than wrap, the underlying error
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "connection refused") ||
+		strings.Contains(msg, "connection reset") ||
+		strings.Contains(msg, "no such host")
+}
@@ -0,0 +1,118 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/openai/openai-go"
+)
+
+// openAIError returns an error as the openai client returns it for a
+// response with the given status code and headers.
+func openAIError(code int, header http.Header) error {
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &openai.Error{
+		StatusCode: code,
+		Request:    httptest.NewRequest(http.MethodPost, "https://api.openai.com/v1/chat/completions", nil),
+		Response:   &http.Response{StatusCode: code, Header: header},
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorClass
+	}{
+		{
+			name: "nil",
+			want: ErrorClassPermanent,
+		},
+		{
+			name: "canceled",
+			err:  fmt.Errorf("error generating: %w", context.Canceled),
+			want: ErrorClassPermanent,
+		},
+		{
+			name: "deadline exceeded before the status",
+			err:  fmt.Errorf("status 503: %w", context.DeadlineExceeded),
+			want: ErrorClassPermanent,
+		},
+		{
+			name: "openai rate limit",
+			err:  fmt.Errorf("error generating: %w", openAIError(http.StatusTooManyRequests, nil)),
+			want: ErrorClassRateLimit,
+		},
+		{
+			name: "openai server error",
+			err:  openAIError(http.StatusServiceUnavailable, nil),
+			want: ErrorClassServer,
+		},
+		{
+			name: "anthropic bad request",
+			err: &anthropic.Error{
+				StatusCode: http.StatusBadRequest,
+				Request:    httptest.NewRequest(http.MethodPost, "https://api.anthropic.com/v1/messages", nil),
+				Response:   &http.Response{StatusCode: http.StatusBadRequest},
+			},
+			want: ErrorClassPermanent,
+		},
+		{
+			name: "status in the error string",
+			err:  errors.New("request failed with status 502: bad gateway"),
+			want: ErrorClassServer,
+		},
+		{
+			name: "status code in the error string",
+			err:  errors.New("unexpected status code: 429"),
+			want: ErrorClassRateLimit,
+		},
+		{
+			name: "not found in the error string",
+			err:  errors.New("request failed with status 404: model not found"),
+			want: ErrorClassPermanent,
+		},
+		{
+			name: "connection refused",
+			err:  fmt.Errorf("error calling ollama: %w", syscall.ECONNREFUSED),
+			want: ErrorClassConnection,
+		},
+		{
+			name: "net error",
+			err:  &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("i/o timeout")},
+			want: ErrorClassConnection,
+		},
+		{
+			name: "formatted connection error",
+			err:  errors.New("dial tcp: lookup localhost.invalid: no such host"),
+			want: ErrorClassConnection,
+		},
+		{
+			name: "unknown",
+			err:  errors.New("invalid tool schema"),
+			want: ErrorClassPermanent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyError(tt.err); got != tt.want {
+				t.Errorf("got class %s, want %s", got, tt.want)
+			}
+
+			if got := IsTransient(tt.err); got != (tt.want != ErrorClassPermanent) {
+				t.Errorf("got transient %t for class %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/agent-api/core"
+)
+
+// ServedByProperty is the message metadata provider property set to the name
+// of the provider that actually generated a message.
+const ServedByProperty string = "served_by"
+
+// ErrNoProviderAvailable is returned when every provider in a fallback chain
+// failed or had an open circuit breaker.
+var ErrNoProviderAvailable = errors.New("no provider available")
+
+// FallbackMember is a single provider in a fallback chain.
+type FallbackMember struct {
+	// Name identifies the provider in health reports and message metadata
+	Name string
+
+	Provider core.Provider
+
+	// Model, when set, is passed to the provider's UseModel when the chain is
+	// created. Since models are provider specific, members of a chain usually
+	// each set their own model.
+	Model *core.Model
+}
+
+// FallbackProviderOpts configures a new FallbackProvider.
+type FallbackProviderOpts struct {
+	// Members are tried in order
+	Members []*FallbackMember
+
+	// FailureThreshold is the number of consecutive failures that opens a
+	// provider's circuit breaker. Defaults to 3.
+	FailureThreshold int
+
+	// Cooldown is how long an open circuit breaker skips its provider before
+	// allowing a trial request. Defaults to 30 seconds.
+	Cooldown time.Duration
+
+	// ShouldFailover decides if an error moves on to the next provider.
+	// Defaults to IsTransient: connection errors, 5xx responses and rate limits.
+	ShouldFailover func(err error) bool
+
+	Logger *logr.Logger
+}
+
+// FallbackProvider is a core.Provider that tries an ordered list of providers,
+// failing over to the next one on transient errors.
+type FallbackProvider struct {
+	members        []*fallbackMember
+	shouldFailover func(err error) bool
+
+	logger *logr.Logger
+}
+
+type fallbackMember struct {
+	*FallbackMember
+	breaker *breaker
+
+	mu        sync.Mutex
+	served    int
+	failed    int
+	lastError error
+}
+
+// MemberHealth is a point in time health report of a provider in a chain.
+type MemberHealth struct {
+	Name                string
+	State               BreakerState
+	ConsecutiveFailures int
+	OpenedAt            time.Time
+
+	// Served and Failed are the total number of requests served by and failed
+	// on this provider
+	Served int
+	Failed int
+
+	LastError error
+}
+
+// NewFallbackProvider creates a new FallbackProvider.
+func NewFallbackProvider(ctx context.Context, opts *FallbackProviderOpts) (*FallbackProvider, error) {
+	if len(opts.Members) == 0 {
+		return nil, errors.New("fallback provider must have at least one member")
+	}
+
+	if opts.FailureThreshold == 0 {
+		opts.FailureThreshold = 3
+	}
+
+	if opts.Cooldown == 0 {
+		opts.Cooldown = 30 * time.Second
+	}
+
+	if opts.ShouldFailover == nil {
+		opts.ShouldFailover = IsTransient
+	}
+
+	f := &FallbackProvider{
+		shouldFailover: opts.ShouldFailover,
+		logger:         opts.Logger,
+	}
+
+	for _, m := range opts.Members {
+		if m.Name == "" || m.Provider == nil {
+			return nil, errors.New("fallback member must have a name and a provider")
+		}
+
+		if m.Model != nil {
+			if err := m.Provider.UseModel(ctx, m.Model); err != nil {
+				return nil, fmt.Errorf("error setting model for %s: %w", m.Name, err)
+			}
+		}
+
+		f.members = append(f.members, &fallbackMember{
+			FallbackMember: m,
+			breaker:        newBreaker(opts.FailureThreshold, opts.Cooldown),
+		})
+	}
+
+	return f, nil
+}
+
+// Health returns the current health of every provider in the chain.
+func (f *FallbackProvider) Health() []*MemberHealth {
+	health := make([]*MemberHealth, len(f.members))
+
+	for i, m := range f.members {
+		state, failures, openedAt := m.breaker.snapshot()
+
+		m.mu.Lock()
+		health[i] = &MemberHealth{
+			Name:                m.Name,
+			State:               state,
+			ConsecutiveFailures: failures,
+			OpenedAt:            openedAt,
+			Served:              m.served,
+			Failed:              m.failed,
+			LastError:           m.lastError,
+		}
+		m.mu.Unlock()
+	}
+
+	return health
+}
+
+// GetCapabilities returns the capabilities of the first available provider.
+func (f *FallbackProvider) GetCapabilities(ctx context.Context) (*core.Capabilities, error) {
+	for _, m := range f.members {
+		state, _, _ := m.breaker.snapshot()
+		if state == BreakerOpen {
+			continue
+		}
+
+		return m.Provider.GetCapabilities(ctx)
+	}
+
+	return nil, ErrNoProviderAvailable
+}
+
+// UseModel sets the model on every provider in the chain. Prefer setting
+// FallbackMember.Model since model IDs are rarely shared across providers.
+func (f *FallbackProvider) UseModel(ctx context.Context, model *core.Model) error {
+	errs := []error{}
+	for _, m := range f.members {
+		if err := m.Provider.UseModel(ctx, model); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", m.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Generate tries each available provider in order until one succeeds.
+func (f *FallbackProvider) Generate(ctx context.Context, opts *core.GenerateOptions) (*core.Message, error) {
+	errs := []error{}
+
+	for _, m := range f.members {
+		if !m.breaker.allow() {
+			f.log("skipping provider with open circuit breaker", m)
+			continue
+		}
+
+		msg, err := m.Provider.Generate(ctx, opts)
+		if err == nil {
+			f.recordSuccess(m)
+			return markServedBy(msg, m.Name), nil
+		}
+
+		if !f.shouldFailover(err) {
+			m.breaker.release()
+			return nil, fmt.Errorf("%s: %w", m.Name, err)
+		}
+
+		f.recordFailure(m, err)
+		errs = append(errs, fmt.Errorf("%s: %w", m.Name, err))
+	}
+
+	return nil, errors.Join(append([]error{ErrNoProviderAvailable}, errs...)...)
+}
+
+// GenerateStream tries each available provider in order. A stream only fails
+// over if it errors before emitting any message or delta: once output has been
+// emitted the stream is committed to that provider.
+func (f *FallbackProvider) GenerateStream(ctx context.Context, opts *core.GenerateOptions) (<-chan *core.Message, <-chan string, <-chan error) {
+	// buffered, non-blocking channels
+	outMsgChan := make(chan *core.Message, 10)
+	outDeltaChan := make(chan string, 10)
+	outErrChan := make(chan error, 10)
+
+	go func() {
+		defer close(outMsgChan)
+		defer close(outDeltaChan)
+		defer close(outErrChan)
+
+		errs := []error{}
+
+		for _, m := range f.members {
+			if !m.breaker.allow() {
+				f.log("skipping provider with open circuit breaker", m)
+				continue
+			}
+
+			emitted, err := pipeStream(ctx, m.Provider, opts, outMsgChan, outDeltaChan, func(msg *core.Message) *core.Message {
+				return markServedBy(msg, m.Name)
+			})
+			if err == nil {
+				f.recordSuccess(m)
+				return
+			}
+
+			if errors.Is(err, errStreamUnsupported) {
+				m.breaker.release()
+				errs = append(errs, fmt.Errorf("%s: %w", m.Name, err))
+				continue
+			}
+
+			if emitted || !f.shouldFailover(err) {
+				if f.shouldFailover(err) {
+					f.recordFailure(m, err)
+				} else {
+					m.breaker.release()
+				}
+
+				outErrChan <- fmt.Errorf("%s: %w", m.Name, err)
+				return
+			}
+
+			f.recordFailure(m, err)
+			errs = append(errs, fmt.Errorf("%s: %w", m.Name, err))
+		}
+
+		outErrChan <- errors.Join(append([]error{ErrNoProviderAvailable}, errs...)...)
+	}()
+
+	return outMsgChan, outDeltaChan, outErrChan
+}
+
+func (f *FallbackProvider) recordSuccess(m *fallbackMember) {
+	m.breaker.success()
+
+	m.mu.Lock()
+	m.served++
+	m.mu.Unlock()
+}
+
+func (f *FallbackProvider) recordFailure(m *fallbackMember, err error) {
+	m.breaker.failure()
+
+	m.mu.Lock()
+	m.failed++
+	m.lastError = err
+	m.mu.Unlock()
+
+	if f.logger != nil {
+		f.logger.V(-1).Info("provider request failed", "provider", m.Name, "class", ClassifyError(err).String(), "error", err)
+	}
+}
+
+func (f *FallbackProvider) log(msg string, m *fallbackMember) {
+	if f.logger != nil {
+		f.logger.V(1).Info(msg, "provider", m.Name)
+	}
+}
+
+// markServedBy records the provider name in the message metadata.
+func markServedBy(msg *core.Message, name string) *core.Message {
+	if msg == nil {
+		return nil
+	}
+
+	if msg.Metadata == nil {
+		msg.Metadata = &core.Metadata{}
+	}
+
+	if msg.Metadata.ProviderProperties == nil {
+		msg.Metadata.ProviderProperties = make(map[string]string)
+	}
+
+	msg.Metadata.ProviderProperties[ServedByProperty] = name
+
+	return msg
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/agent-api/anthropic"
+	anthropicmodels "github.com/agent-api/anthropic/models"
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/core/agent/bootstrap"
+	"github.com/agent-api/examples/resilience"
+	"github.com/agent-api/googlegenai"
+	googlegenaimodels "github.com/agent-api/googlegenai/models"
+	"github.com/agent-api/ollama"
+	ollamamodels "github.com/agent-api/ollama/models"
+	"github.com/agent-api/openai"
+	openaimodels "github.com/agent-api/openai/models"
+)
+
+func main() {
+	ctx := context.Background()
+
+	// Create a zap logger
+	config := zap.NewDevelopmentConfig()
+	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	zLogger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a logr.Logger using zapr adapter
+	logger := zapr.NewLogger(zLogger)
+
+	// The anthropic provider logs through log/slog
+	slogger := slog.New(logr.ToSlogHandler(logger))
+
+	// Try the local Ollama server first, then fail over to OpenAI, Anthropic
+	// and finally Google Gen AI if it is down, erroring or rate limited.
+	provider, err := resilience.NewFallbackProvider(ctx, &resilience.FallbackProviderOpts{
+		Logger:           &logger,
+		FailureThreshold: 2,
+		Members: []*resilience.FallbackMember{
+			{
+				Name: "ollama",
+				Provider: ollama.NewProvider(&ollama.ProviderOpts{
+					Logger:  &logger,
+					BaseURL: "http://localhost",
+					Port:    11434,
+				}),
+				Model: ollamamodels.QWEN2_5_LATEST,
+			},
+			{
+				Name: "openai",
+				Provider: openai.NewProvider(&openai.ProviderOpts{
+					Logger: &logger,
+				}),
+				Model: openaimodels.GPT4_O,
+			},
+			{
+				Name: "anthropic",
+				Provider: anthropic.NewProvider(&anthropic.ProviderOpts{
+					Logger: slogger,
+				}),
+				Model: anthropicmodels.CLAUDE_3_5_SONNET_V2,
+			},
+			{
+				Name: "googlegenai",
+				Provider: googlegenai.NewProvider(&googlegenai.ProviderOpts{
+					Logger: &logger,
+				}),
+				Model: googlegenaimodels.GEMINI_1_5_FLASH,
+			},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a new agent
+	myAgent, err := agent.NewAgent(
+		bootstrap.WithProvider(provider),
+		bootstrap.WithLogger(&logger),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	response, err := myAgent.Run(
+		ctx,
+		agent.WithInput("Why is the sky blue?"),
+	)
+	if err != nil {
+		logger.Error(err, "failed sending message to agent")
+		return
+	}
+
+	last := response.Messages[len(response.Messages)-1]
+	fmt.Println("Served by:", last.Metadata.ProviderProperties[resilience.ServedByProperty])
+	fmt.Println("Agent response:", last.Content)
+
+	for _, h := range provider.Health() {
+		logger.Info("provider health",
+			"provider", h.Name,
+			"state", h.State.String(),
+			"served", h.Served,
+			"failed", h.Failed,
+			"last_error", h.LastError,
+		)
+	}
+}
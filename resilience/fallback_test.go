@@ -0,0 +1,315 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/agent-api/core"
+)
+
+var (
+	errUnavailable = errors.New("request failed with status 503: unavailable")
+	errBadRequest  = errors.New("request failed with status 400: bad request")
+)
+
+// fakeProvider fails its calls with errs, in order, and answers with content
+// once they run out. Streams emit deltas before failing or answering.
+type fakeProvider struct {
+	errs     []error
+	content  string
+	deltas   []string
+	noStream bool
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (p *fakeProvider) GetCapabilities(ctx context.Context) (*core.Capabilities, error) {
+	return &core.Capabilities{}, nil
+}
+
+func (p *fakeProvider) UseModel(ctx context.Context, model *core.Model) error {
+	return nil
+}
+
+// next returns the error of the next call, if any.
+func (p *fakeProvider) next() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.calls++
+	if p.calls <= len(p.errs) {
+		return p.errs[p.calls-1]
+	}
+
+	return nil
+}
+
+func (p *fakeProvider) callCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.calls
+}
+
+func (p *fakeProvider) Generate(ctx context.Context, opts *core.GenerateOptions) (*core.Message, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	return &core.Message{Role: core.AssistantMessageRole, Content: p.content}, nil
+}
+
+func (p *fakeProvider) GenerateStream(ctx context.Context, opts *core.GenerateOptions) (<-chan *core.Message, <-chan string, <-chan error) {
+	if p.noStream {
+		return nil, nil, nil
+	}
+
+	msgChan := make(chan *core.Message, 10)
+	deltaChan := make(chan string, 10)
+	errChan := make(chan error, 10)
+
+	go func() {
+		defer close(msgChan)
+		defer close(deltaChan)
+		defer close(errChan)
+
+		err := p.next()
+
+		for _, d := range p.deltas {
+			deltaChan <- d
+		}
+
+		if err != nil {
+			errChan <- err
+			return
+		}
+
+		msgChan <- &core.Message{Role: core.AssistantMessageRole, Content: p.content}
+	}()
+
+	return msgChan, deltaChan, errChan
+}
+
+// drain collects a stream's output until all of its channels are closed.
+func drain(msgChan <-chan *core.Message, deltaChan <-chan string, errChan <-chan error) ([]*core.Message, []string, error) {
+	msgs := []*core.Message{}
+	deltas := []string{}
+	var err error
+
+	for msgChan != nil || deltaChan != nil || errChan != nil {
+		select {
+		case msg, ok := <-msgChan:
+			if !ok {
+				msgChan = nil
+				continue
+			}
+			msgs = append(msgs, msg)
+
+		case delta, ok := <-deltaChan:
+			if !ok {
+				deltaChan = nil
+				continue
+			}
+			deltas = append(deltas, delta)
+
+		case e, ok := <-errChan:
+			if !ok {
+				errChan = nil
+				continue
+			}
+			err = e
+		}
+	}
+
+	return msgs, deltas, err
+}
+
+func newFallback(t *testing.T, threshold int, providers ...*fakeProvider) *FallbackProvider {
+	t.Helper()
+
+	members := []*FallbackMember{}
+	for i, p := range providers {
+		members = append(members, &FallbackMember{Name: string(rune('a' + i)), Provider: p})
+	}
+
+	f, err := NewFallbackProvider(context.Background(), &FallbackProviderOpts{
+		Members:          members,
+		FailureThreshold: threshold,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return f
+}
+
+func servedBy(msg *core.Message) string {
+	return msg.Metadata.ProviderProperties[ServedByProperty]
+}
+
+func TestFallbackGenerate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("fails over on transient errors", func(t *testing.T) {
+		a := &fakeProvider{errs: []error{errUnavailable}}
+		b := &fakeProvider{content: "hello"}
+		f := newFallback(t, 3, a, b)
+
+		msg, err := f.Generate(ctx, &core.GenerateOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if msg.Content != "hello" || servedBy(msg) != "b" {
+			t.Errorf("got %q served by %q", msg.Content, servedBy(msg))
+		}
+
+		health := f.Health()
+		if health[0].Failed != 1 || health[0].LastError != errUnavailable || health[1].Served != 1 {
+			t.Errorf("got health %+v, %+v", health[0], health[1])
+		}
+	})
+
+	t.Run("returns permanent errors", func(t *testing.T) {
+		a := &fakeProvider{errs: []error{errBadRequest}}
+		b := &fakeProvider{content: "hello"}
+		f := newFallback(t, 3, a, b)
+
+		if _, err := f.Generate(ctx, &core.GenerateOptions{}); !errors.Is(err, errBadRequest) {
+			t.Errorf("got error %v", err)
+		}
+
+		if b.callCount() != 0 {
+			t.Error("failed over on a permanent error")
+		}
+
+		if f.Health()[0].Failed != 0 {
+			t.Error("a permanent error counted as a provider failure")
+		}
+	})
+
+	t.Run("skips providers with an open breaker", func(t *testing.T) {
+		a := &fakeProvider{errs: []error{errUnavailable}}
+		b := &fakeProvider{content: "hello"}
+		f := newFallback(t, 1, a, b)
+
+		for range 2 {
+			if _, err := f.Generate(ctx, &core.GenerateOptions{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if a.callCount() != 1 || b.callCount() != 2 {
+			t.Errorf("got %d and %d calls, want 1 and 2", a.callCount(), b.callCount())
+		}
+
+		if f.Health()[0].State != BreakerOpen {
+			t.Errorf("got state %s", f.Health()[0].State)
+		}
+	})
+
+	t.Run("no provider available", func(t *testing.T) {
+		a := &fakeProvider{errs: []error{errUnavailable}}
+		b := &fakeProvider{errs: []error{errUnavailable}}
+		f := newFallback(t, 3, a, b)
+
+		if _, err := f.Generate(ctx, &core.GenerateOptions{}); !errors.Is(err, ErrNoProviderAvailable) {
+			t.Errorf("got error %v", err)
+		}
+	})
+}
+
+func TestFallbackGenerateStream(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		providers  []*fakeProvider
+		wantDeltas []string
+		wantServed string
+		wantErr    error
+		wantCalls  []int
+	}{
+		{
+			name: "fails over before any output",
+			providers: []*fakeProvider{
+				{errs: []error{errUnavailable}},
+				{deltas: []string{"hel", "lo"}, content: "hello"},
+			},
+			wantDeltas: []string{"hel", "lo"},
+			wantServed: "b",
+			wantCalls:  []int{1, 1},
+		},
+		{
+			name: "skips providers without streaming",
+			providers: []*fakeProvider{
+				{noStream: true},
+				{content: "hello"},
+			},
+			wantDeltas: []string{},
+			wantServed: "b",
+			wantCalls:  []int{0, 1},
+		},
+		{
+			name: "commits to a provider once it has emitted output",
+			providers: []*fakeProvider{
+				{errs: []error{errUnavailable}, deltas: []string{"hel"}},
+				{content: "hello"},
+			},
+			wantDeltas: []string{"hel"},
+			wantErr:    errUnavailable,
+			wantCalls:  []int{1, 0},
+		},
+		{
+			name: "no provider available",
+			providers: []*fakeProvider{
+				{errs: []error{errUnavailable}},
+				{noStream: true},
+			},
+			wantDeltas: []string{},
+			wantErr:    ErrNoProviderAvailable,
+			wantCalls:  []int{1, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFallback(t, 3, tt.providers...)
+
+			msgs, deltas, err := drain(f.GenerateStream(ctx, &core.GenerateOptions{}))
+
+			if !slices.Equal(deltas, tt.wantDeltas) {
+				t.Errorf("got deltas %v, want %v", deltas, tt.wantDeltas)
+			}
+
+			for i, p := range tt.providers {
+				if p.callCount() != tt.wantCalls[i] {
+					t.Errorf("provider %d got %d calls, want %d", i, p.callCount(), tt.wantCalls[i])
+				}
+			}
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("got error %v, want %v", err, tt.wantErr)
+				}
+
+				if len(msgs) != 0 {
+					t.Errorf("got %d messages from a failed stream", len(msgs))
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(msgs) != 1 || msgs[0].Content != "hello" || servedBy(msgs[0]) != tt.wantServed {
+				t.Errorf("got messages %v", msgs)
+			}
+		})
+	}
+}
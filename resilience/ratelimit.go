@@ -0,0 +1,74 @@
+package resilience
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/examples/modelinfo"
+)
+
+// RateLimiter is a client side token bucket rate limiter for requests per
+// minute and tokens per minute. A zero limit is unlimited.
+type RateLimiter struct {
+	requests *rate.Limiter
+	tokens   *rate.Limiter
+}
+
+// NewRateLimiter returns a new RateLimiter. Buckets start full, so up to a
+// minute's worth of requests or tokens may be bursted.
+func NewRateLimiter(requestsPerMinute, tokensPerMinute int) *RateLimiter {
+	return &RateLimiter{
+		requests: perMinuteLimiter(requestsPerMinute),
+		tokens:   perMinuteLimiter(tokensPerMinute),
+	}
+}
+
+func perMinuteLimiter(perMinute int) *rate.Limiter {
+	if perMinute <= 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+
+	return rate.NewLimiter(rate.Limit(float64(perMinute)/60), perMinute)
+}
+
+// Wait blocks until one request and the given number of tokens are available
+// or the context is done.
+func (r *RateLimiter) Wait(ctx context.Context, tokens int) error {
+	if err := r.requests.Wait(ctx); err != nil {
+		return err
+	}
+
+	// a single request may never need more than the whole bucket
+	if burst := r.tokens.Burst(); burst > 0 && tokens > burst {
+		tokens = burst
+	}
+
+	return r.tokens.WaitN(ctx, tokens)
+}
+
+// Charge takes tokens that were only known after a request completed (i.e.,
+// completion tokens) from the bucket without blocking. Later requests wait
+// for the bucket to refill.
+func (r *RateLimiter) Charge(tokens int) {
+	if tokens <= 0 {
+		return
+	}
+
+	r.tokens.ReserveN(time.Now(), min(tokens, max(r.tokens.Burst(), 1)))
+}
+
+func estimatePromptTokens(opts *core.GenerateOptions) int {
+	tokens := 0
+	for _, m := range opts.Messages {
+		tokens += modelinfo.EstimateTokens(m.Content)
+	}
+
+	for _, t := range opts.Tools {
+		tokens += modelinfo.EstimateTokens(t.Description) + modelinfo.EstimateTokens(string(t.JSONSchema))
+	}
+
+	return tokens
+}
@@ -0,0 +1,204 @@
+package resilience
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/examples/modelinfo"
+)
+
+// RetryProviderOpts configures a new RetryProvider.
+type RetryProviderOpts struct {
+	// The wrapped core.Provider
+	Provider core.Provider
+
+	// MaxRetries is the number of retries after the first attempt. Defaults to 3.
+	MaxRetries int
+
+	// BaseDelay is the backoff delay before the first retry. Each retry doubles
+	// the delay, up to MaxDelay, and applies full jitter. Defaults to 500ms.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the backoff delay. Defaults to 30 seconds. A provider's
+	// Retry-After is always honored, even when longer than MaxDelay.
+	MaxDelay time.Duration
+
+	// ShouldRetry decides if an error is retried. Defaults to IsTransient.
+	ShouldRetry func(err error) bool
+
+	// Client side rate limits. Zero is unlimited.
+	RequestsPerMinute int
+	TokensPerMinute   int
+
+	Logger *logr.Logger
+}
+
+// RetryProvider is a core.Provider that retries transient errors from the
+// wrapped provider with jittered exponential backoff and applies client side
+// rate limits.
+type RetryProvider struct {
+	provider    core.Provider
+	maxRetries  int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	shouldRetry func(err error) bool
+	limiter     *RateLimiter
+
+	logger *logr.Logger
+}
+
+// NewRetryProvider creates a new RetryProvider.
+func NewRetryProvider(opts *RetryProviderOpts) *RetryProvider {
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = 3
+	}
+
+	if opts.BaseDelay == 0 {
+		opts.BaseDelay = 500 * time.Millisecond
+	}
+
+	if opts.MaxDelay == 0 {
+		opts.MaxDelay = 30 * time.Second
+	}
+
+	if opts.ShouldRetry == nil {
+		opts.ShouldRetry = IsTransient
+	}
+
+	return &RetryProvider{
+		provider:    opts.Provider,
+		maxRetries:  opts.MaxRetries,
+		baseDelay:   opts.BaseDelay,
+		maxDelay:    opts.MaxDelay,
+		shouldRetry: opts.ShouldRetry,
+		limiter:     NewRateLimiter(opts.RequestsPerMinute, opts.TokensPerMinute),
+		logger:      opts.Logger,
+	}
+}
+
+func (r *RetryProvider) GetCapabilities(ctx context.Context) (*core.Capabilities, error) {
+	return r.provider.GetCapabilities(ctx)
+}
+
+func (r *RetryProvider) UseModel(ctx context.Context, model *core.Model) error {
+	return r.provider.UseModel(ctx, model)
+}
+
+// Generate calls the wrapped provider, retrying transient errors.
+func (r *RetryProvider) Generate(ctx context.Context, opts *core.GenerateOptions) (*core.Message, error) {
+	for attempt := 0; ; attempt++ {
+		if err := r.limiter.Wait(ctx, estimatePromptTokens(opts)); err != nil {
+			return nil, fmt.Errorf("error waiting for rate limiter: %w", err)
+		}
+
+		msg, err := r.provider.Generate(ctx, opts)
+		if err == nil {
+			if msg != nil {
+				r.limiter.Charge(modelinfo.EstimateTokens(msg.Content))
+			}
+
+			return msg, nil
+		}
+
+		if attempt >= r.maxRetries || !r.shouldRetry(err) {
+			return nil, fmt.Errorf("generate failed after %d attempts: %w", attempt+1, err)
+		}
+
+		if err := r.wait(ctx, attempt, err); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// GenerateStream calls the wrapped provider's stream, retrying transient
+// errors. A stream is never retried once any message or delta has been
+// emitted since consumers would otherwise see duplicated output.
+func (r *RetryProvider) GenerateStream(ctx context.Context, opts *core.GenerateOptions) (<-chan *core.Message, <-chan string, <-chan error) {
+	// buffered, non-blocking channels
+	outMsgChan := make(chan *core.Message, 10)
+	outDeltaChan := make(chan string, 10)
+	outErrChan := make(chan error, 10)
+
+	go func() {
+		defer close(outMsgChan)
+		defer close(outDeltaChan)
+		defer close(outErrChan)
+
+		for attempt := 0; ; attempt++ {
+			if err := r.limiter.Wait(ctx, estimatePromptTokens(opts)); err != nil {
+				outErrChan <- fmt.Errorf("error waiting for rate limiter: %w", err)
+				return
+			}
+
+			completion := 0
+			emitted, err := pipeStream(ctx, r.provider, opts, outMsgChan, outDeltaChan, func(msg *core.Message) *core.Message {
+				completion += modelinfo.EstimateTokens(msg.Content)
+				return msg
+			})
+			r.limiter.Charge(completion)
+
+			if err == nil {
+				return
+			}
+
+			if emitted || attempt >= r.maxRetries || !r.shouldRetry(err) {
+				outErrChan <- fmt.Errorf("stream failed after %d attempts: %w", attempt+1, err)
+				return
+			}
+
+			if err := r.wait(ctx, attempt, err); err != nil {
+				outErrChan <- err
+				return
+			}
+		}
+	}()
+
+	return outMsgChan, outDeltaChan, outErrChan
+}
+
+// wait sleeps before the next retry, or returns early if the context is done.
+func (r *RetryProvider) wait(ctx context.Context, attempt int, err error) error {
+	delay := r.delay(attempt, err)
+
+	if r.logger != nil {
+		r.logger.V(-1).Info("retrying provider request",
+			"attempt", attempt+1,
+			"delay", delay.String(),
+			"class", ClassifyError(err).String(),
+			"error", err,
+		)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// delay returns the provider's Retry-After if set, otherwise a "full jitter"
+// exponential backoff: a random duration between 0 and
+// min(MaxDelay, BaseDelay * 2^attempt).
+func (r *RetryProvider) delay(attempt int, err error) time.Duration {
+	if retryAfter, ok := RetryAfter(err); ok {
+		return retryAfter
+	}
+
+	ceiling := r.maxDelay
+	if attempt < 32 {
+		if backoff := r.baseDelay << attempt; backoff > 0 && backoff < ceiling {
+			ceiling = backoff
+		}
+	}
+
+	return rand.N(ceiling) + 1
+}
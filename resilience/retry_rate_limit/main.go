@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/core/agent/bootstrap"
+	"github.com/agent-api/examples/resilience"
+	"github.com/agent-api/openai"
+	"github.com/agent-api/openai/models"
+)
+
+func main() {
+	ctx := context.Background()
+
+	// Create a zap logger
+	config := zap.NewDevelopmentConfig()
+	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	zLogger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a logr.Logger using zapr adapter
+	logger := zapr.NewLogger(zLogger)
+
+	// Create an openai provider
+	openaiProvider := openai.NewProvider(&openai.ProviderOpts{
+		Logger: &logger,
+	})
+	openaiProvider.UseModel(ctx, models.GPT4_O)
+
+	// Wrap it to retry transient errors and stay under the account's limits
+	provider := resilience.NewRetryProvider(&resilience.RetryProviderOpts{
+		Provider:          openaiProvider,
+		MaxRetries:        5,
+		BaseDelay:         time.Second,
+		RequestsPerMinute: 60,
+		TokensPerMinute:   30000,
+		Logger:            &logger,
+	})
+
+	// Create a new agent
+	myAgent, err := agent.NewAgent(
+		bootstrap.WithProvider(provider),
+		bootstrap.WithLogger(&logger),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	result := myAgent.RunStream(
+		ctx,
+		agent.WithInput("Why is the sky blue?"),
+	)
+
+	for delta := range result.DeltaChan {
+		print(delta)
+	}
+}
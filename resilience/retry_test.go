@@ -0,0 +1,211 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/agent-api/core"
+)
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		want   time.Duration
+		wantOK bool
+	}{
+		{
+			name:   "milliseconds",
+			err:    openAIError(429, http.Header{"Retry-After-Ms": {"1500"}, "Retry-After": {"9"}}),
+			want:   1500 * time.Millisecond,
+			wantOK: true,
+		},
+		{
+			name:   "seconds",
+			err:    openAIError(429, http.Header{"Retry-After": {"20"}}),
+			want:   20 * time.Second,
+			wantOK: true,
+		},
+		{
+			name:   "past date",
+			err:    openAIError(503, http.Header{"Retry-After": {"Wed, 21 Oct 2015 07:28:00 GMT"}}),
+			want:   0,
+			wantOK: true,
+		},
+		{
+			name: "invalid header",
+			err:  openAIError(429, http.Header{"Retry-After": {"soon"}}),
+		},
+		{
+			name: "no header",
+			err:  openAIError(429, nil),
+		},
+		{
+			name: "not an API error",
+			err:  errors.New("request failed with status 429: slow down"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := RetryAfter(tt.err)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("got %s, %t, want %s, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	t.Run("future date", func(t *testing.T) {
+		date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+
+		got, ok := RetryAfter(openAIError(429, http.Header{"Retry-After": {date}}))
+		if !ok || got <= 58*time.Second || got > time.Minute {
+			t.Errorf("got %s, %t, want about a minute", got, ok)
+		}
+	})
+}
+
+func TestRetryDelay(t *testing.T) {
+	r := NewRetryProvider(&RetryProviderOpts{
+		BaseDelay: 100 * time.Millisecond,
+		MaxDelay:  time.Second,
+	})
+
+	tests := []struct {
+		attempt int
+		ceiling time.Duration
+	}{
+		{attempt: 0, ceiling: 100 * time.Millisecond},
+		{attempt: 1, ceiling: 200 * time.Millisecond},
+		{attempt: 3, ceiling: 800 * time.Millisecond},
+		{attempt: 4, ceiling: time.Second},
+		// past 32 the shift would overflow
+		{attempt: 40, ceiling: time.Second},
+	}
+
+	for _, tt := range tests {
+		for range 100 {
+			delay := r.delay(tt.attempt, errUnavailable)
+			if delay <= 0 || delay > tt.ceiling {
+				t.Fatalf("attempt %d: got delay %s, want (0, %s]", tt.attempt, delay, tt.ceiling)
+			}
+		}
+	}
+
+	// Retry-After wins, even over MaxDelay
+	err := openAIError(429, http.Header{"Retry-After": {"5"}})
+	if delay := r.delay(0, err); delay != 5*time.Second {
+		t.Errorf("got delay %s, want the 5s Retry-After", delay)
+	}
+}
+
+func newTestRetryProvider(p *fakeProvider, maxRetries int) *RetryProvider {
+	return NewRetryProvider(&RetryProviderOpts{
+		Provider:   p,
+		MaxRetries: maxRetries,
+		BaseDelay:  time.Nanosecond,
+		MaxDelay:   time.Millisecond,
+	})
+}
+
+func TestRetryGenerate(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		errs       []error
+		maxRetries int
+		wantErr    error
+		wantCalls  int
+	}{
+		{
+			name:       "retries transient errors",
+			errs:       []error{errUnavailable, errUnavailable},
+			maxRetries: 3,
+			wantCalls:  3,
+		},
+		{
+			name:       "gives up after the max retries",
+			errs:       []error{errUnavailable, errUnavailable, errUnavailable},
+			maxRetries: 2,
+			wantErr:    errUnavailable,
+			wantCalls:  3,
+		},
+		{
+			name:       "does not retry permanent errors",
+			errs:       []error{errBadRequest},
+			maxRetries: 3,
+			wantErr:    errBadRequest,
+			wantCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &fakeProvider{errs: tt.errs, content: "hello"}
+
+			msg, err := newTestRetryProvider(p, tt.maxRetries).Generate(ctx, &core.GenerateOptions{})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("got error %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil || msg.Content != "hello" {
+				t.Errorf("got %v, %v", msg, err)
+			}
+
+			if p.callCount() != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", p.callCount(), tt.wantCalls)
+			}
+		})
+	}
+
+	t.Run("stops waiting when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		p := &fakeProvider{errs: []error{errUnavailable}}
+		r := NewRetryProvider(&RetryProviderOpts{Provider: p, BaseDelay: time.Hour})
+
+		if _, err := r.Generate(ctx, &core.GenerateOptions{}); err == nil {
+			t.Error("expected an error")
+		}
+
+		if p.callCount() > 1 {
+			t.Errorf("got %d calls after the context was canceled", p.callCount())
+		}
+	})
+}
+
+func TestRetryGenerateStream(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("retries before any output", func(t *testing.T) {
+		p := &fakeProvider{errs: []error{errUnavailable}, content: "hello"}
+
+		msgs, _, err := drain(newTestRetryProvider(p, 3).GenerateStream(ctx, &core.GenerateOptions{}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(msgs) != 1 || msgs[0].Content != "hello" || p.callCount() != 2 {
+			t.Errorf("got %d messages after %d calls", len(msgs), p.callCount())
+		}
+	})
+
+	t.Run("does not retry once output was emitted", func(t *testing.T) {
+		p := &fakeProvider{errs: []error{errUnavailable}, deltas: []string{"hel"}, content: "hello"}
+
+		msgs, deltas, err := drain(newTestRetryProvider(p, 3).GenerateStream(ctx, &core.GenerateOptions{}))
+		if !errors.Is(err, errUnavailable) {
+			t.Errorf("got error %v", err)
+		}
+
+		if len(msgs) != 0 || !slices.Equal(deltas, []string{"hel"}) || p.callCount() != 1 {
+			t.Errorf("got messages %v and deltas %v after %d calls", msgs, deltas, p.callCount())
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+
+	"github.com/agent-api/core"
+)
+
+// errStreamUnsupported is returned for providers that return nil stream
+// channels (i.e., the ollama provider does not implement streaming yet).
+var errStreamUnsupported = errors.New("provider does not support streaming")
+
+// pipeStream starts a stream on the provider and forwards it to the output
+// channels, passing each complete message through onMessage. It returns
+// whether any output was emitted and the first stream error.
+func pipeStream(
+	ctx context.Context,
+	provider core.Provider,
+	opts *core.GenerateOptions,
+	outMsgChan chan<- *core.Message,
+	outDeltaChan chan<- string,
+	onMessage func(*core.Message) *core.Message,
+) (bool, error) {
+	msgChan, deltaChan, errChan := provider.GenerateStream(ctx, opts)
+	if msgChan == nil && deltaChan == nil && errChan == nil {
+		return false, errStreamUnsupported
+	}
+
+	emitted := false
+
+	for msgChan != nil || deltaChan != nil || errChan != nil {
+		select {
+		case msg, ok := <-msgChan:
+			if !ok {
+				msgChan = nil
+				continue
+			}
+
+			if msg != nil {
+				emitted = true
+				outMsgChan <- onMessage(msg)
+			}
+
+		case delta, ok := <-deltaChan:
+			if !ok {
+				deltaChan = nil
+				continue
+			}
+
+			if delta != "" {
+				emitted = true
+				outDeltaChan <- delta
+			}
+
+		case err, ok := <-errChan:
+			if !ok {
+				errChan = nil
+				continue
+			}
+
+			if err != nil {
+				return emitted, err
+			}
+
+		case <-ctx.Done():
+			return emitted, ctx.Err()
+		}
+	}
+
+	return emitted, nil
+}
@@ -0,0 +1,239 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/examples/internal/llmjson"
+	"github.com/agent-api/examples/vectorstorer"
+)
+
+// KeywordClassifier routes inputs that contain any of a route's keywords as
+// whole words. The route with the most keyword matches wins.
+type KeywordClassifier struct{}
+
+// NewKeywordClassifier returns a new KeywordClassifier.
+func NewKeywordClassifier() *KeywordClassifier {
+	return &KeywordClassifier{}
+}
+
+func (k *KeywordClassifier) Name() string {
+	return "keyword"
+}
+
+func (k *KeywordClassifier) Classify(ctx context.Context, input string, routes []*Route) (*Decision, error) {
+	words := vectorstorer.Tokenize(input)
+
+	var best *Route
+	var bestMatches []string
+
+	for _, route := range routes {
+		matches := []string{}
+		for _, keyword := range route.Keywords {
+			if containsWords(words, vectorstorer.Tokenize(keyword)) {
+				matches = append(matches, keyword)
+			}
+		}
+
+		if len(matches) > len(bestMatches) {
+			best = route
+			bestMatches = matches
+		}
+	}
+
+	if best == nil {
+		return nil, nil
+	}
+
+	return &Decision{
+		Route:  best.Name,
+		Reason: fmt.Sprintf("input matched keywords: %s", strings.Join(bestMatches, ", ")),
+		Score:  float64(len(bestMatches)) / float64(len(best.Keywords)),
+	}, nil
+}
+
+// containsWords reports whether phrase occurs in words as a contiguous run.
+func containsWords(words, phrase []string) bool {
+	if len(phrase) == 0 {
+		return false
+	}
+
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		if slices.Equal(words[i:i+len(phrase)], phrase) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LLMClassifier asks a (usually small and cheap) model to choose a route.
+type LLMClassifier struct {
+	provider core.Provider
+}
+
+// NewLLMClassifier returns a classifier that uses the given provider. The
+// provider should already have a model set via UseModel.
+func NewLLMClassifier(provider core.Provider) *LLMClassifier {
+	return &LLMClassifier{
+		provider: provider,
+	}
+}
+
+func (l *LLMClassifier) Name() string {
+	return "llm"
+}
+
+const llmClassifierPrompt string = `You are a request router. Choose the single best route for the user input below.
+
+Routes:
+%s
+User input:
+%s
+
+Respond ONLY with a JSON object of the form {"route": "<route name>", "reason": "<one short sentence>"}.
+If no route fits, use an empty string for the route.`
+
+type llmDecision struct {
+	Route  string `json:"route"`
+	Reason string `json:"reason"`
+}
+
+func (l *LLMClassifier) Classify(ctx context.Context, input string, routes []*Route) (*Decision, error) {
+	var list strings.Builder
+	for _, route := range routes {
+		fmt.Fprintf(&list, "- %s: %s\n", route.Name, route.Description)
+	}
+
+	resp, err := l.provider.Generate(ctx, &core.GenerateOptions{
+		Messages: []*core.Message{
+			{
+				Role:    core.UserMessageRole,
+				Content: fmt.Sprintf(llmClassifierPrompt, list.String(), input),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error generating route classification: %w", err)
+	}
+
+	data, ok := llmjson.Extract(resp.Content, '{', '}')
+	if !ok {
+		return nil, fmt.Errorf("route classification was not JSON: %s", resp.Content)
+	}
+
+	decision := &llmDecision{}
+	if err := json.Unmarshal([]byte(data), decision); err != nil {
+		return nil, fmt.Errorf("error unmarshaling route classification: %w", err)
+	}
+
+	if decision.Route == "" {
+		return nil, nil
+	}
+
+	return &Decision{
+		Route:  decision.Route,
+		Reason: decision.Reason,
+		Score:  1,
+	}, nil
+}
+
+// EmbedFunc returns the embedding vector for a text.
+type EmbedFunc func(ctx context.Context, text string) ([]float32, error)
+
+// EmbeddingClassifier routes inputs to the route whose description or
+// examples are most similar to the input.
+type EmbeddingClassifier struct {
+	embed     EmbedFunc
+	threshold float64
+
+	// mu guards cache, the embeddings of route descriptions and examples
+	mu    sync.Mutex
+	cache map[string][]float32
+}
+
+// NewEmbeddingClassifier returns a classifier that only makes a decision when
+// the best cosine similarity is at or above threshold.
+func NewEmbeddingClassifier(embed EmbedFunc, threshold float64) *EmbeddingClassifier {
+	return &EmbeddingClassifier{
+		embed:     embed,
+		threshold: threshold,
+		cache:     make(map[string][]float32),
+	}
+}
+
+func (e *EmbeddingClassifier) Name() string {
+	return "embedding"
+}
+
+func (e *EmbeddingClassifier) Classify(ctx context.Context, input string, routes []*Route) (*Decision, error) {
+	inputVec, err := e.embed(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("error embedding input: %w", err)
+	}
+
+	var best *Route
+	var bestText string
+	bestScore := -1.0
+
+	for _, route := range routes {
+		texts := append([]string{route.Description}, route.Examples...)
+
+		for _, text := range texts {
+			if text == "" {
+				continue
+			}
+
+			vec, err := e.cached(ctx, text)
+			if err != nil {
+				return nil, err
+			}
+
+			score := 0.0
+			if len(vec) == len(inputVec) {
+				score = vectorstorer.Cosine.Score(inputVec, vec)
+			}
+			if score > bestScore {
+				best = route
+				bestText = text
+				bestScore = score
+			}
+		}
+	}
+
+	if best == nil || bestScore < e.threshold {
+		return nil, nil
+	}
+
+	return &Decision{
+		Route:  best.Name,
+		Reason: fmt.Sprintf("input is most similar (%.2f) to %q", bestScore, bestText),
+		Score:  bestScore,
+	}, nil
+}
+
+func (e *EmbeddingClassifier) cached(ctx context.Context, text string) ([]float32, error) {
+	e.mu.Lock()
+	vec, ok := e.cache[text]
+	e.mu.Unlock()
+
+	if ok {
+		return vec, nil
+	}
+
+	vec, err := e.embed(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("error embedding route text: %w", err)
+	}
+
+	e.mu.Lock()
+	e.cache[text] = vec
+	e.mu.Unlock()
+
+	return vec, nil
+}
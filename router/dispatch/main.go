@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/core/agent/bootstrap"
+	"github.com/agent-api/examples/internal/agentrun"
+	"github.com/agent-api/examples/rag"
+	"github.com/agent-api/examples/router"
+	"github.com/agent-api/examples/vectorstorer"
+	"github.com/agent-api/ollama"
+	"github.com/agent-api/ollama/models"
+)
+
+const calculatorSchema string = `{
+  "title": "calculator",
+  "description": "A simple calculator on ints",
+  "type": "object",
+  "properties": {
+    "a": {
+      "description": "The first operand",
+      "type": "number"
+    },
+    "b": {
+      "description": "The second operand",
+      "type": "number"
+    },
+    "operation": {
+      "description": "The operation to perform. One of [add, multiply]",
+      "type": "string"
+    }
+  },
+  "required": [
+    "operation",
+    "a",
+    "b"
+  ]
+}`
+
+type calculatorParams struct {
+	Operation string `json:"operation"`
+	A         int    `json:"a"`
+	B         int    `json:"b"`
+}
+
+func calculator(ctx context.Context, args *calculatorParams) (interface{}, error) {
+	switch args.Operation {
+	case "add":
+		return args.A + args.B, nil
+	case "multiply":
+		return args.A * args.B, nil
+	default:
+		return nil, fmt.Errorf("unsupported operation: %s", args.Operation)
+	}
+}
+
+func main() {
+	ctx := context.Background()
+
+	// Create a zap logger
+	config := zap.NewDevelopmentConfig()
+	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	zLogger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a logr.Logger using zapr adapter
+	logger := zapr.NewLogger(zLogger)
+
+	opts := &ollama.ProviderOpts{
+		Logger:  &logger,
+		BaseURL: "http://localhost",
+		Port:    11434,
+	}
+
+	// A vision model for the image analyst
+	visionProvider := ollama.NewProvider(opts)
+	visionProvider.UseModel(ctx, models.GEMMA3_LATEST)
+
+	// Every routed input gets a new agent, so inputs never share memory
+	newImageAgent := func() (agentrun.Runner, error) {
+		return agent.NewAgent(
+			bootstrap.WithProvider(visionProvider),
+			bootstrap.WithLogger(&logger),
+			bootstrap.WithSystemPrompt("You are a professional image analyst."),
+		)
+	}
+
+	// A tool calling model for the calculator agent
+	toolProvider := ollama.NewProvider(opts)
+	toolProvider.UseModel(ctx, models.QWEN2_5_LATEST)
+
+	wrappedCalc, err := core.WrapToolFunction(calculator)
+	if err != nil {
+		panic(err)
+	}
+
+	newCalcAgent := func() (agentrun.Runner, error) {
+		return agent.NewAgent(
+			bootstrap.WithProvider(toolProvider),
+			bootstrap.WithLogger(&logger),
+			bootstrap.WithTools(&core.Tool{
+				Name:                "calculator",
+				Description:         "Performs basic arithmetic operations: supported operations are 'add' and 'multiply'",
+				WrappedToolFunction: wrappedCalc,
+				JSONSchema:          []byte(calculatorSchema),
+			}),
+		)
+	}
+
+	// A general purpose agent, which also answers from retrieved spells
+	newChatAgent := func() (agentrun.Runner, error) {
+		return agent.NewAgent(
+			bootstrap.WithProvider(toolProvider),
+			bootstrap.WithLogger(&logger),
+		)
+	}
+
+	// A retrieval augmented pipeline answering from the spell book, like the
+	// agent_vec_store examples but over an in-memory store
+	embedder := vectorstorer.NewOllamaEmbedder(&vectorstorer.OllamaEmbedderOpts{
+		Model:  vectorstorer.OllamaNomicEmbedText,
+		Logger: &logger,
+	})
+
+	store, err := vectorstorer.NewMemoryStore(&vectorstorer.MemoryStoreOpts{
+		Embedder: embedder,
+		Logger:   &logger,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = store.Add(ctx, []string{
+		"Fire Bolt - Cantrip - 120ft - You hurl a mote of fire at a creature or an object within range. Make a ranged spell attack against the target. On a hit, the target takes 1d10 Fire damage. A flammable object hit by this spell starts burning if it isn’t being worn or carried.",
+		"Ray of Frost - Cantrip - 60ft - A frigid beam of blue-white light streaks toward a creature within range. On a hit, it takes 1d8 Cold damage, and its speed is reduced by 10 feet until the start of your next turn.",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	spellBook, err := rag.NewPipeline(&rag.PipelineOpts{
+		Store:    store,
+		NewAgent: newChatAgent,
+		Limit:    2,
+		Logger:   &logger,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// The pipeline creates an agent per question itself
+	newSpellBook := func() (agentrun.Runner, error) {
+		return spellBook, nil
+	}
+
+	// The same small model doubles as a cheap classifier
+	classifierProvider := ollama.NewProvider(opts)
+	classifierProvider.UseModel(ctx, models.QWEN2_5_LATEST)
+
+	r, err := router.NewRouter(&router.RouterOpts{
+		Logger:       &logger,
+		DefaultRoute: "chat",
+		Classifiers: []router.Classifier{
+			// cheap, deterministic rules first, then fall back to the model
+			router.NewKeywordClassifier(),
+			router.NewLLMClassifier(classifierProvider),
+		},
+		Routes: []*router.Route{
+			{
+				Name:        "image_analyst",
+				Description: "Describes and analyzes images",
+				Keywords:    []string{"image", "picture", "photo"},
+				New:         newImageAgent,
+			},
+			{
+				Name:        "calculator",
+				Description: "Adds and multiplies numbers",
+				Keywords:    []string{"add", "multiply", "sum", "product"},
+				New:         newCalcAgent,
+			},
+			{
+				Name:        "spell_book",
+				Description: "Answers questions about spells from the spell book, with citations",
+				Keywords:    []string{"spell", "cantrip"},
+				New:         newSpellBook,
+			},
+			{
+				Name:        "chat",
+				Description: "General questions and conversation",
+				New:         newChatAgent,
+			},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	inputs := []struct {
+		input string
+		opts  []agent.RunOptionFunc
+	}{
+		{
+			input: "What is in this picture?",
+			opts: []agent.RunOptionFunc{
+				// Note: this path is relative to where your "go run" this program or
+				// run a compiled executable.
+				agent.WithImagePath("./cute-dog.jpg"),
+			},
+		},
+		{input: "What is 987 times 123?"},
+		{input: "How does the Fire Bolt spell work?"},
+		{input: "Why is the sky blue?"},
+	}
+
+	for _, in := range inputs {
+		result, err := r.Run(ctx, in.input, in.opts...)
+		if err != nil {
+			logger.Error(err, "routed run failed", "input", in.input)
+			continue
+		}
+
+		logger.Info("routed input",
+			"input", in.input,
+			"route", result.Decision.Route,
+			"classifier", result.Decision.Classifier,
+			"reason", result.Decision.Reason,
+		)
+
+		fmt.Println("Agent response:", result.Result.Pop().Content)
+	}
+}
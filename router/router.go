@@ -0,0 +1,201 @@
+// Package router classifies an incoming input and dispatches it to one of
+// several configured, specialized agents.
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/examples/internal/agentrun"
+)
+
+// Route is a named destination for inputs.
+type Route struct {
+	// Name uniquely identifies the route
+	Name string
+
+	// Description is used by classifiers to understand what the route handles
+	Description string
+
+	// Keywords are used by the KeywordClassifier. Matching is case insensitive
+	// and by whole words, so "add" does not match "address".
+	Keywords []string
+
+	// Examples are representative inputs for this route used by the
+	// EmbeddingClassifier.
+	Examples []string
+
+	// New creates the agent that handles an input dispatched to this route.
+	// Every input gets a fresh agent, so inputs never share memory.
+	New func() (agentrun.Runner, error)
+}
+
+// Decision is the outcome of classifying an input.
+type Decision struct {
+	// Route is the name of the chosen route
+	Route string
+
+	// Reason is a human readable explanation of why the route was chosen
+	Reason string
+
+	// Score is a classifier specific confidence score in the range 0.0-1.0
+	Score float64
+
+	// Classifier is the name of the classifier that made the decision
+	Classifier string
+}
+
+// Classifier chooses a route for the given input. A classifier returns a nil
+// decision (and no error) when it cannot confidently choose a route.
+type Classifier interface {
+	Name() string
+	Classify(ctx context.Context, input string, routes []*Route) (*Decision, error)
+}
+
+// RouterOpts configures a new Router.
+type RouterOpts struct {
+	Routes []*Route
+
+	// Classifiers are tried, in order, until one returns a decision.
+	Classifiers []Classifier
+
+	// DefaultRoute is used when no classifier returns a decision. When empty,
+	// unclassified inputs return ErrNoRoute.
+	DefaultRoute string
+
+	Logger *logr.Logger
+}
+
+// Router dispatches inputs to agents.
+type Router struct {
+	routes       []*Route
+	byName       map[string]*Route
+	classifiers  []Classifier
+	defaultRoute string
+
+	logger *logr.Logger
+}
+
+// Result is the outcome of a routed run.
+type Result struct {
+	Decision *Decision
+
+	// Result is the chosen agent's run result
+	Result *agent.AgentRunAggregator
+}
+
+// ErrNoRoute is returned when an input could not be routed.
+var ErrNoRoute = errors.New("no route matched input")
+
+// NewRouter creates a new Router.
+func NewRouter(opts *RouterOpts) (*Router, error) {
+	if len(opts.Routes) == 0 {
+		return nil, errors.New("router must have at least one route")
+	}
+
+	r := &Router{
+		routes:       opts.Routes,
+		byName:       make(map[string]*Route),
+		classifiers:  opts.Classifiers,
+		defaultRoute: opts.DefaultRoute,
+		logger:       opts.Logger,
+	}
+
+	for _, route := range opts.Routes {
+		if route.Name == "" {
+			return nil, errors.New("route must have a name")
+		}
+
+		if route.New == nil {
+			return nil, fmt.Errorf("route %s has no agent constructor", route.Name)
+		}
+
+		if _, ok := r.byName[route.Name]; ok {
+			return nil, fmt.Errorf("route %s already registered", route.Name)
+		}
+
+		r.byName[route.Name] = route
+	}
+
+	if r.defaultRoute != "" {
+		if _, ok := r.byName[r.defaultRoute]; !ok {
+			return nil, fmt.Errorf("default route %s not found", r.defaultRoute)
+		}
+	}
+
+	return r, nil
+}
+
+// Classify chooses a route for the input without running any agent.
+func (r *Router) Classify(ctx context.Context, input string) (*Decision, error) {
+	for _, c := range r.classifiers {
+		decision, err := c.Classify(ctx, input, r.routes)
+		if err != nil {
+			// A failing classifier should not prevent the next from trying
+			if r.logger != nil {
+				r.logger.V(-1).Info("classifier failed", "classifier", c.Name(), "error", err)
+			}
+			continue
+		}
+
+		if decision == nil {
+			continue
+		}
+
+		if _, ok := r.byName[decision.Route]; !ok {
+			if r.logger != nil {
+				r.logger.V(-1).Info("classifier chose unknown route", "classifier", c.Name(), "route", decision.Route)
+			}
+			continue
+		}
+
+		decision.Classifier = c.Name()
+		return decision, nil
+	}
+
+	if r.defaultRoute != "" {
+		return &Decision{
+			Route:      r.defaultRoute,
+			Reason:     "no classifier matched, using the default route",
+			Classifier: "default",
+		}, nil
+	}
+
+	return nil, ErrNoRoute
+}
+
+// Run classifies the input and runs the chosen route's agent with it. Any
+// additional run options (images, stop conditions, etc.) are passed along.
+func (r *Router) Run(ctx context.Context, input string, opts ...agent.RunOptionFunc) (*Result, error) {
+	decision, err := r.Classify(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.logger != nil {
+		r.logger.V(1).Info("routing input",
+			"route", decision.Route,
+			"classifier", decision.Classifier,
+			"reason", decision.Reason,
+		)
+	}
+
+	route := r.byName[decision.Route]
+	runOpts := append([]agent.RunOptionFunc{agent.WithInput(input)}, opts...)
+
+	runner, err := route.New()
+	if err != nil {
+		return nil, fmt.Errorf("error creating agent for route %s: %w", route.Name, err)
+	}
+
+	agg, err := runner.Run(ctx, runOpts...)
+
+	return &Result{
+		Decision: decision,
+		Result:   agg,
+	}, err
+}
@@ -0,0 +1,307 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/examples/internal/agentrun"
+	"github.com/agent-api/examples/vectorstorer"
+)
+
+// recordingRunner answers every run with its name and records the input.
+type recordingRunner struct {
+	name  string
+	input string
+}
+
+func (r *recordingRunner) Run(ctx context.Context, opts ...agent.RunOptionFunc) (*agent.AgentRunAggregator, error) {
+	runOpts := &agent.RunOptions{}
+	for _, opt := range opts {
+		opt(runOpts)
+	}
+
+	r.input = runOpts.Input
+
+	agg := agent.NewAgentRunAggregator()
+	agg.Messages = append(agg.Messages, &core.Message{Role: core.AssistantMessageRole, Content: r.name})
+
+	return agg, nil
+}
+
+// testRoute returns a route whose agents are appended to created.
+func testRoute(name string, created *[]*recordingRunner, keywords ...string) *Route {
+	return &Route{
+		Name:        name,
+		Description: "handles " + name,
+		Keywords:    keywords,
+		New: func() (agentrun.Runner, error) {
+			r := &recordingRunner{name: name}
+			*created = append(*created, r)
+			return r, nil
+		},
+	}
+}
+
+// staticClassifier always returns its decision or error.
+type staticClassifier struct {
+	decision *Decision
+	err      error
+}
+
+func (s *staticClassifier) Name() string {
+	return "static"
+}
+
+func (s *staticClassifier) Classify(ctx context.Context, input string, routes []*Route) (*Decision, error) {
+	return s.decision, s.err
+}
+
+func TestKeywordClassifier(t *testing.T) {
+	created := []*recordingRunner{}
+	routes := []*Route{
+		testRoute("calc", &created, "add", "multiply", "sum"),
+		testRoute("spells", &created, "fire bolt", "spell"),
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "Add 2 and 3", want: "calc"},
+		{input: "What is the sum? Multiply it by 2", want: "calc"},
+		{input: "How does the Fire-Bolt spell work?", want: "spells"},
+		{input: "What is my address?"},
+		{input: "Write a summary of the meeting"},
+		{input: "Is a bolt of fire a spell?", want: "spells"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			decision, err := NewKeywordClassifier().Classify(context.Background(), tt.input, routes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := ""
+			if decision != nil {
+				got = decision.Route
+			}
+
+			if got != tt.want {
+				t.Errorf("got route %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// routeProvider answers every request with its content.
+type routeProvider struct {
+	content string
+}
+
+func (p *routeProvider) GetCapabilities(ctx context.Context) (*core.Capabilities, error) {
+	return &core.Capabilities{}, nil
+}
+
+func (p *routeProvider) UseModel(ctx context.Context, model *core.Model) error {
+	return nil
+}
+
+func (p *routeProvider) Generate(ctx context.Context, opts *core.GenerateOptions) (*core.Message, error) {
+	return &core.Message{Role: core.AssistantMessageRole, Content: p.content}, nil
+}
+
+func (p *routeProvider) GenerateStream(ctx context.Context, opts *core.GenerateOptions) (<-chan *core.Message, <-chan string, <-chan error) {
+	return nil, nil, nil
+}
+
+func TestLLMClassifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "fenced JSON",
+			content: "```json\n{\"route\": \"spells\", \"reason\": \"asks about a spell\"}\n```",
+			want:    "spells",
+		},
+		{
+			name:    "no route fits",
+			content: `{"route": "", "reason": "small talk"}`,
+		},
+		{
+			name:    "not JSON",
+			content: "spells",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, err := NewLLMClassifier(&routeProvider{content: tt.content}).Classify(context.Background(), "input", nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := ""
+			if decision != nil {
+				got = decision.Route
+			}
+
+			if got != tt.want {
+				t.Errorf("got route %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmbeddingClassifier(t *testing.T) {
+	embedder := vectorstorer.NewHashEmbedder(&vectorstorer.HashEmbedderOpts{})
+	embeds := 0
+	embed := func(ctx context.Context, text string) ([]float32, error) {
+		embeds++
+		vectors, err := embedder.Embed(ctx, []string{text})
+		if err != nil {
+			return nil, err
+		}
+
+		return vectors[0], nil
+	}
+
+	routes := []*Route{
+		{Name: "calc", Description: "arithmetic", Examples: []string{"add two numbers together"}},
+		{Name: "spells", Description: "spell rules", Examples: []string{"how far does a fire bolt reach"}},
+	}
+
+	classifier := NewEmbeddingClassifier(embed, 0.3)
+
+	decision, err := classifier.Classify(context.Background(), "how far does ray of frost reach", routes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decision == nil || decision.Route != "spells" {
+		t.Fatalf("got decision %+v", decision)
+	}
+
+	// route texts are embedded once
+	embeds = 0
+	if _, err := classifier.Classify(context.Background(), "unrelated words entirely", routes); err != nil {
+		t.Fatal(err)
+	}
+
+	if embeds != 1 {
+		t.Errorf("embedded %d texts for a second input, want 1", embeds)
+	}
+
+	decision, err = classifier.Classify(context.Background(), "zebra", routes)
+	if err != nil || decision != nil {
+		t.Errorf("got decision %+v, %v below the threshold", decision, err)
+	}
+}
+
+func TestRouterRun(t *testing.T) {
+	ctx := context.Background()
+	created := []*recordingRunner{}
+
+	r, err := NewRouter(&RouterOpts{
+		Routes: []*Route{
+			testRoute("calc", &created, "add"),
+			testRoute("chat", &created),
+		},
+		Classifiers: []Classifier{
+			&staticClassifier{err: errors.New("unavailable")},
+			&staticClassifier{decision: &Decision{Route: "unknown"}},
+			NewKeywordClassifier(),
+		},
+		DefaultRoute: "chat",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		input          string
+		wantRoute      string
+		wantClassifier string
+	}{
+		{input: "add 2 and 3", wantRoute: "calc", wantClassifier: "keyword"},
+		{input: "add 4 and 5", wantRoute: "calc", wantClassifier: "keyword"},
+		{input: "hello", wantRoute: "chat", wantClassifier: "default"},
+	}
+
+	for i, tt := range tests {
+		result, err := r.Run(ctx, tt.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Decision.Route != tt.wantRoute || result.Decision.Classifier != tt.wantClassifier {
+			t.Errorf("%q: got decision %+v", tt.input, result.Decision)
+		}
+
+		// every input gets a new agent
+		if len(created) != i+1 || created[i].input != tt.input {
+			t.Fatalf("%q: created %d agents, the last run with %q", tt.input, len(created), created[len(created)-1].input)
+		}
+
+		if got := result.Result.Pop().Content; got != tt.wantRoute {
+			t.Errorf("%q: answered by %q", tt.input, got)
+		}
+	}
+
+	noDefault, err := NewRouter(&RouterOpts{Routes: []*Route{testRoute("calc", &created, "add")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := noDefault.Run(ctx, "hello"); !errors.Is(err, ErrNoRoute) {
+		t.Errorf("got error %v, want ErrNoRoute", err)
+	}
+}
+
+func TestNewRouterValidation(t *testing.T) {
+	created := []*recordingRunner{}
+
+	tests := []struct {
+		name string
+		opts *RouterOpts
+	}{
+		{
+			name: "no routes",
+			opts: &RouterOpts{},
+		},
+		{
+			name: "no agent constructor",
+			opts: &RouterOpts{Routes: []*Route{{Name: "calc"}}},
+		},
+		{
+			name: "duplicate route",
+			opts: &RouterOpts{Routes: []*Route{testRoute("calc", &created), testRoute("calc", &created)}},
+		},
+		{
+			name: "unknown default route",
+			opts: &RouterOpts{Routes: []*Route{testRoute("calc", &created)}, DefaultRoute: "chat"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewRouter(tt.opts); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
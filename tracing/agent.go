@@ -0,0 +1,145 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/agent-api/core/agent"
+)
+
+// AgentOpts configures a new traced Agent.
+type AgentOpts struct {
+	// Name is recorded as the gen_ai.agent.name attribute
+	Name string
+
+	// TracerProvider defaults to the global otel TracerProvider
+	TracerProvider trace.TracerProvider
+}
+
+// Agent wraps an *agent.Agent so that every Run and RunStream happens within
+// an "invoke_agent" span. Provider and tool spans become its children as long
+// as the agent's provider and tools are wrapped as well.
+type Agent struct {
+	*agent.Agent
+
+	name   string
+	tracer trace.Tracer
+}
+
+// NewAgent creates a new traced Agent.
+func NewAgent(a *agent.Agent, opts *AgentOpts) *Agent {
+	tp := opts.TracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+
+	return &Agent{
+		Agent:  a,
+		name:   opts.Name,
+		tracer: tp.Tracer(InstrumentationName, trace.WithSchemaURL(semconv.SchemaURL)),
+	}
+}
+
+func (a *Agent) startSpan(ctx context.Context) (context.Context, trace.Span) {
+	return a.tracer.Start(ctx, operationInvokeAgent+" "+a.name,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(
+			semconv.GenAIOperationNameKey.String(operationInvokeAgent),
+			GenAIAgentNameKey.String(a.name),
+		),
+	)
+}
+
+// Run runs the agent within a span.
+func (a *Agent) Run(ctx context.Context, opts ...agent.RunOptionFunc) (*agent.AgentRunAggregator, error) {
+	ctx, span := a.startSpan(ctx)
+	defer span.End()
+
+	agg, err := a.Agent.Run(ctx, opts...)
+	if agg != nil {
+		span.SetAttributes(RunMessageCountKey.Int(len(agg.Messages)))
+	}
+
+	if err != nil {
+		recordError(span, err)
+	}
+
+	return agg, err
+}
+
+// RunStream runs the agent within a span that ends once the run's channels
+// are all closed.
+func (a *Agent) RunStream(ctx context.Context, opts ...agent.RunOptionFunc) *agent.StreamRunnerResults {
+	ctx, span := a.startSpan(ctx)
+
+	results := a.Agent.RunStream(ctx, opts...)
+
+	// buffered, non-blocking channels
+	outAggChan := make(chan agent.AgentRunAggregator, 10)
+	outDeltaChan := make(chan string, 10)
+	outErrChan := make(chan error, 10)
+
+	go func() {
+		defer span.End()
+		defer close(outAggChan)
+		defer close(outDeltaChan)
+		defer close(outErrChan)
+
+		aggChan, deltaChan, errChan := results.AggChan, results.DeltaChan, results.ErrChan
+		messages := 0
+
+		for aggChan != nil || deltaChan != nil || errChan != nil {
+			// forward like the agent does: skip if no one is listening
+			select {
+			case agg, ok := <-aggChan:
+				if !ok {
+					aggChan = nil
+					continue
+				}
+
+				messages = len(agg.Messages)
+				select {
+				case outAggChan <- agg:
+				default:
+				}
+
+			case delta, ok := <-deltaChan:
+				if !ok {
+					deltaChan = nil
+					continue
+				}
+
+				select {
+				case outDeltaChan <- delta:
+				default:
+				}
+
+			case err, ok := <-errChan:
+				if !ok {
+					errChan = nil
+					continue
+				}
+
+				if err != nil {
+					recordError(span, err)
+				}
+
+				select {
+				case outErrChan <- err:
+				default:
+				}
+			}
+		}
+
+		span.SetAttributes(RunMessageCountKey.Int(messages))
+	}()
+
+	return &agent.StreamRunnerResults{
+		AggChan:   outAggChan,
+		DeltaChan: outDeltaChan,
+		ErrChan:   outErrChan,
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-logr/zapr"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/core/agent/bootstrap"
+	"github.com/agent-api/examples/tracing"
+	"github.com/agent-api/openai"
+	"github.com/agent-api/openai/models"
+)
+
+const jsonSchema string = `{
+  "title": "calculator",
+  "description": "A simple calculator on ints",
+  "type": "object",
+  "properties": {
+    "a": {
+      "description": "The first operand",
+      "type": "number"
+    },
+    "b": {
+      "description": "The second operand",
+      "type": "number"
+    },
+    "operation": {
+      "description": "The operation to perform. One of [add, multiply]",
+      "type": "string"
+    }
+  },
+  "required": [
+    "operation",
+    "a",
+    "b"
+  ]
+}`
+
+type calculatorParams struct {
+	Operation string `json:"operation"`
+	A         int    `json:"a"`
+	B         int    `json:"b"`
+}
+
+func calculator(ctx context.Context, args *calculatorParams) (interface{}, error) {
+	switch args.Operation {
+	case "add":
+		return args.A + args.B, nil
+	case "multiply":
+		return args.A * args.B, nil
+	default:
+		return nil, fmt.Errorf("unsupported operation: %s", args.Operation)
+	}
+}
+
+func main() {
+	ctx := context.Background()
+
+	// Create a zap logger
+	config := zap.NewDevelopmentConfig()
+	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	zLogger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a logr.Logger using zapr adapter
+	logger := zapr.NewLogger(zLogger)
+
+	// Export to a collector when one is configured through the standard otel
+	// environment variables, otherwise keep spans in memory and print them.
+	var tp *sdktrace.TracerProvider
+	var exporter *tracetest.InMemoryExporter
+
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" {
+		tp, err = tracing.NewOTLPTracerProvider(ctx, &tracing.OTLPOpts{
+			ServiceName: "otel-agent-example",
+		})
+		if err != nil {
+			panic(err)
+		}
+	} else {
+		tp, exporter = tracing.NewInMemoryTracerProvider()
+	}
+	defer tp.Shutdown(ctx)
+	otel.SetTracerProvider(tp)
+
+	// Create a traced openai provider
+	provider := tracing.NewProvider(&tracing.ProviderOpts{
+		Provider: openai.NewProvider(&openai.ProviderOpts{
+			Logger: &logger,
+		}),
+		System: "openai",
+	})
+	provider.UseModel(ctx, models.GPT4_O)
+
+	wrappedCalc, err := core.WrapToolFunction(calculator)
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a new agent with a traced calculator tool
+	baseAgent, err := agent.NewAgent(
+		bootstrap.WithProvider(provider),
+		bootstrap.WithLogger(&logger),
+		bootstrap.WithTools(tracing.WrapTools(nil, &core.Tool{
+			Name:                "calculator",
+			Description:         "Performs basic arithmetic operations: supported operations are 'add' and 'multiply'",
+			WrappedToolFunction: wrappedCalc,
+			JSONSchema:          []byte(jsonSchema),
+		})...),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	myAgent := tracing.NewAgent(baseAgent, &tracing.AgentOpts{
+		Name: "calculator_agent",
+	})
+
+	response, err := myAgent.Run(
+		ctx,
+		agent.WithInput("What is 987 * 123?"),
+	)
+	if err != nil {
+		logger.Error(err, "failed sending message to agent")
+		return
+	}
+
+	fmt.Println("Agent response:", response.Messages[len(response.Messages)-1].Content)
+
+	if exporter != nil {
+		for _, span := range exporter.GetSpans() {
+			fmt.Printf("span: %s (trace %s, %s)\n", span.Name, span.SpanContext.TraceID(), span.EndTime.Sub(span.StartTime))
+			for _, attr := range span.Attributes {
+				fmt.Printf("  %s = %s\n", attr.Key, attr.Value.Emit())
+			}
+		}
+	}
+}
@@ -0,0 +1,237 @@
+// Package tracing instruments agent runs, provider calls and tool executions
+// with OpenTelemetry spans following the GenAI semantic conventions.
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/examples/usage"
+)
+
+// InstrumentationName is the name of the tracer used by this package.
+const InstrumentationName string = "github.com/agent-api/examples/tracing"
+
+// Attribute keys from newer GenAI semantic conventions that are not yet part
+// of the semconv package, plus agent-api specific attributes.
+const (
+	GenAIToolNameKey     = attribute.Key("gen_ai.tool.name")
+	GenAIAgentNameKey    = attribute.Key("gen_ai.agent.name")
+	ToolArgumentsHashKey = attribute.Key("agent_api.tool.arguments_sha256")
+	ToolCallCountKey     = attribute.Key("agent_api.response.tool_calls")
+	TimeToFirstTokenKey  = attribute.Key("agent_api.stream.time_to_first_token_ms")
+	UsageEstimatedKey    = attribute.Key("agent_api.usage.estimated")
+	RunMessageCountKey   = attribute.Key("agent_api.run.messages")
+)
+
+const (
+	operationInvokeAgent string = "invoke_agent"
+	operationExecuteTool string = "execute_tool"
+
+	finishReasonToolCalls string = "tool_calls"
+	finishReasonStop      string = "stop"
+	finishReasonError     string = "error"
+)
+
+// ProviderOpts configures a new traced Provider.
+type ProviderOpts struct {
+	// The wrapped core.Provider
+	Provider core.Provider
+
+	// System is the gen_ai.system attribute, i.e., "openai", "anthropic",
+	// "gemini" or "ollama"
+	System string
+
+	// Model is recorded as the requested model. It only needs to be set when
+	// UseModel was called on the wrapped provider rather than this one.
+	Model *core.Model
+
+	// TracerProvider defaults to the global otel TracerProvider
+	TracerProvider trace.TracerProvider
+}
+
+// Provider is a core.Provider that creates a span for every Generate and
+// GenerateStream call.
+type Provider struct {
+	provider core.Provider
+	system   string
+	model    *core.Model
+	tracer   trace.Tracer
+}
+
+// NewProvider creates a new traced Provider.
+func NewProvider(opts *ProviderOpts) *Provider {
+	tp := opts.TracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+
+	return &Provider{
+		provider: opts.Provider,
+		system:   opts.System,
+		model:    opts.Model,
+		tracer:   tp.Tracer(InstrumentationName, trace.WithSchemaURL(semconv.SchemaURL)),
+	}
+}
+
+func (p *Provider) GetCapabilities(ctx context.Context) (*core.Capabilities, error) {
+	return p.provider.GetCapabilities(ctx)
+}
+
+func (p *Provider) UseModel(ctx context.Context, model *core.Model) error {
+	p.model = model
+	return p.provider.UseModel(ctx, model)
+}
+
+func (p *Provider) startSpan(ctx context.Context, opts *core.GenerateOptions) (context.Context, trace.Span) {
+	modelID := ""
+	if p.model != nil {
+		modelID = p.model.ID
+	}
+
+	attrs := []attribute.KeyValue{
+		semconv.GenAIOperationNameChat,
+		semconv.GenAISystemKey.String(p.system),
+		semconv.GenAIRequestModel(modelID),
+	}
+
+	if opts.MaxTokens > 0 {
+		attrs = append(attrs, semconv.GenAIRequestMaxTokens(opts.MaxTokens))
+	}
+
+	if opts.Temperature > 0 {
+		attrs = append(attrs, semconv.GenAIRequestTemperature(opts.Temperature))
+	}
+
+	if opts.TopP > 0 {
+		attrs = append(attrs, semconv.GenAIRequestTopP(opts.TopP))
+	}
+
+	// span names follow "{gen_ai.operation.name} {gen_ai.request.model}"
+	return p.tracer.Start(ctx, "chat "+modelID,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// Generate calls the wrapped provider within a span.
+func (p *Provider) Generate(ctx context.Context, opts *core.GenerateOptions) (*core.Message, error) {
+	ctx, span := p.startSpan(ctx, opts)
+	defer span.End()
+
+	msg, err := p.provider.Generate(ctx, opts)
+	if err != nil {
+		recordError(span, err)
+		span.SetAttributes(semconv.GenAIResponseFinishReasons(finishReasonError))
+		return nil, err
+	}
+
+	endResponse(span, opts, msg)
+
+	return msg, nil
+}
+
+// GenerateStream calls the wrapped provider within a span that ends once the
+// stream is complete.
+func (p *Provider) GenerateStream(ctx context.Context, opts *core.GenerateOptions) (<-chan *core.Message, <-chan string, <-chan error) {
+	ctx, span := p.startSpan(ctx, opts)
+	start := time.Now()
+
+	msgChan, deltaChan, errChan := p.provider.GenerateStream(ctx, opts)
+	if msgChan == nil && deltaChan == nil && errChan == nil {
+		span.End()
+		return nil, nil, nil
+	}
+
+	// buffered, non-blocking channels
+	outMsgChan := make(chan *core.Message, 10)
+	outDeltaChan := make(chan string, 10)
+	outErrChan := make(chan error, 10)
+
+	go func() {
+		defer span.End()
+		defer close(outMsgChan)
+		defer close(outDeltaChan)
+		defer close(outErrChan)
+
+		var last *core.Message
+		firstToken := true
+
+		for msgChan != nil || deltaChan != nil || errChan != nil {
+			select {
+			case msg, ok := <-msgChan:
+				if !ok {
+					msgChan = nil
+					continue
+				}
+
+				if msg != nil {
+					last = msg
+				}
+				outMsgChan <- msg
+
+			case delta, ok := <-deltaChan:
+				if !ok {
+					deltaChan = nil
+					continue
+				}
+
+				if firstToken && delta != "" {
+					firstToken = false
+					ttft := time.Since(start)
+					span.AddEvent("first_token")
+					span.SetAttributes(TimeToFirstTokenKey.Int64(ttft.Milliseconds()))
+				}
+				outDeltaChan <- delta
+
+			case err, ok := <-errChan:
+				if !ok {
+					errChan = nil
+					continue
+				}
+
+				if err != nil {
+					recordError(span, err)
+				}
+				outErrChan <- err
+			}
+		}
+
+		if last != nil {
+			endResponse(span, opts, last)
+		}
+	}()
+
+	return outMsgChan, outDeltaChan, outErrChan
+}
+
+// endResponse records the response attributes on a provider span.
+func endResponse(span trace.Span, opts *core.GenerateOptions, msg *core.Message) {
+	finishReason := finishReasonStop
+	if len(msg.ToolCalls) > 0 {
+		finishReason = finishReasonToolCalls
+	}
+
+	u := usage.FromResponse(opts, msg)
+
+	span.SetAttributes(
+		semconv.GenAIResponseFinishReasons(finishReason),
+		semconv.GenAIUsageInputTokens(u.PromptTokens),
+		semconv.GenAIUsageOutputTokens(u.CompletionTokens),
+		UsageEstimatedKey.Bool(u.Estimated),
+		ToolCallCountKey.Int(len(msg.ToolCalls)),
+	)
+}
+
+func recordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	span.SetAttributes(semconv.ErrorTypeOther)
+}
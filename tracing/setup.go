@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+)
+
+// NewInMemoryTracerProvider returns a TracerProvider that synchronously
+// exports every span to the returned in-memory exporter. It is meant for tests
+// and local debugging: spans can be inspected with exporter.GetSpans().
+func NewInMemoryTracerProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(exporter),
+	)
+
+	return tp, exporter
+}
+
+// OTLPOpts configures an OTLP/HTTP trace exporter.
+type OTLPOpts struct {
+	// Endpoint is the collector host and port, i.e., "localhost:4318". When
+	// empty, the OTEL_EXPORTER_OTLP_ENDPOINT environment variable or the
+	// exporter's default is used.
+	Endpoint string
+
+	// Insecure disables TLS
+	Insecure bool
+
+	// Headers are sent with every export request, i.e., for authentication
+	Headers map[string]string
+
+	// ServiceName is recorded as the service.name resource attribute
+	ServiceName string
+
+	// SampleRatio is the fraction of traces to sample (0.0-1.0). Defaults to
+	// sampling every trace.
+	SampleRatio float64
+}
+
+// NewOTLPTracerProvider returns a TracerProvider that batches and exports
+// spans to an OTLP collector. Callers should call Shutdown on the returned
+// provider before exiting to flush any remaining spans.
+func NewOTLPTracerProvider(ctx context.Context, opts *OTLPOpts) (*sdktrace.TracerProvider, error) {
+	exporterOpts := []otlptracehttp.Option{}
+
+	if opts.Endpoint != "" {
+		exporterOpts = append(exporterOpts, otlptracehttp.WithEndpoint(opts.Endpoint))
+	}
+
+	if opts.Insecure {
+		exporterOpts = append(exporterOpts, otlptracehttp.WithInsecure())
+	}
+
+	if len(opts.Headers) > 0 {
+		exporterOpts = append(exporterOpts, otlptracehttp.WithHeaders(opts.Headers))
+	}
+
+	exporter, err := otlptracehttp.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating otlp exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(semconv.ServiceName(opts.ServiceName)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating trace resource: %w", err)
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if opts.SampleRatio > 0 && opts.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(opts.SampleRatio)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+	), nil
+}
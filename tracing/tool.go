@@ -0,0 +1,60 @@
+package tracing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/agent-api/core"
+)
+
+// WrapTool returns a copy of the tool whose function runs within an
+// "execute_tool" span. Arguments are recorded as a SHA-256 hash so that
+// sensitive values never end up in traces. A nil TracerProvider uses the
+// global otel TracerProvider.
+func WrapTool(tp trace.TracerProvider, tool *core.Tool) *core.Tool {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+
+	tracer := tp.Tracer(InstrumentationName, trace.WithSchemaURL(semconv.SchemaURL))
+	fn := tool.WrappedToolFunction
+
+	wrapped := *tool
+	wrapped.WrappedToolFunction = func(ctx context.Context, args []byte) (interface{}, error) {
+		hash := sha256.Sum256(args)
+
+		ctx, span := tracer.Start(ctx, operationExecuteTool+" "+tool.Name,
+			trace.WithSpanKind(trace.SpanKindInternal),
+			trace.WithAttributes(
+				semconv.GenAIOperationNameKey.String(operationExecuteTool),
+				GenAIToolNameKey.String(tool.Name),
+				ToolArgumentsHashKey.String(hex.EncodeToString(hash[:])),
+			),
+		)
+		defer span.End()
+
+		result, err := fn(ctx, args)
+		if err != nil {
+			recordError(span, err)
+		}
+
+		return result, err
+	}
+
+	return &wrapped
+}
+
+// WrapTools wraps every tool with WrapTool.
+func WrapTools(tp trace.TracerProvider, tools ...*core.Tool) []*core.Tool {
+	wrapped := make([]*core.Tool, len(tools))
+	for i, t := range tools {
+		wrapped[i] = WrapTool(tp, t)
+	}
+
+	return wrapped
+}
@@ -0,0 +1,237 @@
+package tracing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/core/agent/bootstrap"
+	"github.com/agent-api/examples/usage"
+)
+
+// scriptedProvider returns its responses in order, one per Generate call.
+type scriptedProvider struct {
+	responses []*core.Message
+	err       error
+}
+
+func (p *scriptedProvider) GetCapabilities(ctx context.Context) (*core.Capabilities, error) {
+	return &core.Capabilities{}, nil
+}
+
+func (p *scriptedProvider) UseModel(ctx context.Context, model *core.Model) error {
+	return nil
+}
+
+func (p *scriptedProvider) Generate(ctx context.Context, opts *core.GenerateOptions) (*core.Message, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	msg := p.responses[0]
+	p.responses = p.responses[1:]
+
+	return msg, nil
+}
+
+func (p *scriptedProvider) GenerateStream(ctx context.Context, opts *core.GenerateOptions) (<-chan *core.Message, <-chan string, <-chan error) {
+	return nil, nil, nil
+}
+
+func attributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+
+	return attrs
+}
+
+func checkAttributes(t *testing.T, span tracetest.SpanStub, want ...attribute.KeyValue) {
+	t.Helper()
+
+	attrs := attributes(span)
+	for _, kv := range want {
+		got, ok := attrs[kv.Key]
+		if !ok {
+			t.Errorf("span %q has no %s attribute", span.Name, kv.Key)
+			continue
+		}
+
+		if got.Emit() != kv.Value.Emit() {
+			t.Errorf("span %q has %s %s, want %s", span.Name, kv.Key, got.Emit(), kv.Value.Emit())
+		}
+	}
+}
+
+func TestTracedAgentRun(t *testing.T) {
+	tp, exporter := NewInMemoryTracerProvider()
+
+	args := json.RawMessage(`{"a":2,"b":3}`)
+	argsHash := sha256.Sum256(args)
+
+	provider := NewProvider(&ProviderOpts{
+		Provider: &scriptedProvider{
+			responses: []*core.Message{
+				{
+					Role: core.AssistantMessageRole,
+					ToolCalls: []*core.ToolCall{
+						{ID: "call-1", Name: "calculator", Arguments: args},
+					},
+					Metadata: &core.Metadata{
+						ProviderProperties: map[string]string{
+							usage.PromptTokensProperty:     "12",
+							usage.CompletionTokensProperty: "7",
+						},
+					},
+				},
+				{
+					Role:    core.AssistantMessageRole,
+					Content: "The answer is 5",
+				},
+			},
+		},
+		System:         "test",
+		TracerProvider: tp,
+	})
+	provider.UseModel(context.Background(), &core.Model{ID: "test-model"})
+
+	calculator := WrapTool(tp, &core.Tool{
+		Name:        "calculator",
+		Description: "Adds two numbers",
+		WrappedToolFunction: func(ctx context.Context, args []byte) (interface{}, error) {
+			return 5, nil
+		},
+	})
+
+	a, err := agent.NewAgent(
+		bootstrap.WithProvider(provider),
+		bootstrap.WithTools(calculator),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	traced := NewAgent(a, &AgentOpts{Name: "calc_agent", TracerProvider: tp})
+
+	if _, err := traced.Run(context.Background(), agent.WithInput("What is 2 + 3?")); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+
+	names := []string{}
+	for _, s := range spans {
+		names = append(names, s.Name)
+	}
+
+	// spans are exported as they end: children before their parent
+	want := []string{"chat test-model", "execute_tool calculator", "chat test-model", "invoke_agent calc_agent"}
+	if len(names) != len(want) {
+		t.Fatalf("got spans %v, want %v", names, want)
+	}
+
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("got spans %v, want %v", names, want)
+		}
+	}
+
+	agentSpan := spans[3]
+	for _, s := range spans[:3] {
+		if s.Parent.SpanID() != agentSpan.SpanContext.SpanID() {
+			t.Errorf("span %q is not a child of the agent span", s.Name)
+		}
+	}
+
+	checkAttributes(t, agentSpan,
+		semconv.GenAIOperationNameKey.String(operationInvokeAgent),
+		GenAIAgentNameKey.String("calc_agent"),
+		RunMessageCountKey.Int(4),
+	)
+
+	if agentSpan.SpanKind != trace.SpanKindInternal {
+		t.Errorf("agent span has kind %s", agentSpan.SpanKind)
+	}
+
+	chatSpan := spans[0]
+	checkAttributes(t, chatSpan,
+		semconv.GenAIOperationNameChat,
+		semconv.GenAISystemKey.String("test"),
+		semconv.GenAIRequestModel("test-model"),
+		semconv.GenAIResponseFinishReasons(finishReasonToolCalls),
+		semconv.GenAIUsageInputTokens(12),
+		semconv.GenAIUsageOutputTokens(7),
+		UsageEstimatedKey.Bool(false),
+		ToolCallCountKey.Int(1),
+	)
+
+	if chatSpan.SpanKind != trace.SpanKindClient {
+		t.Errorf("chat span has kind %s", chatSpan.SpanKind)
+	}
+
+	toolSpan := spans[1]
+	checkAttributes(t, toolSpan,
+		semconv.GenAIOperationNameKey.String(operationExecuteTool),
+		GenAIToolNameKey.String("calculator"),
+		ToolArgumentsHashKey.String(hex.EncodeToString(argsHash[:])),
+	)
+
+	if toolSpan.SpanKind != trace.SpanKindInternal {
+		t.Errorf("tool span has kind %s", toolSpan.SpanKind)
+	}
+
+	if toolSpan.Status.Code != codes.Unset {
+		t.Errorf("tool span has status %s", toolSpan.Status.Code)
+	}
+
+	checkAttributes(t, spans[2],
+		semconv.GenAIResponseFinishReasons(finishReasonStop),
+		UsageEstimatedKey.Bool(true),
+		ToolCallCountKey.Int(0),
+	)
+}
+
+func TestTracedProviderError(t *testing.T) {
+	tp, exporter := NewInMemoryTracerProvider()
+
+	provider := NewProvider(&ProviderOpts{
+		Provider:       &scriptedProvider{err: errors.New("unavailable")},
+		System:         "test",
+		Model:          &core.Model{ID: "test-model"},
+		TracerProvider: tp,
+	})
+
+	if _, err := provider.Generate(context.Background(), &core.GenerateOptions{MaxTokens: 100, Temperature: 0.5}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+
+	span := spans[0]
+	if span.Status.Code != codes.Error || span.Status.Description != "unavailable" {
+		t.Errorf("got status %v", span.Status)
+	}
+
+	checkAttributes(t, span,
+		semconv.GenAIRequestModel("test-model"),
+		semconv.GenAIRequestMaxTokens(100),
+		semconv.GenAIRequestTemperature(0.5),
+		semconv.GenAIResponseFinishReasons(finishReasonError),
+		semconv.ErrorTypeOther,
+	)
+}
@@ -0,0 +1,38 @@
+package usage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// RunInfo identifies the run and agent that provider calls are attributed to.
+type RunInfo struct {
+	ID    string
+	Agent string
+}
+
+type runInfoKey struct{}
+
+// WithRun returns a copy of ctx that attributes provider calls to the given
+// run and agent. Pass it to agent.Run so that every call made during the run
+// is recorded against it.
+func WithRun(ctx context.Context, runID, agentName string) context.Context {
+	return context.WithValue(ctx, runInfoKey{}, &RunInfo{
+		ID:    runID,
+		Agent: agentName,
+	})
+}
+
+// RunFromContext returns the run info carried by ctx, if any.
+func RunFromContext(ctx context.Context) (*RunInfo, bool) {
+	info, ok := ctx.Value(runInfoKey{}).(*RunInfo)
+	return info, ok
+}
+
+// NewRunID returns a random, hex encoded run ID.
+func NewRunID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
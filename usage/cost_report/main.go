@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/core/agent/bootstrap"
+	"github.com/agent-api/examples/usage"
+	"github.com/agent-api/ollama"
+	ollamamodels "github.com/agent-api/ollama/models"
+	"github.com/agent-api/openai"
+	openaimodels "github.com/agent-api/openai/models"
+)
+
+func main() {
+	ctx := context.Background()
+
+	// Create a zap logger
+	config := zap.NewDevelopmentConfig()
+	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	zLogger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a logr.Logger using zapr adapter
+	logger := zapr.NewLogger(zLogger)
+
+	// A single ledger records usage across every provider
+	ledger := usage.NewLedger(usage.DefaultPrices())
+
+	openaiProvider := usage.NewProvider(&usage.ProviderOpts{
+		Provider: openai.NewProvider(&openai.ProviderOpts{
+			Logger: &logger,
+		}),
+		Name:   "openai",
+		Ledger: ledger,
+	})
+	openaiProvider.UseModel(ctx, openaimodels.GPT4_O)
+
+	ollamaProvider := usage.NewProvider(&usage.ProviderOpts{
+		Provider: ollama.NewProvider(&ollama.ProviderOpts{
+			Logger:  &logger,
+			BaseURL: "http://localhost",
+			Port:    11434,
+		}),
+		Name:   "ollama",
+		Ledger: ledger,
+	})
+	ollamaProvider.UseModel(ctx, ollamamodels.QWEN2_5_LATEST)
+
+	openaiAgent, err := agent.NewAgent(
+		bootstrap.WithProvider(openaiProvider),
+		bootstrap.WithLogger(&logger),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	ollamaAgent, err := agent.NewAgent(
+		bootstrap.WithProvider(ollamaProvider),
+		bootstrap.WithLogger(&logger),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	runs := []struct {
+		name  string
+		agent *agent.Agent
+		input string
+	}{
+		{name: "openai_agent", agent: openaiAgent, input: "Why is the sky blue?"},
+		{name: "ollama_agent", agent: ollamaAgent, input: "Why is the ocean salty?"},
+	}
+
+	for _, run := range runs {
+		runID := usage.NewRunID()
+
+		_, err := run.agent.Run(
+			usage.WithRun(ctx, runID, run.name),
+			agent.WithInput(run.input),
+		)
+		if err != nil {
+			logger.Error(err, "run failed", "agent", run.name)
+			continue
+		}
+
+		summary := ledger.RunSummary(runID)
+		fmt.Printf("run %s (%s): %d requests, %d prompt tokens, %d completion tokens, $%.6f\n",
+			runID, run.name, summary.Requests, summary.PromptTokens, summary.CompletionTokens, summary.Cost)
+	}
+
+	report := ledger.Report()
+	fmt.Printf("\ntotal: %d tokens, $%.6f (%d of %d requests estimated)\n",
+		report.Total.TotalTokens(), report.Total.Cost, report.Total.EstimatedRequests, report.Total.Requests)
+
+	for model, summary := range report.ByModel {
+		fmt.Printf("  model %s: %d tokens, $%.6f\n", model, summary.TotalTokens(), summary.Cost)
+	}
+
+	for provider, summary := range report.ByProvider {
+		fmt.Printf("  provider %s: %d tokens, $%.6f\n", provider, summary.TotalTokens(), summary.Cost)
+	}
+}
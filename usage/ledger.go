@@ -0,0 +1,171 @@
+package usage
+
+import (
+	"sync"
+	"time"
+)
+
+// Entry is a single recorded provider response.
+type Entry struct {
+	Time time.Time
+
+	RunID    string
+	Agent    string
+	Provider string
+	Model    string
+
+	Usage *Usage
+
+	// Cost in US dollars. Zero when the model has no price.
+	Cost float64
+
+	// Priced is false when the model was not found in the price table
+	Priced bool
+}
+
+// Summary aggregates a number of entries.
+type Summary struct {
+	Requests         int
+	PromptTokens     int
+	CompletionTokens int
+	Cost             float64
+
+	// EstimatedRequests is the number of requests whose usage was estimated
+	EstimatedRequests int
+
+	// UnpricedRequests is the number of requests for models without a price
+	UnpricedRequests int
+}
+
+// TotalTokens returns the sum of prompt and completion tokens.
+func (s *Summary) TotalTokens() int {
+	return s.PromptTokens + s.CompletionTokens
+}
+
+func (s *Summary) add(e *Entry) {
+	s.Requests++
+	s.PromptTokens += e.Usage.PromptTokens
+	s.CompletionTokens += e.Usage.CompletionTokens
+	s.Cost += e.Cost
+
+	if e.Usage.Estimated {
+		s.EstimatedRequests++
+	}
+
+	if !e.Priced {
+		s.UnpricedRequests++
+	}
+}
+
+// Report is a cumulative breakdown of every recorded entry.
+type Report struct {
+	Total *Summary
+
+	ByRun      map[string]*Summary
+	ByAgent    map[string]*Summary
+	ByProvider map[string]*Summary
+	ByModel    map[string]*Summary
+}
+
+// Ledger records the usage and cost of provider responses. It is safe for
+// concurrent use.
+type Ledger struct {
+	prices PriceTable
+
+	mu      sync.Mutex
+	entries []*Entry
+}
+
+// NewLedger creates a new Ledger with the given price table. A nil table uses
+// DefaultPrices.
+func NewLedger(prices PriceTable) *Ledger {
+	if prices == nil {
+		prices = DefaultPrices()
+	}
+
+	return &Ledger{
+		prices:  prices,
+		entries: []*Entry{},
+	}
+}
+
+// Record prices and stores an entry. The entry's Cost and Priced fields are
+// set from the ledger's price table.
+func (l *Ledger) Record(e *Entry) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	price, ok := l.prices.Lookup(e.Model)
+	e.Priced = ok
+	e.Cost = price.Cost(e.Usage)
+
+	l.mu.Lock()
+	l.entries = append(l.entries, e)
+	l.mu.Unlock()
+}
+
+// Entries returns a copy of every recorded entry.
+func (l *Ledger) Entries() []*Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]*Entry{}, l.entries...)
+}
+
+// RunSummary summarizes every entry recorded for a run.
+func (l *Ledger) RunSummary(runID string) *Summary {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s := &Summary{}
+	for _, e := range l.entries {
+		if e.RunID == runID {
+			s.add(e)
+		}
+	}
+
+	return s
+}
+
+// Report returns the cumulative report of every recorded entry.
+func (l *Ledger) Report() *Report {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r := &Report{
+		Total:      &Summary{},
+		ByRun:      make(map[string]*Summary),
+		ByAgent:    make(map[string]*Summary),
+		ByProvider: make(map[string]*Summary),
+		ByModel:    make(map[string]*Summary),
+	}
+
+	for _, e := range l.entries {
+		r.Total.add(e)
+		summaryFor(r.ByRun, e.RunID).add(e)
+		summaryFor(r.ByAgent, e.Agent).add(e)
+		summaryFor(r.ByProvider, e.Provider).add(e)
+		summaryFor(r.ByModel, e.Model).add(e)
+	}
+
+	return r
+}
+
+// Reset removes every recorded entry.
+func (l *Ledger) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = []*Entry{}
+}
+
+func summaryFor(m map[string]*Summary, key string) *Summary {
+	s, ok := m[key]
+	if !ok {
+		s = &Summary{}
+		m[key] = s
+	}
+
+	return s
+}
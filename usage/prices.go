@@ -0,0 +1,52 @@
+package usage
+
+import (
+	anthropicmodels "github.com/agent-api/anthropic/models"
+	googlegenaimodels "github.com/agent-api/googlegenai/models"
+	ollamamodels "github.com/agent-api/ollama/models"
+	openaimodels "github.com/agent-api/openai/models"
+)
+
+// Price is the cost, in US dollars, per million tokens of a model.
+type Price struct {
+	PromptPerMillion     float64
+	CompletionPerMillion float64
+}
+
+// Cost returns the cost of the given usage at this price.
+func (p Price) Cost(u *Usage) float64 {
+	return float64(u.PromptTokens)/1_000_000*p.PromptPerMillion +
+		float64(u.CompletionTokens)/1_000_000*p.CompletionPerMillion
+}
+
+// PriceTable maps model IDs to their price.
+type PriceTable map[string]Price
+
+// Lookup returns the price of a model and whether it is known.
+func (t PriceTable) Lookup(modelID string) (Price, bool) {
+	p, ok := t[modelID]
+	return p, ok
+}
+
+// DefaultPrices returns a price table with list prices for the models used
+// throughout the examples. Local Ollama models are free. Prices change often:
+// override or extend the returned table for accurate accounting.
+func DefaultPrices() PriceTable {
+	return PriceTable{
+		openaimodels.GPT4_O.ID:      {PromptPerMillion: 2.50, CompletionPerMillion: 10.00},
+		openaimodels.GPT4_O_MINI.ID: {PromptPerMillion: 0.15, CompletionPerMillion: 0.60},
+		openaimodels.GPT4_TURBO.ID:  {PromptPerMillion: 10.00, CompletionPerMillion: 30.00},
+		openaimodels.O1.ID:          {PromptPerMillion: 15.00, CompletionPerMillion: 60.00},
+		openaimodels.O3_MINI.ID:     {PromptPerMillion: 1.10, CompletionPerMillion: 4.40},
+
+		anthropicmodels.CLAUDE_3_7_SONNET.ID:    {PromptPerMillion: 3.00, CompletionPerMillion: 15.00},
+		anthropicmodels.CLAUDE_3_5_SONNET_V2.ID: {PromptPerMillion: 3.00, CompletionPerMillion: 15.00},
+		anthropicmodels.CLAUDE_3_5_SONNET.ID:    {PromptPerMillion: 3.00, CompletionPerMillion: 15.00},
+
+		googlegenaimodels.GEMINI_1_5_FLASH.ID: {PromptPerMillion: 0.075, CompletionPerMillion: 0.30},
+
+		ollamamodels.DEEPSEEK_R1_7B.ID: {},
+		ollamamodels.QWEN2_5_LATEST.ID: {},
+		ollamamodels.GEMMA3_LATEST.ID:  {},
+	}
+}
@@ -0,0 +1,105 @@
+package usage
+
+import (
+	"context"
+
+	"github.com/agent-api/core"
+)
+
+// ProviderOpts configures a new usage recording Provider.
+type ProviderOpts struct {
+	// The wrapped core.Provider
+	Provider core.Provider
+
+	// Name identifies the provider in reports, i.e., "openai"
+	Name string
+
+	// Model is the model the wrapped provider uses. It only needs to be set
+	// when UseModel was called on the wrapped provider rather than this one.
+	Model *core.Model
+
+	Ledger *Ledger
+}
+
+// Provider is a core.Provider that records the usage of every response in a
+// Ledger. Calls are attributed to the run and agent set with WithRun.
+type Provider struct {
+	provider core.Provider
+	name     string
+	model    *core.Model
+	ledger   *Ledger
+}
+
+// NewProvider creates a new usage recording Provider.
+func NewProvider(opts *ProviderOpts) *Provider {
+	return &Provider{
+		provider: opts.Provider,
+		name:     opts.Name,
+		model:    opts.Model,
+		ledger:   opts.Ledger,
+	}
+}
+
+func (p *Provider) GetCapabilities(ctx context.Context) (*core.Capabilities, error) {
+	return p.provider.GetCapabilities(ctx)
+}
+
+func (p *Provider) UseModel(ctx context.Context, model *core.Model) error {
+	p.model = model
+	return p.provider.UseModel(ctx, model)
+}
+
+// Generate calls the wrapped provider and records the response's usage.
+func (p *Provider) Generate(ctx context.Context, opts *core.GenerateOptions) (*core.Message, error) {
+	msg, err := p.provider.Generate(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	p.record(ctx, opts, msg)
+
+	return msg, nil
+}
+
+// GenerateStream calls the wrapped provider and records the usage of each
+// complete message in the stream.
+func (p *Provider) GenerateStream(ctx context.Context, opts *core.GenerateOptions) (<-chan *core.Message, <-chan string, <-chan error) {
+	msgChan, deltaChan, errChan := p.provider.GenerateStream(ctx, opts)
+	if msgChan == nil {
+		return msgChan, deltaChan, errChan
+	}
+
+	outMsgChan := make(chan *core.Message, 10)
+
+	go func() {
+		defer close(outMsgChan)
+
+		for msg := range msgChan {
+			if msg != nil {
+				p.record(ctx, opts, msg)
+			}
+
+			outMsgChan <- msg
+		}
+	}()
+
+	return outMsgChan, deltaChan, errChan
+}
+
+func (p *Provider) record(ctx context.Context, opts *core.GenerateOptions, msg *core.Message) {
+	entry := &Entry{
+		Provider: p.name,
+		Usage:    FromResponse(opts, msg),
+	}
+
+	if p.model != nil {
+		entry.Model = p.model.ID
+	}
+
+	if info, ok := RunFromContext(ctx); ok {
+		entry.RunID = info.ID
+		entry.Agent = info.Agent
+	}
+
+	p.ledger.Record(entry)
+}
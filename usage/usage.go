@@ -0,0 +1,68 @@
+// Package usage accounts for the tokens and cost of provider calls per run,
+// per agent, per provider and per model.
+package usage
+
+import (
+	"strconv"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/examples/modelinfo"
+)
+
+// Message metadata provider properties through which providers may report
+// the actual token usage of a response.
+const (
+	PromptTokensProperty     string = "prompt_tokens"
+	CompletionTokensProperty string = "completion_tokens"
+)
+
+// Usage is the token usage of a single provider response.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+
+	// Estimated is true when the provider did not report usage and tokens
+	// were estimated from message contents.
+	Estimated bool
+}
+
+// TotalTokens returns the sum of prompt and completion tokens.
+func (u *Usage) TotalTokens() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// FromResponse returns the usage of a provider response to the given
+// generate options.
+func FromResponse(opts *core.GenerateOptions, msg *core.Message) *Usage {
+	if msg.Metadata != nil && msg.Metadata.ProviderProperties != nil {
+		prompt, promptErr := strconv.Atoi(msg.Metadata.ProviderProperties[PromptTokensProperty])
+		completion, completionErr := strconv.Atoi(msg.Metadata.ProviderProperties[CompletionTokensProperty])
+
+		if promptErr == nil && completionErr == nil {
+			return &Usage{
+				PromptTokens:     prompt,
+				CompletionTokens: completion,
+			}
+		}
+	}
+
+	prompt := 0
+	for _, m := range opts.Messages {
+		prompt += modelinfo.EstimateTokens(m.Content)
+	}
+
+	for _, t := range opts.Tools {
+		prompt += modelinfo.EstimateTokens(t.Description) + modelinfo.EstimateTokens(string(t.JSONSchema))
+	}
+
+	completion := modelinfo.EstimateTokens(msg.Content)
+	for _, tc := range msg.ToolCalls {
+		completion += modelinfo.EstimateTokens(tc.Name) + modelinfo.EstimateTokens(string(tc.Arguments))
+	}
+
+	return &Usage{
+		PromptTokens:     prompt,
+		CompletionTokens: completion,
+		Estimated:        true,
+	}
+}
@@ -0,0 +1,188 @@
+package usage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/examples/modelinfo"
+	openaimodels "github.com/agent-api/openai/models"
+)
+
+// reportingProvider answers every request with content and, when set, the
+// reported token usage.
+type reportingProvider struct {
+	content    string
+	prompt     string
+	completion string
+}
+
+func (p *reportingProvider) GetCapabilities(ctx context.Context) (*core.Capabilities, error) {
+	return &core.Capabilities{}, nil
+}
+
+func (p *reportingProvider) UseModel(ctx context.Context, model *core.Model) error {
+	return nil
+}
+
+func (p *reportingProvider) Generate(ctx context.Context, opts *core.GenerateOptions) (*core.Message, error) {
+	msg := &core.Message{Role: core.AssistantMessageRole, Content: p.content}
+
+	if p.prompt != "" {
+		msg.Metadata = &core.Metadata{
+			ProviderProperties: map[string]string{
+				PromptTokensProperty:     p.prompt,
+				CompletionTokensProperty: p.completion,
+			},
+		}
+	}
+
+	return msg, nil
+}
+
+func (p *reportingProvider) GenerateStream(ctx context.Context, opts *core.GenerateOptions) (<-chan *core.Message, <-chan string, <-chan error) {
+	msgChan := make(chan *core.Message, 1)
+	msg, _ := p.Generate(ctx, opts)
+	msgChan <- msg
+	close(msgChan)
+
+	return msgChan, nil, nil
+}
+
+func approx(a, b float64) bool {
+	return a-b < 1e-12 && b-a < 1e-12
+}
+
+func TestFromResponse(t *testing.T) {
+	opts := &core.GenerateOptions{
+		Messages: []*core.Message{{Role: core.UserMessageRole, Content: "What is 2 + 3?"}},
+	}
+
+	tests := []struct {
+		name string
+		msg  *core.Message
+		want *Usage
+	}{
+		{
+			name: "reported",
+			msg: &core.Message{
+				Content: "5",
+				Metadata: &core.Metadata{ProviderProperties: map[string]string{
+					PromptTokensProperty:     "12",
+					CompletionTokensProperty: "3",
+				}},
+			},
+			want: &Usage{PromptTokens: 12, CompletionTokens: 3},
+		},
+		{
+			name: "estimated",
+			msg:  &core.Message{Content: "2 + 3 is 5"},
+			want: &Usage{
+				PromptTokens:     modelinfo.EstimateTokens("What is 2 + 3?"),
+				CompletionTokens: modelinfo.EstimateTokens("2 + 3 is 5"),
+				Estimated:        true,
+			},
+		},
+		{
+			name: "partially reported",
+			msg: &core.Message{
+				Content: "2 + 3 is 5",
+				Metadata: &core.Metadata{ProviderProperties: map[string]string{
+					PromptTokensProperty: "12",
+				}},
+			},
+			want: &Usage{
+				PromptTokens:     modelinfo.EstimateTokens("What is 2 + 3?"),
+				CompletionTokens: modelinfo.EstimateTokens("2 + 3 is 5"),
+				Estimated:        true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromResponse(opts, tt.msg); *got != *tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLedger(t *testing.T) {
+	l := NewLedger(nil)
+
+	l.Record(&Entry{RunID: "run-1", Agent: "calc", Provider: "openai", Model: openaimodels.GPT4_O.ID, Usage: &Usage{PromptTokens: 1000, CompletionTokens: 500}})
+	l.Record(&Entry{RunID: "run-1", Agent: "calc", Provider: "openai", Model: openaimodels.GPT4_O_MINI.ID, Usage: &Usage{PromptTokens: 1000, CompletionTokens: 500, Estimated: true}})
+	l.Record(&Entry{RunID: "run-2", Agent: "chat", Provider: "local", Model: "unknown", Usage: &Usage{PromptTokens: 10, CompletionTokens: 5}})
+
+	entries := l.Entries()
+	if len(entries) != 3 || entries[0].Time.IsZero() {
+		t.Fatalf("got entries %+v", entries)
+	}
+
+	// $2.50 and $10 per million tokens
+	if !entries[0].Priced || !approx(entries[0].Cost, 0.0075) {
+		t.Errorf("got cost %g", entries[0].Cost)
+	}
+
+	if entries[2].Priced || entries[2].Cost != 0 {
+		t.Errorf("got unknown model entry %+v", entries[2])
+	}
+
+	run := l.RunSummary("run-1")
+	if run.Requests != 2 || run.TotalTokens() != 3000 || run.EstimatedRequests != 1 || !approx(run.Cost, 0.0075+0.00045) {
+		t.Errorf("got run summary %+v", run)
+	}
+
+	report := l.Report()
+	if report.Total.Requests != 3 || report.Total.UnpricedRequests != 1 {
+		t.Errorf("got total %+v", report.Total)
+	}
+
+	if report.ByAgent["calc"].Requests != 2 || report.ByProvider["local"].Requests != 1 || report.ByModel[openaimodels.GPT4_O.ID].Requests != 1 {
+		t.Errorf("got report %+v", report)
+	}
+
+	l.Reset()
+	if len(l.Entries()) != 0 || l.Report().Total.Requests != 0 {
+		t.Error("entries were kept after a reset")
+	}
+}
+
+func TestProvider(t *testing.T) {
+	l := NewLedger(nil)
+	p := NewProvider(&ProviderOpts{
+		Provider: &reportingProvider{content: "5", prompt: "1000", completion: "500"},
+		Name:     "openai",
+		Ledger:   l,
+	})
+
+	if err := p.UseModel(context.Background(), openaimodels.GPT4_O); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithRun(context.Background(), "run-1", "calc")
+
+	if _, err := p.Generate(ctx, &core.GenerateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	msgChan, _, _ := p.GenerateStream(context.Background(), &core.GenerateOptions{})
+	for range msgChan {
+	}
+
+	entries := l.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("recorded %d entries, want 2", len(entries))
+	}
+
+	first := entries[0]
+	if first.RunID != "run-1" || first.Agent != "calc" || first.Provider != "openai" || first.Model != openaimodels.GPT4_O.ID || !approx(first.Cost, 0.0075) {
+		t.Errorf("got entry %+v", first)
+	}
+
+	// a call outside a run is recorded without one
+	if entries[1].RunID != "" || entries[1].Usage.TotalTokens() != 1500 {
+		t.Errorf("got streamed entry %+v", entries[1])
+	}
+}
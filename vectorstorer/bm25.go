@@ -0,0 +1,137 @@
+package vectorstorer
+
+import (
+	"context"
+	"math"
+)
+
+// LexicalSearcher is implemented by stores that also support full text
+// search, ranking stored content by the query's terms rather than by vector
+// similarity.
+type LexicalSearcher interface {
+	LexicalSearch(ctx context.Context, params *SearchParams) ([]*SearchResult, error)
+}
+
+// BM25 parameters
+const (
+	bm25K1 float64 = 1.2
+	bm25B  float64 = 0.75
+)
+
+// termStats are the term frequencies and length of a tokenized document.
+type termStats struct {
+	freq   map[string]int
+	length int
+}
+
+func newTermStats(content string) *termStats {
+	tokens := Tokenize(content)
+
+	stats := &termStats{
+		freq:   make(map[string]int),
+		length: len(tokens),
+	}
+
+	for _, t := range tokens {
+		stats.freq[t]++
+	}
+
+	return stats
+}
+
+// LexicalSearch ranks the stored embeddings of the search's namespace that
+// match its filter and predicates by the BM25 score of their content against
+// the query. Document frequencies are computed over the matching embeddings
+// only, so one namespace's content never affects another's ranking.
+func (s *MemoryStore) LexicalSearch(ctx context.Context, params *SearchParams) ([]*SearchResult, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	if err := validateSearch(params); err != nil {
+		return nil, err
+	}
+
+	queryTerms := map[string]bool{}
+	for _, t := range Tokenize(params.Query) {
+		queryTerms[t] = true
+	}
+
+	s.mu.RLock()
+
+	candidates := []*Embedding{}
+	docFreq := map[string]int{}
+	totalLength := 0
+
+	for _, e := range s.embeddings {
+		if !matchesSearch(e, params) {
+			continue
+		}
+
+		candidates = append(candidates, e)
+		stats := s.termStatsLocked(e)
+		totalLength += stats.length
+
+		for t := range queryTerms {
+			if stats.freq[t] > 0 {
+				docFreq[t]++
+			}
+		}
+	}
+
+	results := []*SearchResult{}
+	if len(candidates) > 0 {
+		n := float64(len(candidates))
+		avgLength := math.Max(float64(totalLength)/n, 1)
+
+		for _, e := range candidates {
+			stats := s.termStatsLocked(e)
+			score := 0.0
+
+			for t := range queryTerms {
+				tf := float64(stats.freq[t])
+				if tf == 0 {
+					continue
+				}
+
+				df := float64(docFreq[t])
+				idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+				score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*float64(stats.length)/avgLength))
+			}
+
+			if score > 0 {
+				results = append(results, &SearchResult{Embedding: e, Score: score})
+			}
+		}
+	}
+
+	s.mu.RUnlock()
+
+	sortResults(results)
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	for _, r := range results {
+		r.Embedding = copyEmbedding(r.Embedding)
+	}
+
+	return results, nil
+}
+
+// termStatsLocked returns the cached term stats of an embedding. The read
+// lock must be held; the cache has its own lock since it fills up on reads.
+func (s *MemoryStore) termStatsLocked(e *Embedding) *termStats {
+	s.termsMu.Lock()
+	defer s.termsMu.Unlock()
+
+	stats, ok := s.terms[e.ID]
+	if !ok {
+		stats = newTermStats(e.Content)
+		s.terms[e.ID] = stats
+	}
+
+	return stats
+}
@@ -0,0 +1,73 @@
+package vectorstorer
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestLexicalSearch(t *testing.T) {
+	docs := []*Embedding{
+		{ID: "bolt", Content: "Tighten the M6 bolt to 10 Nm", Metadata: map[string]any{"part": "frame"}},
+		{ID: "bolts", Content: "Bolt bolt bolt: every bolt of the wheel", Metadata: map[string]any{"part": "wheel"}},
+		{ID: "chain", Content: "Lubricate the chain every 300 km", Metadata: map[string]any{"part": "drivetrain"}},
+		{ID: "tenant", Namespace: "tenant", Content: "The M6 bolt of another tenant"},
+	}
+
+	store := newTestStore(t, &MemoryStoreOpts{}, docs)
+
+	tests := []struct {
+		name   string
+		params *SearchParams
+		want   []string
+	}{
+		{
+			name:   "exact identifier",
+			params: &SearchParams{Query: "M6"},
+			want:   []string{"bolt"},
+		},
+		{
+			name:   "term frequency",
+			params: &SearchParams{Query: "bolt"},
+			want:   []string{"bolts", "bolt"},
+		},
+		{
+			name:   "rare terms weigh more",
+			params: &SearchParams{Query: "bolt chain"},
+			want:   []string{"chain", "bolts", "bolt"},
+		},
+		{
+			name:   "no matching terms",
+			params: &SearchParams{Query: "saddle"},
+			want:   []string{},
+		},
+		{
+			name:   "limit",
+			params: &SearchParams{Query: "bolt", Limit: 1},
+			want:   []string{"bolts"},
+		},
+		{
+			name:   "namespace",
+			params: &SearchParams{Query: "M6 bolt", Namespace: "tenant"},
+			want:   []string{"tenant"},
+		},
+		{
+			name:   "filter",
+			params: &SearchParams{Query: "bolt", Filter: map[string]any{"part": "frame"}},
+			want:   []string{"bolt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := store.LexicalSearch(context.Background(), tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if ids := resultIDs(results); !slices.Equal(ids, tt.want) {
+				t.Errorf("got %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
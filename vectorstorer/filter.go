@@ -0,0 +1,351 @@
+package vectorstorer
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Operator is the comparison of a metadata Predicate.
+type Operator string
+
+const (
+	OpEq  Operator = "eq"
+	OpNe  Operator = "ne"
+	OpGt  Operator = "gt"
+	OpGte Operator = "gte"
+	OpLt  Operator = "lt"
+	OpLte Operator = "lte"
+
+	// OpIn matches values equal to any of the predicate's values
+	OpIn Operator = "in"
+
+	// OpContains matches array values, i.e., tags, containing the
+	// predicate's value
+	OpContains Operator = "contains"
+
+	// OpExists matches metadata that has the key, whatever its value
+	OpExists Operator = "exists"
+)
+
+// Predicate is a condition on a metadata value. Numbers compare by value,
+// timestamps (time.Time or RFC 3339 strings) by time and other strings
+// lexicographically.
+type Predicate struct {
+	Key   string
+	Op    Operator
+	Value any
+}
+
+// Eq matches metadata whose key equals value.
+func Eq(key string, value any) *Predicate {
+	return &Predicate{Key: key, Op: OpEq, Value: value}
+}
+
+// Ne matches metadata whose key is missing or does not equal value.
+func Ne(key string, value any) *Predicate {
+	return &Predicate{Key: key, Op: OpNe, Value: value}
+}
+
+// Gt matches metadata whose key is greater than value.
+func Gt(key string, value any) *Predicate {
+	return &Predicate{Key: key, Op: OpGt, Value: value}
+}
+
+// Gte matches metadata whose key is greater than or equal to value.
+func Gte(key string, value any) *Predicate {
+	return &Predicate{Key: key, Op: OpGte, Value: value}
+}
+
+// Lt matches metadata whose key is less than value.
+func Lt(key string, value any) *Predicate {
+	return &Predicate{Key: key, Op: OpLt, Value: value}
+}
+
+// Lte matches metadata whose key is less than or equal to value.
+func Lte(key string, value any) *Predicate {
+	return &Predicate{Key: key, Op: OpLte, Value: value}
+}
+
+// In matches metadata whose key equals any of values.
+func In(key string, values ...any) *Predicate {
+	return &Predicate{Key: key, Op: OpIn, Value: values}
+}
+
+// Contains matches metadata whose key is an array containing value.
+func Contains(key string, value any) *Predicate {
+	return &Predicate{Key: key, Op: OpContains, Value: value}
+}
+
+// Exists matches metadata that has the key.
+func Exists(key string) *Predicate {
+	return &Predicate{Key: key, Op: OpExists}
+}
+
+// validate checks that the predicate's operator is known and its value fits.
+func (p *Predicate) validate() error {
+	if p.Key == "" {
+		return fmt.Errorf("predicate %s has no key", p.Op)
+	}
+
+	switch p.Op {
+	case OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpContains, OpExists:
+		return nil
+	case OpIn:
+		if _, ok := p.Value.([]any); !ok {
+			return fmt.Errorf("predicate %s on %s requires a []any value", p.Op, p.Key)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown predicate operator %q", p.Op)
+	}
+}
+
+// matches reports whether metadata satisfies the predicate.
+func (p *Predicate) matches(metadata map[string]any) bool {
+	got, ok := metadata[p.Key]
+
+	switch p.Op {
+	case OpExists:
+		return ok
+	case OpNe:
+		return !ok || !valuesEqual(got, p.Value)
+	}
+
+	if !ok {
+		return false
+	}
+
+	switch p.Op {
+	case OpEq:
+		return valuesEqual(got, p.Value)
+
+	case OpIn:
+		for _, v := range p.Value.([]any) {
+			if valuesEqual(got, v) {
+				return true
+			}
+		}
+		return false
+
+	case OpContains:
+		return containsValue(got, []any{p.Value})
+	}
+
+	cmp, ok := compareValues(got, p.Value)
+	if !ok {
+		return false
+	}
+
+	switch p.Op {
+	case OpGt:
+		return cmp > 0
+	case OpGte:
+		return cmp >= 0
+	case OpLt:
+		return cmp < 0
+	case OpLte:
+		return cmp <= 0
+	default:
+		return false
+	}
+}
+
+// matchesSearch reports whether an embedding is in the namespace and matches
+// the filter and every predicate of a search.
+func matchesSearch(e *Embedding, params *SearchParams) bool {
+	if e.Namespace != params.Namespace {
+		return false
+	}
+
+	if !matchesFilter(e.Metadata, params.Filter) {
+		return false
+	}
+
+	for _, p := range params.Where {
+		if !p.matches(e.Metadata) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validateSearch checks the predicates of a search.
+func validateSearch(params *SearchParams) error {
+	for _, p := range params.Where {
+		if err := p.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matchesFilter reports whether metadata contains filter, with the semantics
+// of Postgres' jsonb containment: see containsValue.
+func matchesFilter(metadata, filter map[string]any) bool {
+	return containsValue(metadata, filter)
+}
+
+// containsValue reports whether got contains want like jsonb's @> operator:
+// objects contain the keys of want with contained values, arrays contain
+// every element of want in any order, and other values are equal. Numbers
+// are compared by value so that filters match metadata loaded back from
+// JSON, where every number is a float64.
+func containsValue(got, want any) bool {
+	switch w := plainValue(want).(type) {
+	case map[string]any:
+		g, ok := plainValue(got).(map[string]any)
+		if !ok {
+			return false
+		}
+
+		for k, wv := range w {
+			gv, ok := g[k]
+			if !ok || !containsValue(gv, wv) {
+				return false
+			}
+		}
+		return true
+
+	case []any:
+		g, ok := plainValue(got).([]any)
+		if !ok {
+			return false
+		}
+
+		for _, wv := range w {
+			if !slices.ContainsFunc(g, func(gv any) bool { return containsValue(gv, wv) }) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return valuesEqual(got, want)
+}
+
+// plainValue converts composite values, i.e., []string or map[string]int, to
+// the []any or map[string]any they decode to from JSON. Other values are
+// returned as is.
+func plainValue(v any) any {
+	switch v.(type) {
+	case map[string]any, []any:
+		return v
+	}
+
+	kind := reflect.ValueOf(v).Kind()
+	if kind != reflect.Slice && kind != reflect.Array && kind != reflect.Map {
+		return v
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+
+	var plain any
+	if err := json.Unmarshal(data, &plain); err != nil {
+		return v
+	}
+
+	return plain
+}
+
+func valuesEqual(a, b any) bool {
+	if af, ok := toFloat(a); ok {
+		bf, ok := toFloat(b)
+		return ok && af == bf
+	}
+
+	if at, ok := toTime(a); ok {
+		if bt, ok := toTime(b); ok {
+			return at.Equal(bt)
+		}
+	}
+
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+
+	// compare composite values, i.e., []string and []any, by their JSON
+	aj, aErr := json.Marshal(a)
+	bj, bErr := json.Marshal(b)
+
+	return aErr == nil && bErr == nil && string(aj) == string(bj)
+}
+
+// compareValues orders two numbers, timestamps or strings.
+func compareValues(a, b any) (int, bool) {
+	if af, ok := toFloat(a); ok {
+		bf, ok := toFloat(b)
+		if !ok {
+			return 0, false
+		}
+
+		switch {
+		case af < bf:
+			return -1, true
+		case af > bf:
+			return 1, true
+		default:
+			return 0, true
+		}
+	}
+
+	if at, ok := toTime(a); ok {
+		if bt, ok := toTime(b); ok {
+			return at.Compare(bt), true
+		}
+	}
+
+	as, aOK := a.(string)
+	bs, bOK := b.(string)
+	if aOK && bOK {
+		return strings.Compare(as, bs), true
+	}
+
+	return 0, false
+}
+
+func toTime(v any) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, true
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, t)
+		return parsed, err == nil
+	default:
+		return time.Time{}, false
+	}
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,116 @@
+package vectorstorer
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// DefaultHashDimensions matches the dimensions of the pgvector examples.
+const DefaultHashDimensions int = 768
+
+// HashEmbedderOpts configures a new HashEmbedder.
+type HashEmbedderOpts struct {
+	// Dimensions defaults to DefaultHashDimensions
+	Dimensions int
+
+	// NGram adds the character n-grams of every word as features, which
+	// makes similar spellings, i.e., "bolt" and "bolts", similar. Zero
+	// disables character n-grams; 3 is a good choice.
+	NGram int
+}
+
+// HashEmbedder is a deterministic, local Embedder that hashes words (and
+// optionally character n-grams) into a fixed number of dimensions. Vectors
+// are L2 normalized, so Cosine and DotProduct similarity agree.
+//
+// It needs no model or network access, so retrieval logic can be tested end
+// to end offline and reproducibly. Similarity is purely lexical.
+type HashEmbedder struct {
+	dimensions int
+	ngram      int
+}
+
+// NewHashEmbedder creates a new HashEmbedder.
+func NewHashEmbedder(opts *HashEmbedderOpts) *HashEmbedder {
+	if opts.Dimensions <= 0 {
+		opts.Dimensions = DefaultHashDimensions
+	}
+
+	return &HashEmbedder{
+		dimensions: opts.Dimensions,
+		ngram:      opts.NGram,
+	}
+}
+
+// Dimensions returns the length of the embedder's vectors.
+func (e *HashEmbedder) Dimensions(ctx context.Context) (int, error) {
+	return e.dimensions, nil
+}
+
+// Embed embeds each text.
+func (e *HashEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, t := range texts {
+		vectors[i] = e.embed(t)
+	}
+
+	return vectors, nil
+}
+
+func (e *HashEmbedder) embed(text string) []float32 {
+	counts := map[string]float64{}
+	for _, word := range Tokenize(text) {
+		counts["w:"+word]++
+
+		if e.ngram > 0 {
+			padded := []rune("^" + word + "$")
+			for i := 0; i+e.ngram <= len(padded); i++ {
+				counts["c:"+string(padded[i:i+e.ngram])] += 0.5
+			}
+		}
+	}
+
+	vector := make([]float64, e.dimensions)
+	for feature, count := range counts {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+
+		// the top bit picks the sign so that collisions tend to cancel out
+		// rather than add up
+		sign := 1.0
+		if sum>>63 == 1 {
+			sign = -1.0
+		}
+
+		// sublinear term frequency
+		vector[sum%uint64(e.dimensions)] += sign * (1 + math.Log(count))
+	}
+
+	norm := 0.0
+	for _, v := range vector {
+		norm += v * v
+	}
+	norm = math.Sqrt(norm)
+
+	out := make([]float32, e.dimensions)
+	if norm == 0 {
+		return out
+	}
+
+	for i, v := range vector {
+		out[i] = float32(v / norm)
+	}
+
+	return out
+}
+
+// Tokenize lower cases text and splits it into words of letters and digits.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
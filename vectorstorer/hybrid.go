@@ -0,0 +1,161 @@
+package vectorstorer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// HybridStoreOpts configures a new HybridStore.
+type HybridStoreOpts struct {
+	// Store must also implement LexicalSearcher, as MemoryStore and
+	// PgVectorStore do
+	Store Store
+
+	// K dampens the influence of top ranks in reciprocal rank fusion.
+	// Defaults to 60.
+	K int
+
+	// Candidates is the number of results fetched from each of the vector
+	// and lexical searches before fusing them. Defaults to 4 times the
+	// search's limit.
+	Candidates int
+
+	// VectorWeight and LexicalWeight scale each search's contribution to the
+	// fused score. Both default to 1.
+	VectorWeight  float64
+	LexicalWeight float64
+}
+
+// HybridStore is a Store whose Search combines vector similarity with full
+// text search through reciprocal rank fusion, so that exact identifiers that
+// embeddings miss are still retrieved. Scores of its results are fused
+// scores: the weighted sum of 1 / (K + rank) over both searches.
+type HybridStore struct {
+	Store
+
+	lexical LexicalSearcher
+
+	k             int
+	candidates    int
+	vectorWeight  float64
+	lexicalWeight float64
+}
+
+// NewHybridStore creates a new HybridStore.
+func NewHybridStore(opts *HybridStoreOpts) (*HybridStore, error) {
+	if opts.Store == nil {
+		return nil, errors.New("hybrid store requires a store")
+	}
+
+	lexical, ok := opts.Store.(LexicalSearcher)
+	if !ok {
+		return nil, fmt.Errorf("store %T does not support lexical search", opts.Store)
+	}
+
+	if opts.K <= 0 {
+		opts.K = 60
+	}
+
+	if opts.VectorWeight == 0 {
+		opts.VectorWeight = 1
+	}
+
+	if opts.LexicalWeight == 0 {
+		opts.LexicalWeight = 1
+	}
+
+	return &HybridStore{
+		Store:         opts.Store,
+		lexical:       lexical,
+		k:             opts.K,
+		candidates:    opts.Candidates,
+		vectorWeight:  opts.VectorWeight,
+		lexicalWeight: opts.LexicalWeight,
+	}, nil
+}
+
+// Search runs the vector and lexical searches concurrently and fuses their
+// rankings.
+func (h *HybridStore) Search(ctx context.Context, params *SearchParams) ([]*SearchResult, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	candidates := h.candidates
+	if candidates <= 0 {
+		candidates = 4 * limit
+	}
+
+	candidateParams := *params
+	candidateParams.Limit = candidates
+
+	var vectorResults, lexicalResults []*SearchResult
+	var vectorErr, lexicalErr error
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		vectorResults, vectorErr = h.Store.Search(ctx, &candidateParams)
+	}()
+
+	go func() {
+		defer wg.Done()
+		lexicalResults, lexicalErr = h.lexical.LexicalSearch(ctx, &candidateParams)
+	}()
+
+	wg.Wait()
+
+	if err := errors.Join(vectorErr, lexicalErr); err != nil {
+		return nil, err
+	}
+
+	results := FuseRankings(h.k, []float64{h.vectorWeight, h.lexicalWeight}, vectorResults, lexicalResults)
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// FuseRankings merges rankings with reciprocal rank fusion: every result
+// scores the sum over rankings of weight / (k + rank), with ranks starting at
+// 1. Weights apply to rankings in order; missing weights are 1. Results are
+// identified by embedding ID.
+func FuseRankings(k int, weights []float64, rankings ...[]*SearchResult) []*SearchResult {
+	fused := map[string]*SearchResult{}
+	order := []string{}
+
+	for i, ranking := range rankings {
+		weight := 1.0
+		if i < len(weights) {
+			weight = weights[i]
+		}
+
+		for rank, r := range ranking {
+			id := r.Embedding.ID
+
+			f, ok := fused[id]
+			if !ok {
+				f = &SearchResult{Embedding: r.Embedding}
+				fused[id] = f
+				order = append(order, id)
+			}
+
+			f.Score += weight / float64(k+rank+1)
+		}
+	}
+
+	results := make([]*SearchResult, len(order))
+	for i, id := range order {
+		results[i] = fused[id]
+	}
+
+	sortResults(results)
+
+	return results
+}
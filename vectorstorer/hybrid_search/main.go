@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/agent-api/examples/vectorstorer"
+)
+
+func main() {
+	ctx := context.Background()
+
+	// Create a zap logger
+	config := zap.NewDevelopmentConfig()
+	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	zLogger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a logr.Logger using zapr adapter
+	logger := zapr.NewLogger(zLogger)
+
+	embedder := vectorstorer.NewOllamaEmbedder(&vectorstorer.OllamaEmbedderOpts{
+		Model:  vectorstorer.OllamaNomicEmbedText,
+		Logger: &logger,
+	})
+
+	memory, err := vectorstorer.NewMemoryStore(&vectorstorer.MemoryStoreOpts{
+		Embedder: embedder,
+		Logger:   &logger,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = memory.Add(ctx, []string{
+		"Fire Bolt - Cantrip - 120ft - You hurl a mote of fire at a creature or an object within range. On a hit, the target takes 1d10 Fire damage.",
+		"Produce Flame - Cantrip - Self - A flickering flame appears in your hand. You can hurl the flame at a creature within 60 feet, dealing 1d8 Fire damage.",
+		"Scorching Ray - Level 2 - 120ft - You create three rays of fire and hurl them at targets within range, each dealing 2d6 Fire damage.",
+		"Ray of Frost - Cantrip - 60ft - A frigid beam of blue-white light streaks toward a creature within range. On a hit, it takes 1d8 Cold damage.",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// Searches through the hybrid store fuse embedding similarity with BM25,
+	// so the exact spell name ranks first rather than any fire spell
+	hybrid, err := vectorstorer.NewHybridStore(&vectorstorer.HybridStoreOpts{
+		Store: memory,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	query := "What is the range of Fire Bolt?"
+
+	vectorOnly, err := memory.Search(ctx, &vectorstorer.SearchParams{Query: query, Limit: 3})
+	if err != nil {
+		panic(err)
+	}
+
+	fused, err := hybrid.Search(ctx, &vectorstorer.SearchParams{Query: query, Limit: 3})
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("vector only:")
+	for _, r := range vectorOnly {
+		fmt.Printf("  %.4f %s\n", r.Score, r.Embedding.Content[:40])
+	}
+
+	fmt.Println("hybrid:")
+	for _, r := range fused {
+		fmt.Printf("  %.4f %s\n", r.Score, r.Embedding.Content[:40])
+	}
+}
@@ -0,0 +1,79 @@
+package vectorstorer
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func ranking(ids ...string) []*SearchResult {
+	results := make([]*SearchResult, len(ids))
+	for i, id := range ids {
+		results[i] = &SearchResult{Embedding: &Embedding{ID: id}, Score: float64(len(ids) - i)}
+	}
+
+	return results
+}
+
+func TestFuseRankings(t *testing.T) {
+	tests := []struct {
+		name     string
+		k        int
+		weights  []float64
+		rankings [][]*SearchResult
+		want     []string
+		scores   []float64
+	}{
+		{
+			name:     "single ranking keeps its order",
+			k:        60,
+			rankings: [][]*SearchResult{ranking("a", "b", "c")},
+			want:     []string{"a", "b", "c"},
+			scores:   []float64{1.0 / 61, 1.0 / 62, 1.0 / 63},
+		},
+		{
+			name:     "results in both rankings win",
+			k:        60,
+			rankings: [][]*SearchResult{ranking("a", "b"), ranking("c", "b")},
+			want:     []string{"b", "a", "c"},
+			scores:   []float64{2.0 / 62, 1.0 / 61, 1.0 / 61},
+		},
+		{
+			name:     "weights",
+			k:        1,
+			weights:  []float64{1, 3},
+			rankings: [][]*SearchResult{ranking("a", "b"), ranking("b", "a")},
+			want:     []string{"b", "a"},
+			scores:   []float64{1.0/3 + 3.0/2, 1.0/2 + 3.0/3},
+		},
+		{
+			name:     "missing weights are 1",
+			k:        1,
+			weights:  []float64{0},
+			rankings: [][]*SearchResult{ranking("a"), ranking("b")},
+			want:     []string{"b", "a"},
+			scores:   []float64{1.0 / 2, 0},
+		},
+		{
+			name: "no rankings",
+			k:    60,
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fused := FuseRankings(tt.k, tt.weights, tt.rankings...)
+
+			if ids := resultIDs(fused); !slices.Equal(ids, tt.want) {
+				t.Fatalf("got %v, want %v", ids, tt.want)
+			}
+
+			for i, score := range tt.scores {
+				if math.Abs(fused[i].Score-score) > 1e-9 {
+					t.Errorf("%s scored %f, want %f", fused[i].Embedding.ID, fused[i].Score, score)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package vectorstorer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+)
+
+// defaultListLimit is the page size of List when none is given.
+const defaultListLimit int = 100
+
+// Selector selects stored embeddings by namespace, metadata filter and
+// predicates, as a search does.
+type Selector struct {
+	Namespace string
+
+	Filter map[string]any
+
+	Where []*Predicate
+}
+
+func (s *Selector) searchParams() *SearchParams {
+	return &SearchParams{
+		Namespace: s.Namespace,
+		Filter:    s.Filter,
+		Where:     s.Where,
+	}
+}
+
+// ListParams selects a page of stored embeddings.
+type ListParams struct {
+	Selector
+
+	// Limit is the page size. Defaults to 100.
+	Limit int
+
+	// Cursor is the NextCursor of the previous page. It is empty for the
+	// first page.
+	Cursor string
+}
+
+// Page is a page of stored embeddings, in ID order.
+type Page struct {
+	Embeddings []*Embedding
+
+	// NextCursor continues the listing after this page. It is empty on the
+	// last page.
+	NextCursor string
+}
+
+// Stats describes the contents of a store.
+type Stats struct {
+	// Count is the number of stored embeddings
+	Count int
+
+	// Namespaces counts the stored embeddings by namespace
+	Namespaces map[string]int
+
+	// Dimensions is the length of the stored vectors. It is 0 when the store
+	// is empty, unless the store has a fixed dimension, and -1 when vectors
+	// of several lengths are stored.
+	Dimensions int
+
+	// Sources is the number of distinct documents, by MetadataSource
+	Sources int
+}
+
+// Manager is a Store whose contents can be maintained over time.
+type Manager interface {
+	Store
+
+	// Delete deletes the embeddings with the given IDs and returns the
+	// number deleted. IDs that are not stored are skipped.
+	Delete(ctx context.Context, ids []string) (int, error)
+
+	// DeleteWhere deletes every embedding the selector selects and returns
+	// the number deleted. A selector with no filter or predicates deletes its
+	// whole namespace.
+	DeleteWhere(ctx context.Context, selector *Selector) (int, error)
+
+	// List returns a page of the selected embeddings, in ID order.
+	List(ctx context.Context, params *ListParams) (*Page, error)
+
+	Stats(ctx context.Context) (*Stats, error)
+}
+
+// UpsertByContentHash upserts embeddings that have an ID and content but no
+// vector. Only embeddings whose content hash differs from the stored
+// embedding with the same ID are embedded: the others reuse their stored
+// vector, so their namespace and metadata are still updated. The vector and
+// MetadataContentHash of every embedding are set in place. It returns the
+// number of embeddings embedded.
+func UpsertByContentHash(ctx context.Context, store Store, embedder Embedder, embeddings []*Embedding) (int, error) {
+	if len(embeddings) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]string, len(embeddings))
+	for i, e := range embeddings {
+		if e.ID == "" {
+			return 0, errors.New("upserted embeddings must have an ID")
+		}
+
+		ids[i] = e.ID
+	}
+
+	stored, err := store.Get(ctx, ids)
+	if err != nil {
+		return 0, fmt.Errorf("error getting stored embeddings: %w", err)
+	}
+
+	storedByID := make(map[string]*Embedding, len(stored))
+	for _, e := range stored {
+		storedByID[e.ID] = e
+	}
+
+	changed := []*Embedding{}
+	for _, e := range embeddings {
+		hash := ContentHash(e.Content)
+
+		if e.Metadata == nil {
+			e.Metadata = map[string]any{}
+		}
+		e.Metadata[MetadataContentHash] = hash
+
+		if s, ok := storedByID[e.ID]; ok && s.Metadata[MetadataContentHash] == hash && len(s.Vector) > 0 {
+			e.Vector = s.Vector
+			continue
+		}
+
+		changed = append(changed, e)
+	}
+
+	if len(changed) > 0 {
+		texts := make([]string, len(changed))
+		for i, e := range changed {
+			texts[i] = e.Content
+		}
+
+		vectors, err := embedder.Embed(ctx, texts)
+		if err != nil {
+			return 0, fmt.Errorf("error embedding: %w", err)
+		}
+
+		if len(vectors) != len(texts) {
+			return 0, fmt.Errorf("embedder returned %d vectors for %d texts", len(vectors), len(texts))
+		}
+
+		for i, e := range changed {
+			e.Vector = vectors[i]
+		}
+	}
+
+	if err := store.Upsert(ctx, embeddings); err != nil {
+		return 0, err
+	}
+
+	return len(changed), nil
+}
+
+// ReembedOpts configures a re-embed migration.
+type ReembedOpts struct {
+	// From is the store whose embeddings are migrated
+	From Manager
+
+	// To receives the re-embedded embeddings under the same IDs. It may be
+	// From itself when its vectors are not of a fixed dimension, as in a
+	// MemoryStore. A PgVectorStore's dimensions are fixed by its table, so
+	// migrating to new dimensions needs a new table.
+	To Store
+
+	// Embedder computes the new vectors, i.e., To's embedder
+	Embedder Embedder
+
+	// BatchSize is the number of embeddings embedded per Embedder call.
+	// Defaults to 64.
+	BatchSize int
+
+	Logger *logr.Logger
+}
+
+// Reembed embeds the content of every embedding in every namespace of
+// opts.From with opts.Embedder and upserts it into opts.To, keeping its ID,
+// namespace and metadata. It returns the number of embeddings migrated.
+// Re-running a migration re-embeds everything.
+func Reembed(ctx context.Context, opts *ReembedOpts) (int, error) {
+	if opts.From == nil || opts.To == nil || opts.Embedder == nil {
+		return 0, errors.New("re-embed requires a source store, a target store and an embedder")
+	}
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 64
+	}
+
+	stats, err := opts.From.Stats(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error getting store stats: %w", err)
+	}
+
+	namespaces := make([]string, 0, len(stats.Namespaces))
+	for namespace := range stats.Namespaces {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	migrated := 0
+	for _, namespace := range namespaces {
+		params := &ListParams{
+			Selector: Selector{Namespace: namespace},
+			Limit:    opts.BatchSize,
+		}
+
+		for {
+			page, err := opts.From.List(ctx, params)
+			if err != nil {
+				return migrated, fmt.Errorf("error listing embeddings: %w", err)
+			}
+
+			if len(page.Embeddings) > 0 {
+				if err := reembedPage(ctx, opts, page.Embeddings); err != nil {
+					return migrated, err
+				}
+
+				migrated += len(page.Embeddings)
+
+				if opts.Logger != nil {
+					opts.Logger.Info("re-embedded embeddings", "namespace", namespace, "migrated", migrated, "total", stats.Count)
+				}
+			}
+
+			if page.NextCursor == "" {
+				break
+			}
+			params.Cursor = page.NextCursor
+		}
+	}
+
+	return migrated, nil
+}
+
+func reembedPage(ctx context.Context, opts *ReembedOpts, embeddings []*Embedding) error {
+	texts := make([]string, len(embeddings))
+	for i, e := range embeddings {
+		texts[i] = e.Content
+	}
+
+	vectors, err := opts.Embedder.Embed(ctx, texts)
+	if err != nil {
+		return fmt.Errorf("error embedding: %w", err)
+	}
+
+	if len(vectors) != len(texts) {
+		return fmt.Errorf("embedder returned %d vectors for %d texts", len(vectors), len(texts))
+	}
+
+	for i, e := range embeddings {
+		e.Vector = vectors[i]
+	}
+
+	if err := opts.To.Upsert(ctx, embeddings); err != nil {
+		return fmt.Errorf("error upserting re-embedded embeddings: %w", err)
+	}
+
+	return nil
+}
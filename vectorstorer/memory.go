@@ -0,0 +1,413 @@
+package vectorstorer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+// MemoryStoreOpts configures a new MemoryStore.
+type MemoryStoreOpts struct {
+	Embedder Embedder
+
+	// Similarity defaults to Cosine
+	Similarity Similarity
+
+	// Path, when set, persists the store to a JSON file. The file is loaded
+	// when the store is created and rewritten after every change.
+	Path string
+
+	Logger *logr.Logger
+}
+
+// MemoryStore is a Store that keeps embeddings in memory and searches them
+// exhaustively, so RAG examples and tests run without a database.
+type MemoryStore struct {
+	embedder   Embedder
+	similarity Similarity
+	path       string
+
+	mu         sync.RWMutex
+	embeddings map[string]*Embedding
+
+	// term stats of embeddings' content for lexical search, by ID
+	termsMu sync.Mutex
+	terms   map[string]*termStats
+
+	logger *logr.Logger
+}
+
+// NewMemoryStore creates a new MemoryStore, loading it from opts.Path if the
+// file exists.
+func NewMemoryStore(opts *MemoryStoreOpts) (*MemoryStore, error) {
+	if opts.Embedder == nil {
+		return nil, errors.New("memory store requires an embedder")
+	}
+
+	s := &MemoryStore{
+		embedder:   opts.Embedder,
+		similarity: opts.Similarity,
+		path:       opts.Path,
+		embeddings: make(map[string]*Embedding),
+		terms:      make(map[string]*termStats),
+		logger:     opts.Logger,
+	}
+
+	if s.path != "" {
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Len returns the number of stored embeddings.
+func (s *MemoryStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.embeddings)
+}
+
+// Add embeds and stores the contents. Contents already stored are not
+// embedded again.
+func (s *MemoryStore) Add(ctx context.Context, contents []string) ([]*Embedding, error) {
+	embeddings := make([]*Embedding, len(contents))
+	for i, content := range contents {
+		embeddings[i] = &Embedding{
+			ID:      ContentHash(content),
+			Content: content,
+		}
+	}
+
+	if _, err := UpsertByContentHash(ctx, s, s.embedder, embeddings); err != nil {
+		return nil, err
+	}
+
+	return embeddings, nil
+}
+
+// Upsert inserts or replaces the embeddings.
+func (s *MemoryStore) Upsert(ctx context.Context, embeddings []*Embedding) error {
+	for _, e := range embeddings {
+		if e.ID == "" || len(e.Vector) == 0 {
+			return errors.New("upserted embeddings must have an ID and a vector")
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.termsMu.Lock()
+	for _, e := range embeddings {
+		s.embeddings[e.ID] = copyEmbedding(e)
+		delete(s.terms, e.ID)
+	}
+	s.termsMu.Unlock()
+
+	return s.saveLocked()
+}
+
+// Get returns the stored embeddings with the given IDs.
+func (s *MemoryStore) Get(ctx context.Context, ids []string) ([]*Embedding, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	embeddings := []*Embedding{}
+	for _, id := range ids {
+		if e, ok := s.embeddings[id]; ok {
+			embeddings = append(embeddings, copyEmbedding(e))
+		}
+	}
+
+	return embeddings, nil
+}
+
+// Search returns the stored embeddings of the search's namespace most similar
+// to the query, among those matching its filter and predicates.
+func (s *MemoryStore) Search(ctx context.Context, params *SearchParams) ([]*SearchResult, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	if err := validateSearch(params); err != nil {
+		return nil, err
+	}
+
+	vectors, err := s.embedder.Embed(ctx, []string{params.Query})
+	if err != nil {
+		return nil, err
+	}
+	query := vectors[0]
+
+	s.mu.RLock()
+	results := []*SearchResult{}
+	for _, e := range s.embeddings {
+		if !matchesSearch(e, params) {
+			continue
+		}
+
+		if len(e.Vector) != len(query) {
+			s.mu.RUnlock()
+			return nil, fmt.Errorf("query has %d dimensions but %s has %d", len(query), e.ID, len(e.Vector))
+		}
+
+		results = append(results, &SearchResult{
+			Embedding: e,
+			Score:     s.similarity.Score(query, e.Vector),
+		})
+	}
+	s.mu.RUnlock()
+
+	sortResults(results)
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	for _, r := range results {
+		r.Embedding = copyEmbedding(r.Embedding)
+	}
+
+	return results, nil
+}
+
+// Delete deletes the embeddings with the given IDs.
+func (s *MemoryStore) Delete(ctx context.Context, ids []string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := []string{}
+	for _, id := range ids {
+		if _, ok := s.embeddings[id]; ok {
+			deleted = append(deleted, id)
+		}
+	}
+
+	return s.deleteLocked(deleted)
+}
+
+// DeleteWhere deletes the embeddings the selector selects.
+func (s *MemoryStore) DeleteWhere(ctx context.Context, selector *Selector) (int, error) {
+	params := selector.searchParams()
+	if err := validateSearch(params); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := []string{}
+	for id, e := range s.embeddings {
+		if matchesSearch(e, params) {
+			deleted = append(deleted, id)
+		}
+	}
+
+	return s.deleteLocked(deleted)
+}
+
+func (s *MemoryStore) deleteLocked(ids []string) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	s.termsMu.Lock()
+	for _, id := range ids {
+		delete(s.embeddings, id)
+		delete(s.terms, id)
+	}
+	s.termsMu.Unlock()
+
+	return len(ids), s.saveLocked()
+}
+
+// List returns a page of the selected embeddings, in ID order.
+func (s *MemoryStore) List(ctx context.Context, params *ListParams) (*Page, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	search := params.searchParams()
+	if err := validateSearch(search); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	selected := []*Embedding{}
+	for _, e := range s.embeddings {
+		if e.ID > params.Cursor && matchesSearch(e, search) {
+			selected = append(selected, e)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].ID < selected[j].ID
+	})
+
+	page := &Page{}
+	if len(selected) > limit {
+		selected = selected[:limit]
+		page.NextCursor = selected[limit-1].ID
+	}
+
+	page.Embeddings = make([]*Embedding, len(selected))
+	for i, e := range selected {
+		page.Embeddings[i] = copyEmbedding(e)
+	}
+
+	return page, nil
+}
+
+// Stats describes the store's contents.
+func (s *MemoryStore) Stats(ctx context.Context) (*Stats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := &Stats{
+		Count:      len(s.embeddings),
+		Namespaces: map[string]int{},
+	}
+
+	sources := map[string]bool{}
+	for _, e := range s.embeddings {
+		stats.Namespaces[e.Namespace]++
+
+		switch {
+		case stats.Dimensions == 0:
+			stats.Dimensions = len(e.Vector)
+		case stats.Dimensions != len(e.Vector):
+			stats.Dimensions = -1
+		}
+
+		if source, ok := e.Metadata[MetadataSource].(string); ok {
+			sources[source] = true
+		}
+	}
+	stats.Sources = len(sources)
+
+	return stats, nil
+}
+
+// Save writes the store to its file. It is only needed after changing the
+// file's contents outside of the store, since every change is saved.
+func (s *MemoryStore) Save() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.saveLocked()
+}
+
+// memoryStoreFile is the JSON representation of a persisted MemoryStore.
+type memoryStoreFile struct {
+	Embeddings []*Embedding `json:"embeddings"`
+}
+
+func (s *MemoryStore) load() error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading memory store: %w", err)
+	}
+
+	file := &memoryStoreFile{}
+	if err := json.Unmarshal(data, file); err != nil {
+		return fmt.Errorf("error decoding memory store: %w", err)
+	}
+
+	for _, e := range file.Embeddings {
+		s.embeddings[e.ID] = e
+	}
+
+	if s.logger != nil {
+		s.logger.V(1).Info("loaded memory store", "path", s.path, "embeddings", len(file.Embeddings))
+	}
+
+	return nil
+}
+
+// saveLocked writes the store to a temporary file then renames it over the
+// store's file, so a crash never leaves a partially written store behind.
+func (s *MemoryStore) saveLocked() error {
+	if s.path == "" {
+		return nil
+	}
+
+	file := &memoryStoreFile{Embeddings: make([]*Embedding, 0, len(s.embeddings))}
+	for _, e := range s.embeddings {
+		file.Embeddings = append(file.Embeddings, e)
+	}
+
+	sort.Slice(file.Embeddings, func(i, j int) bool {
+		return file.Embeddings[i].ID < file.Embeddings[j].ID
+	})
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("error encoding memory store: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error saving memory store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error saving memory store: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error saving memory store: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("error saving memory store: %w", err)
+	}
+
+	return nil
+}
+
+// sortResults sorts by descending score, breaking ties by ID so that results
+// are deterministic.
+func sortResults(results []*SearchResult) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+
+		return results[i].Embedding.ID < results[j].Embedding.ID
+	})
+}
+
+func copyEmbedding(e *Embedding) *Embedding {
+	c := &Embedding{
+		ID:        e.ID,
+		Namespace: e.Namespace,
+		Content:   e.Content,
+		Vector:    append([]float32(nil), e.Vector...),
+	}
+
+	if e.Metadata != nil {
+		c.Metadata = make(map[string]any, len(e.Metadata))
+		for k, v := range e.Metadata {
+			c.Metadata[k] = v
+		}
+	}
+
+	return c
+}
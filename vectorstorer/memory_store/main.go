@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/agent-api/examples/vectorstorer"
+)
+
+func main() {
+	ctx := context.Background()
+
+	// Create a zap logger
+	config := zap.NewDevelopmentConfig()
+	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	zLogger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a logr.Logger using zapr adapter
+	logger := zapr.NewLogger(zLogger)
+
+	embedder := vectorstorer.NewOpenAIEmbedder(&vectorstorer.OpenAIEmbedderOpts{
+		Dimensions: 768,
+		Logger:     &logger,
+	})
+
+	// No database required: the store lives in memory and is persisted to a
+	// JSON file, so re-running the example reuses its embeddings
+	store, err := vectorstorer.NewMemoryStore(&vectorstorer.MemoryStoreOpts{
+		Embedder:   embedder,
+		Similarity: vectorstorer.Cosine,
+		Path:       "spells.json",
+		Logger:     &logger,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	spells := []struct {
+		school string
+		text   string
+	}{
+		{school: "evocation", text: "Fire Bolt - Cantrip - 120ft - You hurl a mote of fire at a creature or an object within range. On a hit, the target takes 1d10 Fire damage."},
+		{school: "evocation", text: "Ray of Frost - Cantrip - 60ft - A frigid beam of blue-white light streaks toward a creature within range. On a hit, it takes 1d8 Cold damage."},
+		{school: "conjuration", text: "Mage Hand - Cantrip - 30ft - A spectral, floating hand appears at a point you choose within range."},
+	}
+
+	// Only embed the spells the persisted store does not have yet
+	for _, spell := range spells {
+		id := vectorstorer.ContentHash(spell.text)
+
+		stored, err := store.Get(ctx, []string{id})
+		if err != nil {
+			panic(err)
+		}
+
+		if len(stored) > 0 {
+			continue
+		}
+
+		vectors, err := embedder.Embed(ctx, []string{spell.text})
+		if err != nil {
+			panic(err)
+		}
+
+		err = store.Upsert(ctx, []*vectorstorer.Embedding{{
+			ID:      id,
+			Content: spell.text,
+			Vector:  vectors[0],
+			Metadata: map[string]any{
+				"school": spell.school,
+			},
+		}})
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	logger.Info("searching", "embeddings", store.Len())
+	res, err := store.Search(ctx, &vectorstorer.SearchParams{
+		Query: "Which spells deal elemental damage?",
+		Limit: 2,
+		Filter: map[string]any{
+			"school": "evocation",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	for _, r := range res {
+		fmt.Printf("%.3f: %s\n", r.Score, r.Embedding.Content)
+	}
+}
@@ -0,0 +1,302 @@
+package vectorstorer
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// storeDocs are the embeddings the MemoryStore tests search.
+var storeDocs = []*Embedding{
+	{
+		ID:       "go",
+		Content:  "The Go gopher compiles fast programs",
+		Metadata: map[string]any{"lang": "go", MetadataSource: "go.md"},
+	},
+	{
+		ID:       "rust",
+		Content:  "The Rust crab borrows memory safely",
+		Metadata: map[string]any{"lang": "rust", MetadataSource: "rust.md"},
+	},
+	{
+		ID:       "python",
+		Content:  "Python snakes interpret scripts slowly",
+		Metadata: map[string]any{"lang": "python", MetadataSource: "python.md"},
+	},
+	{
+		ID:        "other-go",
+		Namespace: "other",
+		Content:   "A gopher in another namespace",
+		Metadata:  map[string]any{"lang": "go", MetadataSource: "go.md"},
+	},
+}
+
+// newTestStore returns a MemoryStore holding docs, embedded with a
+// HashEmbedder.
+func newTestStore(t *testing.T, opts *MemoryStoreOpts, docs []*Embedding) *MemoryStore {
+	t.Helper()
+
+	if opts.Embedder == nil {
+		opts.Embedder = NewHashEmbedder(&HashEmbedderOpts{})
+	}
+
+	store, err := NewMemoryStore(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the embeddings are updated in place
+	copies := make([]*Embedding, len(docs))
+	for i, e := range docs {
+		copies[i] = copyEmbedding(e)
+	}
+
+	if _, err := UpsertByContentHash(context.Background(), store, opts.Embedder, copies); err != nil {
+		t.Fatal(err)
+	}
+
+	return store
+}
+
+func resultIDs(results []*SearchResult) []string {
+	ids := []string{}
+	for _, r := range results {
+		ids = append(ids, r.Embedding.ID)
+	}
+
+	return ids
+}
+
+func TestMemoryStoreSearch(t *testing.T) {
+	store := newTestStore(t, &MemoryStoreOpts{}, storeDocs)
+
+	tests := []struct {
+		name    string
+		params  *SearchParams
+		first   string
+		count   int
+		wantErr bool
+	}{
+		{
+			name:   "most similar first",
+			params: &SearchParams{Query: "gopher compiles programs"},
+			first:  "go",
+			count:  3,
+		},
+		{
+			name:   "limit",
+			params: &SearchParams{Query: "crab memory", Limit: 1},
+			first:  "rust",
+			count:  1,
+		},
+		{
+			name:   "namespace",
+			params: &SearchParams{Query: "gopher", Namespace: "other"},
+			first:  "other-go",
+			count:  1,
+		},
+		{
+			name:   "unknown namespace",
+			params: &SearchParams{Query: "gopher", Namespace: "missing"},
+			count:  0,
+		},
+		{
+			name:   "filter",
+			params: &SearchParams{Query: "gopher", Filter: map[string]any{"lang": "rust"}},
+			first:  "rust",
+			count:  1,
+		},
+		{
+			name:   "predicate",
+			params: &SearchParams{Query: "gopher", Where: []*Predicate{Ne("lang", "go")}},
+			first:  "python",
+			count:  2,
+		},
+		{
+			name:    "invalid predicate",
+			params:  &SearchParams{Query: "gopher", Where: []*Predicate{{Key: "lang", Op: "like"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := store.Search(context.Background(), tt.params)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(results) != tt.count {
+				t.Fatalf("got %d results %v, want %d", len(results), resultIDs(results), tt.count)
+			}
+
+			if tt.count > 0 && results[0].Embedding.ID != tt.first {
+				t.Errorf("got %s first, want %s", results[0].Embedding.ID, tt.first)
+			}
+
+			for i := 1; i < len(results); i++ {
+				if results[i].Score > results[i-1].Score {
+					t.Errorf("results are not sorted by score: %v", resultIDs(results))
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreList(t *testing.T) {
+	store := newTestStore(t, &MemoryStoreOpts{}, storeDocs)
+
+	tests := []struct {
+		name  string
+		limit int
+		pages [][]string
+	}{
+		{
+			name:  "one page",
+			pages: [][]string{{"go", "python", "rust"}},
+		},
+		{
+			name:  "pages",
+			limit: 2,
+			pages: [][]string{{"go", "python"}, {"rust"}},
+		},
+		{
+			name:  "exact pages",
+			limit: 1,
+			pages: [][]string{{"go"}, {"python"}, {"rust"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor := ""
+			for i, want := range tt.pages {
+				page, err := store.List(context.Background(), &ListParams{Limit: tt.limit, Cursor: cursor})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				got := []string{}
+				for _, e := range page.Embeddings {
+					got = append(got, e.ID)
+				}
+
+				if !slices.Equal(got, want) {
+					t.Errorf("page %d: got %v, want %v", i, got, want)
+				}
+
+				last := i == len(tt.pages)-1
+				if last != (page.NextCursor == "") {
+					t.Errorf("page %d: unexpected next cursor %q", i, page.NextCursor)
+				}
+
+				cursor = page.NextCursor
+			}
+		})
+	}
+}
+
+func TestMemoryStoreDelete(t *testing.T) {
+	tests := []struct {
+		name     string
+		ids      []string
+		selector *Selector
+		deleted  int
+		left     []string
+	}{
+		{
+			name:    "by id",
+			ids:     []string{"go", "missing"},
+			deleted: 1,
+			left:    []string{"other-go", "python", "rust"},
+		},
+		{
+			name:     "by filter",
+			selector: &Selector{Filter: map[string]any{"lang": "go"}},
+			deleted:  1,
+			left:     []string{"other-go", "python", "rust"},
+		},
+		{
+			name:     "by predicate",
+			selector: &Selector{Where: []*Predicate{In(MetadataSource, "rust.md", "python.md")}},
+			deleted:  2,
+			left:     []string{"go", "other-go"},
+		},
+		{
+			name:     "whole namespace",
+			selector: &Selector{Namespace: "other"},
+			deleted:  1,
+			left:     []string{"go", "python", "rust"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newTestStore(t, &MemoryStoreOpts{}, storeDocs)
+
+			var deleted int
+			var err error
+			if tt.selector != nil {
+				deleted, err = store.DeleteWhere(ctx, tt.selector)
+			} else {
+				deleted, err = store.Delete(ctx, tt.ids)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if deleted != tt.deleted {
+				t.Errorf("deleted %d, want %d", deleted, tt.deleted)
+			}
+
+			left := []string{}
+			for _, e := range storeDocs {
+				if got, _ := store.Get(ctx, []string{e.ID}); len(got) == 1 {
+					left = append(left, e.ID)
+				}
+			}
+
+			slices.Sort(left)
+			if !slices.Equal(left, tt.left) {
+				t.Errorf("left %v, want %v", left, tt.left)
+			}
+		})
+	}
+}
+
+func TestMemoryStorePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	embedder := NewHashEmbedder(&HashEmbedderOpts{})
+
+	store := newTestStore(t, &MemoryStoreOpts{Embedder: embedder, Path: path}, storeDocs)
+	if _, err := store.Delete(context.Background(), []string{"python"}); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := NewMemoryStore(&MemoryStoreOpts{Embedder: embedder, Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.Len() != len(storeDocs)-1 {
+		t.Fatalf("loaded %d embeddings, want %d", loaded.Len(), len(storeDocs)-1)
+	}
+
+	results, err := loaded.Search(context.Background(), &SearchParams{Query: "crab memory", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ids := resultIDs(results); !slices.Equal(ids, []string{"rust"}) {
+		t.Errorf("got %v, want [rust]", ids)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/agent-api/examples/vectorstorer"
+)
+
+type document struct {
+	id        string
+	tenant    string
+	content   string
+	tags      []string
+	published time.Time
+}
+
+func main() {
+	ctx := context.Background()
+
+	embedder := vectorstorer.NewHashEmbedder(&vectorstorer.HashEmbedderOpts{
+		NGram: 3,
+	})
+
+	store, err := vectorstorer.NewMemoryStore(&vectorstorer.MemoryStoreOpts{
+		Embedder: embedder,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	now := time.Now()
+	docs := []document{
+		{id: "acme-1", tenant: "acme", content: "Acme refund policy: refunds are issued within 30 days of purchase.", tags: []string{"billing", "policy"}, published: now.AddDate(0, -6, 0)},
+		{id: "acme-2", tenant: "acme", content: "Acme refund policy update: refunds are now issued within 60 days.", tags: []string{"billing", "policy"}, published: now.AddDate(0, -1, 0)},
+		{id: "acme-3", tenant: "acme", content: "Acme office hours are 9am to 5pm on weekdays.", tags: []string{"support"}, published: now.AddDate(0, -2, 0)},
+		{id: "globex-1", tenant: "globex", content: "Globex refund policy: no refunds after shipping.", tags: []string{"billing", "policy"}, published: now.AddDate(0, -1, 0)},
+	}
+
+	embeddings := []*vectorstorer.Embedding{}
+	for _, d := range docs {
+		vectors, err := embedder.Embed(ctx, []string{d.content})
+		if err != nil {
+			panic(err)
+		}
+
+		embeddings = append(embeddings, &vectorstorer.Embedding{
+			ID:        d.id,
+			Namespace: d.tenant,
+			Content:   d.content,
+			Vector:    vectors[0],
+			Metadata: map[string]any{
+				"source":    d.id + ".md",
+				"tags":      d.tags,
+				"published": vectorstorer.FormatTimestamp(d.published),
+			},
+		})
+	}
+
+	if err := store.Upsert(ctx, embeddings); err != nil {
+		panic(err)
+	}
+
+	// Each tenant only ever sees its own documents, and only recent billing
+	// policies among them
+	for _, tenant := range []string{"acme", "globex"} {
+		res, err := store.Search(ctx, &vectorstorer.SearchParams{
+			Query:     "how long do refunds take?",
+			Namespace: tenant,
+			Limit:     3,
+			Where: []*vectorstorer.Predicate{
+				vectorstorer.Contains("tags", "billing"),
+				vectorstorer.Gte("published", vectorstorer.FormatTimestamp(now.AddDate(0, -3, 0))),
+			},
+		})
+		if err != nil {
+			panic(err)
+		}
+
+		fmt.Printf("tenant %s:\n", tenant)
+		for _, r := range res {
+			fmt.Printf("  %.3f %s: %s\n", r.Score, r.Embedding.ID, r.Embedding.Content)
+		}
+	}
+}
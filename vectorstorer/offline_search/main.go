@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agent-api/examples/vectorstorer"
+)
+
+func main() {
+	ctx := context.Background()
+
+	// A hashing embedder needs no API key or model, and always produces the
+	// same vectors for the same text
+	embedder := vectorstorer.NewHashEmbedder(&vectorstorer.HashEmbedderOpts{
+		Dimensions: 768,
+		NGram:      3,
+	})
+
+	store, err := vectorstorer.NewMemoryStore(&vectorstorer.MemoryStoreOpts{
+		Embedder: embedder,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = store.Add(ctx, []string{
+		"Fire Bolt - Cantrip - 120ft - You hurl a mote of fire at a creature or an object within range. On a hit, the target takes 1d10 Fire damage.",
+		"Ray of Frost - Cantrip - 60ft - A frigid beam of blue-white light streaks toward a creature within range. On a hit, it takes 1d8 Cold damage.",
+		"Mage Hand - Cantrip - 30ft - A spectral, floating hand appears at a point you choose within range.",
+		"Shield - Level 1 - Self - An invisible barrier of magical force appears and protects you. You gain a +5 bonus to AC.",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	for _, query := range []string{"fire bolts", "cold damage", "floating hand", "armor class bonus"} {
+		res, err := store.Search(ctx, &vectorstorer.SearchParams{
+			Query: query,
+			Limit: 1,
+		})
+		if err != nil {
+			panic(err)
+		}
+
+		fmt.Printf("%q -> %.3f: %s\n", query, res[0].Score, res[0].Embedding.Content)
+	}
+}
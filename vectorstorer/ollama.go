@@ -0,0 +1,189 @@
+package vectorstorer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+// Ollama embedding models.
+const (
+	// OllamaNomicEmbedText produces 768 dimensional vectors
+	OllamaNomicEmbedText string = "nomic-embed-text"
+
+	// OllamaMxbaiEmbedLarge produces 1024 dimensional vectors
+	OllamaMxbaiEmbedLarge string = "mxbai-embed-large"
+)
+
+// OllamaEmbedderOpts configures a new OllamaEmbedder.
+type OllamaEmbedderOpts struct {
+	// BaseURL and Port of the Ollama server default to http://localhost and
+	// 11434
+	BaseURL string
+	Port    int
+
+	// Model defaults to OllamaNomicEmbedText
+	Model string
+
+	// BatchSize is the maximum number of inputs per request. Defaults to 32.
+	BatchSize int
+
+	// HTTPClient defaults to http.DefaultClient
+	HTTPClient *http.Client
+
+	Logger *logr.Logger
+}
+
+// OllamaEmbedder is an Embedder using Ollama's /api/embed endpoint. The
+// dimensions of its vectors are detected from the model's first response.
+type OllamaEmbedder struct {
+	url       string
+	model     string
+	batchSize int
+	client    *http.Client
+
+	mu         sync.Mutex
+	dimensions int
+
+	logger *logr.Logger
+}
+
+// NewOllamaEmbedder creates a new OllamaEmbedder.
+func NewOllamaEmbedder(opts *OllamaEmbedderOpts) *OllamaEmbedder {
+	if opts.BaseURL == "" {
+		opts.BaseURL = "http://localhost"
+	}
+
+	if opts.Port == 0 {
+		opts.Port = 11434
+	}
+
+	if opts.Model == "" {
+		opts.Model = OllamaNomicEmbedText
+	}
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 32
+	}
+
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+
+	return &OllamaEmbedder{
+		url:       fmt.Sprintf("%s:%d/api/embed", strings.TrimSuffix(opts.BaseURL, "/"), opts.Port),
+		model:     opts.Model,
+		batchSize: opts.BatchSize,
+		client:    opts.HTTPClient,
+		logger:    opts.Logger,
+	}
+}
+
+type ollamaEmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type ollamaEmbedResponse struct {
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
+// Dimensions returns the length of the model's vectors, embedding a probe
+// input if no text was embedded yet.
+func (e *OllamaEmbedder) Dimensions(ctx context.Context) (int, error) {
+	e.mu.Lock()
+	dimensions := e.dimensions
+	e.mu.Unlock()
+
+	if dimensions > 0 {
+		return dimensions, nil
+	}
+
+	vectors, err := e.Embed(ctx, []string{"dimensions"})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(vectors[0]), nil
+}
+
+// Embed embeds the texts in batches of at most BatchSize inputs.
+func (e *OllamaEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+
+	for start := 0; start < len(texts); start += e.batchSize {
+		batch := texts[start:min(start+e.batchSize, len(texts))]
+
+		batchVectors, err := e.embedBatch(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+
+		vectors = append(vectors, batchVectors...)
+	}
+
+	return vectors, nil
+}
+
+func (e *OllamaEmbedder) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(&ollamaEmbedRequest{
+		Model: e.model,
+		Input: texts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if e.logger != nil {
+		e.logger.V(1).Info("creating ollama embeddings", "model", e.model, "inputs", len(texts))
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error creating embeddings: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("error creating embeddings: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	out := &ollamaEmbedResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, fmt.Errorf("error decoding embeddings: %w", err)
+	}
+
+	if len(out.Embeddings) != len(texts) {
+		return nil, errors.New("embeddings response does not match the number of inputs")
+	}
+
+	e.mu.Lock()
+	if e.dimensions == 0 {
+		e.dimensions = len(out.Embeddings[0])
+	}
+	dimensions := e.dimensions
+	e.mu.Unlock()
+
+	for _, v := range out.Embeddings {
+		if len(v) != dimensions {
+			return nil, fmt.Errorf("embedding has %d dimensions, expected %d", len(v), dimensions)
+		}
+	}
+
+	return out.Embeddings, nil
+}
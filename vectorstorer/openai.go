@@ -0,0 +1,99 @@
+package vectorstorer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+)
+
+// OpenAIEmbedderOpts configures a new OpenAIEmbedder.
+type OpenAIEmbedderOpts struct {
+	// APIKey defaults to the OPENAI_API_KEY environment variable
+	APIKey string
+
+	// Model defaults to text-embedding-3-small
+	Model openai.EmbeddingModel
+
+	// Dimensions shortens the returned vectors, i.e., to 768 to match the
+	// pgvector examples. Zero keeps the model's default dimensions.
+	Dimensions int
+
+	Logger *logr.Logger
+}
+
+// OpenAIEmbedder is an Embedder using the OpenAI embeddings API.
+type OpenAIEmbedder struct {
+	client     *openai.Client
+	model      openai.EmbeddingModel
+	dimensions int
+
+	logger *logr.Logger
+}
+
+// NewOpenAIEmbedder creates a new OpenAIEmbedder.
+func NewOpenAIEmbedder(opts *OpenAIEmbedderOpts) *OpenAIEmbedder {
+	if opts.Model == "" {
+		opts.Model = openai.EmbeddingModelTextEmbedding3Small
+	}
+
+	clientOpts := []option.RequestOption{}
+	if opts.APIKey != "" {
+		clientOpts = append(clientOpts, option.WithAPIKey(opts.APIKey))
+	}
+
+	return &OpenAIEmbedder{
+		client:     openai.NewClient(clientOpts...),
+		model:      opts.Model,
+		dimensions: opts.Dimensions,
+		logger:     opts.Logger,
+	}
+}
+
+// Embed embeds the texts in a single request.
+func (e *OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	params := openai.EmbeddingNewParams{
+		Input: openai.F[openai.EmbeddingNewParamsInputUnion](openai.EmbeddingNewParamsInputArrayOfStrings(texts)),
+		Model: openai.F(e.model),
+	}
+
+	if e.dimensions > 0 {
+		params.Dimensions = openai.F(int64(e.dimensions))
+	}
+
+	if e.logger != nil {
+		e.logger.V(1).Info("creating openai embeddings", "model", e.model, "inputs", len(texts))
+	}
+
+	resp, err := e.client.Embeddings.New(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("error creating embeddings: %w", err)
+	}
+
+	if len(resp.Data) != len(texts) {
+		return nil, errors.New("embeddings response does not match the number of inputs")
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, d := range resp.Data {
+		if d.Index < 0 || int(d.Index) >= len(texts) {
+			return nil, fmt.Errorf("embedding index out of range: %d", d.Index)
+		}
+
+		vector := make([]float32, len(d.Embedding))
+		for i, v := range d.Embedding {
+			vector[i] = float32(v)
+		}
+
+		vectors[d.Index] = vector
+	}
+
+	return vectors, nil
+}
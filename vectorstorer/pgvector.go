@@ -0,0 +1,585 @@
+package vectorstorer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pgvector/pgvector-go"
+)
+
+const defaultSearchLimit int = 5
+
+// PgVectorStoreOpts configures a new PgVectorStore.
+type PgVectorStoreOpts struct {
+	ConnectionString string
+
+	// TableName is created along with the vector extension if it does not
+	// exist
+	TableName string
+
+	// Dimensions of the embedder's vectors. It may be left zero for
+	// embedders implementing Dimensioner.
+	Dimensions int
+
+	Embedder Embedder
+
+	Logger *logr.Logger
+}
+
+// PgVectorStore is a Store backed by a Postgres table using the pgvector
+// extension. Similarity is cosine similarity.
+type PgVectorStore struct {
+	pool      *pgxpool.Pool
+	tableName string
+	table     string
+	embedder  Embedder
+
+	logger *logr.Logger
+}
+
+// NewPgVectorStore connects to Postgres and creates the store's table.
+func NewPgVectorStore(ctx context.Context, opts *PgVectorStoreOpts) (*PgVectorStore, error) {
+	if opts.TableName == "" || opts.Embedder == nil {
+		return nil, errors.New("pgvector store requires a table name and an embedder")
+	}
+
+	if opts.Dimensions <= 0 {
+		d, ok := opts.Embedder.(Dimensioner)
+		if !ok {
+			return nil, errors.New("pgvector store requires dimensions for an embedder that does not detect them")
+		}
+
+		dimensions, err := d.Dimensions(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error detecting embedding dimensions: %w", err)
+		}
+		opts.Dimensions = dimensions
+	}
+
+	pool, err := pgxpool.New(ctx, opts.ConnectionString)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to postgres: %w", err)
+	}
+
+	s := &PgVectorStore{
+		pool:      pool,
+		tableName: opts.TableName,
+		table:     pgx.Identifier{opts.TableName}.Sanitize(),
+		embedder:  opts.Embedder,
+		logger:    opts.Logger,
+	}
+
+	if err := s.migrate(ctx, opts.Dimensions); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *PgVectorStore) migrate(ctx context.Context, dimensions int) error {
+	statements := []string{
+		"CREATE EXTENSION IF NOT EXISTS vector",
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id TEXT PRIMARY KEY,
+			content TEXT NOT NULL,
+			metadata JSONB NOT NULL DEFAULT '{}',
+			embedding vector(%d) NOT NULL
+		)`, s.table, dimensions),
+
+		// tables created before namespaces existed
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS namespace TEXT NOT NULL DEFAULT ''", s.table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (namespace)", s.indexName("namespace"), s.table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING GIN (metadata)", s.indexName("metadata"), s.table),
+
+		// full text search for LexicalSearch. The simple configuration does
+		// not stem, so exact identifiers match as written.
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS content_tsv tsvector GENERATED ALWAYS AS (to_tsvector('simple', content)) STORED", s.table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING GIN (content_tsv)", s.indexName("content_tsv"), s.table),
+	}
+
+	for _, stmt := range statements {
+		if _, err := s.pool.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("error migrating pgvector store: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *PgVectorStore) indexName(column string) string {
+	return pgx.Identifier{s.tableName + "_" + column + "_idx"}.Sanitize()
+}
+
+// Close closes the store's connections.
+func (s *PgVectorStore) Close() {
+	s.pool.Close()
+}
+
+// Add embeds and stores the contents. Contents already stored are not
+// embedded again.
+func (s *PgVectorStore) Add(ctx context.Context, contents []string) ([]*Embedding, error) {
+	embeddings := make([]*Embedding, len(contents))
+	for i, content := range contents {
+		embeddings[i] = &Embedding{
+			ID:      ContentHash(content),
+			Content: content,
+		}
+	}
+
+	if _, err := UpsertByContentHash(ctx, s, s.embedder, embeddings); err != nil {
+		return nil, err
+	}
+
+	return embeddings, nil
+}
+
+// Upsert inserts or replaces the embeddings in a single transaction.
+func (s *PgVectorStore) Upsert(ctx context.Context, embeddings []*Embedding) error {
+	batch := &pgx.Batch{}
+
+	for _, e := range embeddings {
+		if e.ID == "" || len(e.Vector) == 0 {
+			return errors.New("upserted embeddings must have an ID and a vector")
+		}
+
+		metadata, err := json.Marshal(normalizeMetadata(e.Metadata))
+		if err != nil {
+			return fmt.Errorf("error encoding metadata of %s: %w", e.ID, err)
+		}
+
+		if e.Metadata == nil {
+			metadata = []byte("{}")
+		}
+
+		batch.Queue(fmt.Sprintf(`INSERT INTO %s (id, namespace, content, metadata, embedding)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (id) DO UPDATE SET
+				namespace = EXCLUDED.namespace,
+				content = EXCLUDED.content,
+				metadata = EXCLUDED.metadata,
+				embedding = EXCLUDED.embedding`, s.table),
+			e.ID, e.Namespace, e.Content, metadata, pgvector.NewVector(e.Vector),
+		)
+	}
+
+	if s.logger != nil {
+		s.logger.V(1).Info("upserting embeddings", "count", len(embeddings))
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("error upserting embeddings: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Get returns the stored embeddings with the given IDs.
+func (s *PgVectorStore) Get(ctx context.Context, ids []string) ([]*Embedding, error) {
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(
+		"SELECT id, namespace, content, metadata, embedding::text FROM %s WHERE id = ANY($1)", s.table,
+	), ids)
+	if err != nil {
+		return nil, fmt.Errorf("error getting embeddings: %w", err)
+	}
+
+	return collectEmbeddings(rows)
+}
+
+// Search returns the stored embeddings of the search's namespace most similar
+// to the query, among those matching its filter and predicates.
+func (s *PgVectorStore) Search(ctx context.Context, params *SearchParams) ([]*SearchResult, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	if err := validateSearch(params); err != nil {
+		return nil, err
+	}
+
+	vectors, err := s.embedder.Embed(ctx, []string{params.Query})
+	if err != nil {
+		return nil, err
+	}
+
+	args := []any{pgvector.NewVector(vectors[0]), limit}
+	where, args, err := whereClause(params, args)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`SELECT id, namespace, content, metadata, embedding::text, 1 - (embedding <=> $1) AS score
+		FROM %s WHERE %s ORDER BY embedding <=> $1 LIMIT $2`, s.table, where),
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error searching embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	results := []*SearchResult{}
+	for rows.Next() {
+		e, score, err := scanEmbedding(rows, true)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, &SearchResult{Embedding: e, Score: score})
+	}
+
+	return results, rows.Err()
+}
+
+// LexicalSearch ranks the stored embeddings of the search's namespace that
+// match its filter and predicates by full text search of the query's terms,
+// any of which may match.
+func (s *PgVectorStore) LexicalSearch(ctx context.Context, params *SearchParams) ([]*SearchResult, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	if err := validateSearch(params); err != nil {
+		return nil, err
+	}
+
+	// terms are letters and digits only, so they are safe to quote as
+	// tsquery lexemes
+	terms := Tokenize(params.Query)
+	if len(terms) == 0 {
+		return []*SearchResult{}, nil
+	}
+
+	for i, t := range terms {
+		terms[i] = "'" + t + "'"
+	}
+
+	args := []any{strings.Join(terms, " | "), limit}
+	where, args, err := whereClause(params, args)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`SELECT id, namespace, content, metadata, embedding::text, ts_rank_cd(content_tsv, query) AS score
+		FROM %s, to_tsquery('simple', $1) query
+		WHERE content_tsv @@ query AND %s ORDER BY score DESC LIMIT $2`, s.table, where),
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error searching content: %w", err)
+	}
+	defer rows.Close()
+
+	results := []*SearchResult{}
+	for rows.Next() {
+		e, score, err := scanEmbedding(rows, true)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, &SearchResult{Embedding: e, Score: score})
+	}
+
+	return results, rows.Err()
+}
+
+// Delete deletes the embeddings with the given IDs.
+func (s *PgVectorStore) Delete(ctx context.Context, ids []string) (int, error) {
+	tag, err := s.pool.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE id = ANY($1)", s.table), ids)
+	if err != nil {
+		return 0, fmt.Errorf("error deleting embeddings: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// DeleteWhere deletes the embeddings the selector selects.
+func (s *PgVectorStore) DeleteWhere(ctx context.Context, selector *Selector) (int, error) {
+	params := selector.searchParams()
+	if err := validateSearch(params); err != nil {
+		return 0, err
+	}
+
+	where, args, err := whereClause(params, []any{})
+	if err != nil {
+		return 0, err
+	}
+
+	tag, err := s.pool.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE %s", s.table, where), args...)
+	if err != nil {
+		return 0, fmt.Errorf("error deleting embeddings: %w", err)
+	}
+
+	if s.logger != nil {
+		s.logger.V(1).Info("deleted embeddings", "count", tag.RowsAffected())
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// List returns a page of the selected embeddings, in ID order. Pages are
+// keyed by the last ID of the previous page, so they stay consistent while
+// embeddings are upserted.
+func (s *PgVectorStore) List(ctx context.Context, params *ListParams) (*Page, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	search := params.searchParams()
+	if err := validateSearch(search); err != nil {
+		return nil, err
+	}
+
+	// one more than the page is fetched to tell whether there is a next page
+	args := []any{params.Cursor, limit + 1}
+	where, args, err := whereClause(search, args)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`SELECT id, namespace, content, metadata, embedding::text
+		FROM %s WHERE id > $1 AND %s ORDER BY id LIMIT $2`, s.table, where),
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error listing embeddings: %w", err)
+	}
+
+	embeddings, err := collectEmbeddings(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page{Embeddings: embeddings}
+	if len(embeddings) > limit {
+		page.Embeddings = embeddings[:limit]
+		page.NextCursor = embeddings[limit-1].ID
+	}
+
+	return page, nil
+}
+
+// Stats describes the table's contents. Dimensions are those of the
+// table's vector column, even when it is empty.
+func (s *PgVectorStore) Stats(ctx context.Context) (*Stats, error) {
+	stats := &Stats{
+		Namespaces: map[string]int{},
+	}
+
+	rows, err := s.pool.Query(ctx, fmt.Sprintf("SELECT namespace, count(*) FROM %s GROUP BY namespace", s.table))
+	if err != nil {
+		return nil, fmt.Errorf("error counting embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var namespace string
+		var count int
+		if err := rows.Scan(&namespace, &count); err != nil {
+			return nil, err
+		}
+
+		stats.Namespaces[namespace] = count
+		stats.Count += count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// the type modifier of a vector column is its dimensions
+	err = s.pool.QueryRow(ctx, fmt.Sprintf(`SELECT
+			(SELECT atttypmod FROM pg_attribute WHERE attrelid = $1::regclass AND attname = 'embedding'),
+			(SELECT count(DISTINCT metadata ->> $2) FROM %s)`, s.table),
+		s.table, MetadataSource,
+	).Scan(&stats.Dimensions, &stats.Sources)
+	if err != nil {
+		return nil, fmt.Errorf("error getting store stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+func collectEmbeddings(rows pgx.Rows) ([]*Embedding, error) {
+	defer rows.Close()
+
+	embeddings := []*Embedding{}
+	for rows.Next() {
+		e, _, err := scanEmbedding(rows, false)
+		if err != nil {
+			return nil, err
+		}
+
+		embeddings = append(embeddings, e)
+	}
+
+	return embeddings, rows.Err()
+}
+
+// scanEmbedding scans a row of id, namespace, content, metadata and
+// embedding text, followed by a score when withScore is set.
+func scanEmbedding(rows pgx.Rows, withScore bool) (*Embedding, float64, error) {
+	e := &Embedding{}
+	var metadata []byte
+	var vectorText string
+	var score float64
+
+	dest := []any{&e.ID, &e.Namespace, &e.Content, &metadata, &vectorText}
+	if withScore {
+		dest = append(dest, &score)
+	}
+
+	if err := rows.Scan(dest...); err != nil {
+		return nil, 0, err
+	}
+
+	if err := json.Unmarshal(metadata, &e.Metadata); err != nil {
+		return nil, 0, fmt.Errorf("error decoding metadata of %s: %w", e.ID, err)
+	}
+
+	var vector pgvector.Vector
+	if err := vector.Parse(vectorText); err != nil {
+		return nil, 0, fmt.Errorf("error decoding vector of %s: %w", e.ID, err)
+	}
+	e.Vector = vector.Slice()
+
+	return e, score, nil
+}
+
+// whereClause builds the SQL condition of a search's namespace, filter and
+// predicates on the jsonb metadata column, appending its parameters to args.
+func whereClause(params *SearchParams, args []any) (string, []any, error) {
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	jsonArg := func(v any) (string, error) {
+		data, err := json.Marshal(normalizeValue(v))
+		if err != nil {
+			return "", fmt.Errorf("error encoding filter value: %w", err)
+		}
+
+		return arg(data) + "::jsonb", nil
+	}
+
+	conditions := []string{"namespace = " + arg(params.Namespace)}
+
+	if len(params.Filter) > 0 {
+		filter, err := jsonArg(params.Filter)
+		if err != nil {
+			return "", nil, err
+		}
+
+		// filters match by jsonb containment
+		conditions = append(conditions, "metadata @> "+filter)
+	}
+
+	for _, p := range params.Where {
+		key := arg(p.Key)
+		value := "NULL"
+
+		if p.Op != OpExists {
+			v := p.Value
+			if p.Op == OpContains {
+				v = []any{p.Value}
+			}
+
+			var err error
+			value, err = jsonArg(v)
+			if err != nil {
+				return "", nil, err
+			}
+		}
+
+		var condition string
+		switch p.Op {
+		case OpEq:
+			condition = fmt.Sprintf("metadata -> %s = %s", key, value)
+		case OpNe:
+			condition = fmt.Sprintf("metadata -> %s IS DISTINCT FROM %s", key, value)
+		case OpGt, OpGte, OpLt, OpLte:
+			condition = rangeCondition(p, key, value)
+		case OpIn:
+			condition = fmt.Sprintf("metadata -> %s IN (SELECT jsonb_array_elements(%s))", key, value)
+		case OpContains:
+			condition = fmt.Sprintf("metadata -> %s @> %s", key, value)
+		case OpExists:
+			condition = fmt.Sprintf("metadata ? %s", key)
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+// rangeOperators are the SQL operators of the range predicates.
+var rangeOperators = map[Operator]string{
+	OpGt:  ">",
+	OpGte: ">=",
+	OpLt:  "<",
+	OpLte: "<=",
+}
+
+// rangeCondition compares a metadata key to a number or string value. jsonb
+// orders values of different types, i.e., every number before every string,
+// so the key must have the value's type, and other values match nothing, as
+// they do in the MemoryStore.
+func rangeCondition(p *Predicate, key, value string) string {
+	return fmt.Sprintf("jsonb_typeof(metadata -> %s) = jsonb_typeof(%s) AND jsonb_typeof(%s) IN ('number', 'string') AND metadata -> %s %s %s",
+		key, value, value, key, rangeOperators[p.Op], value)
+}
+
+// normalizeMetadata formats timestamps, both time.Time and RFC 3339 strings,
+// with FormatTimestamp, so that they order correctly as jsonb strings.
+func normalizeMetadata(metadata map[string]any) map[string]any {
+	if metadata == nil {
+		return nil
+	}
+
+	out := make(map[string]any, len(metadata))
+	for k, v := range metadata {
+		out[k] = normalizeValue(v)
+	}
+
+	return out
+}
+
+func normalizeValue(v any) any {
+	switch val := v.(type) {
+	case time.Time:
+		return FormatTimestamp(val)
+	case string:
+		// RFC 3339 strings compare as times in the MemoryStore
+		if t, err := time.Parse(time.RFC3339Nano, val); err == nil {
+			return FormatTimestamp(t)
+		}
+		return val
+	case map[string]any:
+		return normalizeMetadata(val)
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = normalizeValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
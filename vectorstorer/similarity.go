@@ -0,0 +1,64 @@
+package vectorstorer
+
+import "math"
+
+// Similarity is the measure used to rank embeddings against a query.
+type Similarity int
+
+const (
+	// Cosine is the cosine of the angle between vectors, in [-1, 1]
+	Cosine Similarity = iota
+
+	// DotProduct is the inner product of vectors. It equals Cosine for
+	// normalized vectors.
+	DotProduct
+
+	// Euclidean ranks by L2 distance, scored as 1 / (1 + distance) so that
+	// higher is more similar
+	Euclidean
+)
+
+func (s Similarity) String() string {
+	switch s {
+	case DotProduct:
+		return "dot_product"
+	case Euclidean:
+		return "euclidean"
+	default:
+		return "cosine"
+	}
+}
+
+// Score returns the similarity of two vectors of equal length.
+func (s Similarity) Score(a, b []float32) float64 {
+	switch s {
+	case DotProduct:
+		return dot(a, b)
+
+	case Euclidean:
+		sum := 0.0
+		for i := range a {
+			d := float64(a[i]) - float64(b[i])
+			sum += d * d
+		}
+
+		return 1 / (1 + math.Sqrt(sum))
+
+	default:
+		norms := math.Sqrt(dot(a, a)) * math.Sqrt(dot(b, b))
+		if norms == 0 {
+			return 0
+		}
+
+		return dot(a, b) / norms
+	}
+}
+
+func dot(a, b []float32) float64 {
+	sum := 0.0
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+
+	return sum
+}
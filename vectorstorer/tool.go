@@ -0,0 +1,123 @@
+package vectorstorer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/agent-api/core"
+)
+
+// SearchToolOpts configures a new search tool.
+type SearchToolOpts struct {
+	// Store is searched with the model's queries. Any VectorStorer works,
+	// i.e., a HybridStore for hybrid search.
+	Store VectorStorer
+
+	// Name defaults to "search_knowledge_base"
+	Name string
+
+	// Description tells the model what the store contains. Defaults to a
+	// generic description.
+	Description string
+
+	// Limit is the number of results returned per search. Defaults to 5.
+	Limit int
+
+	// Namespace, Filter and Where restrict every search, i.e., to a tenant's
+	// documents. The model cannot change them.
+	Namespace string
+	Filter    map[string]any
+	Where     []*Predicate
+}
+
+const searchToolSchema string = `{
+  "title": "search",
+  "description": "Search the knowledge base",
+  "type": "object",
+  "properties": {
+    "query": {
+      "description": "What to search for, i.e., a question or the keywords of the information needed",
+      "type": "string"
+    }
+  },
+  "required": [
+    "query"
+  ]
+}`
+
+type searchToolParams struct {
+	Query string `json:"query"`
+}
+
+// SearchToolResult is a single search result as it is returned to the model.
+// RetrievalScore is only set when the store reranks its results.
+type SearchToolResult struct {
+	ID             string  `json:"id"`
+	Source         string  `json:"source,omitempty"`
+	Content        string  `json:"content"`
+	Score          float64 `json:"score"`
+	RetrievalScore float64 `json:"retrieval_score,omitempty"`
+}
+
+// NewSearchTool wraps a store's Search as a core.Tool, so that agents
+// retrieve from the store by calling the tool with a query.
+func NewSearchTool(opts *SearchToolOpts) (*core.Tool, error) {
+	if opts.Store == nil {
+		return nil, errors.New("search tool requires a store")
+	}
+
+	if opts.Name == "" {
+		opts.Name = "search_knowledge_base"
+	}
+
+	if opts.Description == "" {
+		opts.Description = "Search the knowledge base for information relevant to the user's question. Returns the most relevant passages first."
+	}
+
+	if opts.Limit <= 0 {
+		opts.Limit = 5
+	}
+
+	return &core.Tool{
+		Name:        opts.Name,
+		Description: opts.Description,
+		JSONSchema:  []byte(searchToolSchema),
+		WrappedToolFunction: func(ctx context.Context, args []byte) (interface{}, error) {
+			params := &searchToolParams{}
+			if err := json.Unmarshal(args, params); err != nil {
+				return nil, fmt.Errorf("error unmarshaling args: %w", err)
+			}
+
+			if params.Query == "" {
+				return nil, errors.New("query is required")
+			}
+
+			results, err := opts.Store.Search(ctx, &SearchParams{
+				Query:     params.Query,
+				Limit:     opts.Limit,
+				Namespace: opts.Namespace,
+				Filter:    opts.Filter,
+				Where:     opts.Where,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			found := make([]*SearchToolResult, len(results))
+			for i, r := range results {
+				source, _ := r.Embedding.Metadata[MetadataSource].(string)
+				found[i] = &SearchToolResult{
+					ID:             r.Embedding.ID,
+					Source:         source,
+					Content:        r.Embedding.Content,
+					Score:          r.Score,
+					RetrievalScore: r.RetrievalScore,
+				}
+			}
+
+			return found, nil
+		},
+	}, nil
+}
@@ -0,0 +1,91 @@
+package vectorstorer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestSearchTool(t *testing.T) {
+	store := newTestStore(t, &MemoryStoreOpts{}, storeDocs)
+
+	hybrid, err := NewHybridStore(&HybridStoreOpts{Store: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    *SearchToolOpts
+		args    string
+		ids     []string
+		wantErr bool
+	}{
+		{
+			name: "hybrid search",
+			opts: &SearchToolOpts{Store: hybrid, Limit: 1},
+			args: `{"query":"crab memory"}`,
+			ids:  []string{"rust"},
+		},
+		{
+			name: "namespace",
+			opts: &SearchToolOpts{Store: store, Namespace: "other"},
+			args: `{"query":"gopher"}`,
+			ids:  []string{"other-go"},
+		},
+		{
+			name: "where",
+			opts: &SearchToolOpts{Store: store, Where: []*Predicate{Eq("lang", "python")}},
+			args: `{"query":"gopher"}`,
+			ids:  []string{"python"},
+		},
+		{
+			name:    "missing query",
+			opts:    &SearchToolOpts{Store: store},
+			args:    `{}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid args",
+			opts:    &SearchToolOpts{Store: store},
+			args:    `query`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool, err := NewSearchTool(tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := tool.WrappedToolFunction(context.Background(), json.RawMessage(tt.args))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			found := out.([]*SearchToolResult)
+			if len(found) != len(tt.ids) {
+				t.Fatalf("got %d results, want %v", len(found), tt.ids)
+			}
+
+			for i, r := range found {
+				if r.ID != tt.ids[i] {
+					t.Errorf("result %d: got %s, want %s", i, r.ID, tt.ids[i])
+				}
+
+				if r.Content == "" || r.Source == "" {
+					t.Errorf("result %d is missing its content or source: %+v", i, r)
+				}
+			}
+		})
+	}
+}
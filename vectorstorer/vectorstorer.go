@@ -0,0 +1,124 @@
+// Package vectorstorer defines the embedder and vector store interfaces used by
+// the RAG examples, along with embedders and stores implementing them.
+//
+// It replaces the github.com/agent-api/pgvector store and core.SearchParams:
+// agents search a store through NewSearchTool rather than the agent's own
+// vector store option, so any store, i.e., a HybridStore, can back them.
+package vectorstorer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// Metadata keys set on chunks ingested from documents.
+const (
+	MetadataSource      string = "source"
+	MetadataChunkIndex  string = "chunk_index"
+	MetadataContentHash string = "content_hash"
+)
+
+// Embedding is a piece of stored content along with its vector.
+type Embedding struct {
+	// ID uniquely identifies the embedding within its store
+	ID string `json:"id"`
+
+	// Namespace isolates embeddings, i.e., per tenant. Searches only match
+	// embeddings of their own namespace.
+	Namespace string `json:"namespace,omitempty"`
+
+	Content string    `json:"content"`
+	Vector  []float32 `json:"vector"`
+
+	// Metadata values must be JSON compatible
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// SearchParams are the parameters of a similarity search.
+type SearchParams struct {
+	Query string
+
+	// Limit is the maximum number of results. Stores default to 5.
+	Limit int
+
+	// Namespace restricts the search to embeddings of the namespace. The
+	// empty namespace is a namespace like any other, not a wildcard.
+	Namespace string
+
+	// Filter restricts the search to embeddings whose metadata contains the
+	// filter, like Postgres' jsonb containment: each of the filter's keys
+	// must be set to the same value, except arrays and objects, which must
+	// contain the filter's elements, i.e., {"tags": ["a"]} matches tags
+	// ["a", "b"]
+	Filter map[string]any
+
+	// Where restricts the search to embeddings whose metadata satisfies
+	// every predicate. Filters apply before similarity ranking.
+	Where []*Predicate
+}
+
+// SearchResult is a single match of a similarity search.
+type SearchResult struct {
+	Embedding *Embedding
+
+	// Score is the similarity of the embedding to the query. Higher is more
+	// similar.
+	Score float64
+
+	// RetrievalScore is the score the result was retrieved with when Score
+	// was replaced by a later stage, i.e., a reranker.
+	RetrievalScore float64
+}
+
+// Embedder creates embedding vectors for a batch of texts. The returned
+// vectors are in the same order as the texts.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// Dimensioner is implemented by embedders that know, or can detect, the
+// length of their vectors.
+type Dimensioner interface {
+	Dimensions(ctx context.Context) (int, error)
+}
+
+// VectorStorer embeds and stores content and searches it by similarity.
+type VectorStorer interface {
+	// Add embeds and stores the contents. The ID of each embedding is the
+	// hash of its content, so adding the same content twice stores and
+	// embeds it once.
+	Add(ctx context.Context, contents []string) ([]*Embedding, error)
+
+	Search(ctx context.Context, params *SearchParams) ([]*SearchResult, error)
+}
+
+// Store is a VectorStorer that also stores embeddings whose vectors were
+// computed ahead of time, i.e., by a batching ingestion pipeline.
+type Store interface {
+	VectorStorer
+
+	// Upsert inserts the embeddings or replaces the ones with the same ID.
+	// Every embedding must have an ID and a vector.
+	Upsert(ctx context.Context, embeddings []*Embedding) error
+
+	// Get returns the embeddings with the given IDs. IDs that are not stored
+	// are skipped.
+	Get(ctx context.Context, ids []string) ([]*Embedding, error)
+}
+
+// TimestampFormat is a fixed width RFC 3339 layout. Timestamps stored in
+// metadata as UTC strings of this format order correctly in every store.
+const TimestampFormat string = "2006-01-02T15:04:05.000000000Z07:00"
+
+// FormatTimestamp formats t in UTC with TimestampFormat.
+func FormatTimestamp(t time.Time) string {
+	return t.UTC().Format(TimestampFormat)
+}
+
+// ContentHash returns the hex encoded SHA-256 hash of content.
+func ContentHash(content string) string {
+	hash := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(hash[:])
+}
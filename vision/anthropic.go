@@ -0,0 +1,251 @@
+package vision
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/go-logr/logr"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/examples/modelinfo"
+)
+
+// AnthropicProviderOpts configures a new AnthropicProvider.
+type AnthropicProviderOpts struct {
+	// APIKey defaults to the ANTHROPIC_API_KEY environment variable
+	APIKey string
+
+	// BaseURL targets Anthropic compatible APIs
+	BaseURL string
+
+	// Limits images are fitted to. Defaults to
+	// LimitsFor(modelinfo.Anthropic).
+	Limits *Limits
+
+	// MaxTokens is used for requests without MaxTokens, which Anthropic
+	// requires. Defaults to the model's maximum output from modelinfo, or
+	// 4096.
+	MaxTokens int
+
+	Logger *logr.Logger
+}
+
+// AnthropicProvider is a core.Provider for Claude models that sends the
+// images of user messages.
+type AnthropicProvider struct {
+	client    *anthropic.Client
+	model     *core.Model
+	limits    *Limits
+	maxTokens int
+
+	logger *logr.Logger
+}
+
+// NewAnthropicProvider creates a new AnthropicProvider.
+func NewAnthropicProvider(opts *AnthropicProviderOpts) *AnthropicProvider {
+	if opts.Limits == nil {
+		opts.Limits = LimitsFor(modelinfo.Anthropic)
+	}
+
+	clientOpts := []option.RequestOption{}
+	if opts.APIKey != "" {
+		clientOpts = append(clientOpts, option.WithAPIKey(opts.APIKey))
+	}
+
+	if opts.BaseURL != "" {
+		clientOpts = append(clientOpts, option.WithBaseURL(opts.BaseURL))
+	}
+
+	return &AnthropicProvider{
+		client:    anthropic.NewClient(clientOpts...),
+		limits:    opts.Limits,
+		maxTokens: opts.MaxTokens,
+		logger:    opts.Logger,
+	}
+}
+
+func (p *AnthropicProvider) GetCapabilities(ctx context.Context) (*core.Capabilities, error) {
+	return capabilities(p.model), nil
+}
+
+func (p *AnthropicProvider) UseModel(ctx context.Context, model *core.Model) error {
+	p.model = model
+	return nil
+}
+
+// Generate sends a messages request with the messages' images.
+func (p *AnthropicProvider) Generate(ctx context.Context, opts *core.GenerateOptions) (*core.Message, error) {
+	if p.model == nil {
+		return nil, errors.New("no model set: call UseModel first")
+	}
+
+	opts, err := fitRequest(opts, p.limits)
+	if err != nil {
+		return nil, err
+	}
+
+	system := []anthropic.TextBlockParam{}
+	messages := []anthropic.MessageParam{}
+
+	for _, m := range opts.Messages {
+		if m.Role == core.SystemMessageRole {
+			system = append(system, anthropic.NewTextBlock(m.Content))
+			continue
+		}
+
+		role, blocks := anthropicBlocks(m)
+		if len(blocks) == 0 {
+			continue
+		}
+
+		// roles must alternate: merge consecutive messages of a role, i.e.,
+		// tool results followed by user input
+		if last := len(messages) - 1; last >= 0 && messages[last].Role.Value == role {
+			messages[last].Content = anthropic.F(append(messages[last].Content.Value, blocks...))
+			continue
+		}
+
+		messages = append(messages, anthropic.MessageParam{
+			Role:    anthropic.F(role),
+			Content: anthropic.F(blocks),
+		})
+	}
+
+	params := anthropic.MessageNewParams{
+		Model:     anthropic.F(p.model.ID),
+		MaxTokens: anthropic.F(int64(p.requestMaxTokens(opts))),
+		Messages:  anthropic.F(messages),
+	}
+
+	if len(system) > 0 {
+		params.System = anthropic.F(system)
+	}
+
+	if len(opts.Tools) > 0 {
+		tools := []anthropic.ToolUnionUnionParam{}
+		for _, t := range opts.Tools {
+			schema := map[string]any{"type": "object", "properties": map[string]any{}}
+			if len(t.JSONSchema) > 0 {
+				if err := json.Unmarshal(t.JSONSchema, &schema); err != nil {
+					return nil, fmt.Errorf("error unmarshaling schema of tool %s: %w", t.Name, err)
+				}
+			}
+
+			tools = append(tools, anthropic.ToolParam{
+				Name:        anthropic.F(t.Name),
+				Description: anthropic.F(t.Description),
+				InputSchema: anthropic.F[any](schema),
+			})
+		}
+
+		params.Tools = anthropic.F(tools)
+	}
+
+	if opts.Temperature > 0 {
+		params.Temperature = anthropic.F(opts.Temperature)
+	}
+
+	if opts.TopP > 0 {
+		params.TopP = anthropic.F(opts.TopP)
+	}
+
+	if len(opts.StopSequences) > 0 {
+		params.StopSequences = anthropic.F(opts.StopSequences)
+	}
+
+	if p.logger != nil {
+		p.logger.V(1).Info("creating anthropic message", "model", p.model.ID, "messages", len(messages))
+	}
+
+	resp, err := p.client.Messages.New(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("error creating message: %w", err)
+	}
+
+	msg := &core.Message{
+		Role: core.AssistantMessageRole,
+	}
+
+	for _, block := range resp.Content {
+		switch block.Type {
+		case anthropic.ContentBlockTypeText:
+			msg.Content += block.Text
+		case anthropic.ContentBlockTypeToolUse:
+			msg.ToolCalls = append(msg.ToolCalls, &core.ToolCall{
+				ID:        block.ID,
+				Name:      block.Name,
+				Arguments: block.Input,
+			})
+		}
+	}
+
+	return withUsage(msg, resp.Usage.InputTokens, resp.Usage.OutputTokens), nil
+}
+
+// GenerateStream generates the response with Generate and streams it as a
+// single message.
+func (p *AnthropicProvider) GenerateStream(ctx context.Context, opts *core.GenerateOptions) (<-chan *core.Message, <-chan string, <-chan error) {
+	return streamOf(ctx, opts, p.Generate)
+}
+
+func (p *AnthropicProvider) requestMaxTokens(opts *core.GenerateOptions) int {
+	if opts.MaxTokens > 0 {
+		return opts.MaxTokens
+	}
+
+	if p.maxTokens > 0 {
+		return p.maxTokens
+	}
+
+	if info, ok := modelinfo.LookupModel(p.model); ok && info.MaxOutputTokens > 0 {
+		return info.MaxOutputTokens
+	}
+
+	return 4096
+}
+
+// anthropicBlocks converts a non system message to its role and content
+// blocks. Images come before text, as Anthropic recommends, and tool
+// results are sent as user content.
+func anthropicBlocks(m *core.Message) (anthropic.MessageParamRole, []anthropic.ContentBlockParamUnion) {
+	blocks := []anthropic.ContentBlockParamUnion{}
+
+	switch m.Role {
+	case core.AssistantMessageRole:
+		if m.Content != "" {
+			blocks = append(blocks, anthropic.NewTextBlock(m.Content))
+		}
+
+		for _, tc := range m.ToolCalls {
+			input := tc.Arguments
+			if len(input) == 0 {
+				input = json.RawMessage("{}")
+			}
+
+			blocks = append(blocks, anthropic.NewToolUseBlockParam(tc.ID, tc.Name, input))
+		}
+
+		return anthropic.MessageParamRoleAssistant, blocks
+
+	case core.ToolMessageRole:
+		for _, tr := range m.ToolResult {
+			blocks = append(blocks, anthropic.NewToolResultBlock(tr.ToolCallID, modelinfo.ToolResultText(tr), tr.Error != ""))
+		}
+
+		return anthropic.MessageParamRoleUser, blocks
+	}
+
+	for _, img := range m.Images {
+		blocks = append(blocks, anthropic.NewImageBlockBase64(img.MimeType, img.Base64Encoding))
+	}
+
+	if m.Content != "" {
+		blocks = append(blocks, anthropic.NewTextBlock(m.Content))
+	}
+
+	return anthropic.MessageParamRoleUser, blocks
+}
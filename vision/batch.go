@@ -0,0 +1,400 @@
+package vision
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/examples/internal/llmjson"
+	"github.com/agent-api/examples/usage"
+)
+
+// ImageExtensions are the lower case file extensions of the images Batch
+// analyzes.
+var ImageExtensions = []string{".jpg", ".jpeg", ".png", ".gif", ".webp", ".heic", ".heif"}
+
+// BatchOpts configures a new Batch.
+type BatchOpts struct {
+	// Provider analyzes the images. It should already have a vision model
+	// set via UseModel.
+	Provider core.Provider
+
+	// Loader loads the images, i.e., with the provider's Limits. Defaults to
+	// a Loader without limits.
+	Loader *Loader
+
+	// Prompt is sent with every image
+	Prompt string
+
+	// Schema is an optional JSON schema of the output. When set, the model
+	// is asked for a JSON object matching it, and responses that are not
+	// JSON objects with its required properties fail.
+	Schema json.RawMessage
+
+	// MaxTokens of each response. Zero uses the provider's default.
+	MaxTokens int
+
+	// Concurrency is the number of images analyzed at once. Defaults to 4.
+	Concurrency int
+
+	// OutDir is where results are written, one JSON file per image at its
+	// path relative to the analyzed directory plus ".json"
+	OutDir string
+
+	Logger *logr.Logger
+}
+
+// Batch analyzes every image in a directory with a vision model, writing a
+// JSON result per image. Images that already have a successful result are
+// skipped, so an interrupted batch resumes where it stopped and failed
+// images are retried.
+type Batch struct {
+	provider    core.Provider
+	loader      *Loader
+	prompt      string
+	schema      json.RawMessage
+	required    []string
+	maxTokens   int
+	concurrency int
+	outDir      string
+
+	logger *logr.Logger
+}
+
+// Result is the analysis of one image.
+type Result struct {
+	// Image is the image's path relative to the analyzed directory, with
+	// forward slashes
+	Image string `json:"image"`
+
+	// Content is the model's response
+	Content string `json:"content,omitempty"`
+
+	// Output is the JSON object parsed from Content when there is a schema
+	Output json.RawMessage `json:"output,omitempty"`
+
+	// Error is why the analysis failed
+	Error string `json:"error,omitempty"`
+
+	PromptTokens     int     `json:"prompt_tokens,omitempty"`
+	CompletionTokens int     `json:"completion_tokens,omitempty"`
+	Seconds          float64 `json:"seconds"`
+
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// BatchStats counts the work done by a batch.
+type BatchStats struct {
+	Images int
+
+	// Skipped images already had a successful result
+	Skipped int
+
+	Succeeded int
+	Failed    int
+}
+
+// NewBatch creates a new Batch.
+func NewBatch(opts *BatchOpts) (*Batch, error) {
+	if opts.Provider == nil || opts.Prompt == "" || opts.OutDir == "" {
+		return nil, errors.New("batch requires a provider, a prompt and an output directory")
+	}
+
+	required := []string{}
+	if len(opts.Schema) > 0 {
+		schema := struct {
+			Required []string `json:"required"`
+		}{}
+
+		if err := json.Unmarshal(opts.Schema, &schema); err != nil {
+			return nil, fmt.Errorf("error unmarshaling output schema: %w", err)
+		}
+
+		required = schema.Required
+	}
+
+	if opts.Loader == nil {
+		opts.Loader = NewLoader(&LoaderOpts{})
+	}
+
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+
+	return &Batch{
+		provider:    opts.Provider,
+		loader:      opts.Loader,
+		prompt:      opts.Prompt,
+		schema:      opts.Schema,
+		required:    required,
+		maxTokens:   opts.MaxTokens,
+		concurrency: opts.Concurrency,
+		outDir:      opts.OutDir,
+		logger:      opts.Logger,
+	}, nil
+}
+
+// Run analyzes every image under dir that has no successful result yet.
+// Results are written as each image completes. When ctx is canceled, the
+// images in flight that fail are left without a result, and ctx's error is
+// returned with the stats so far.
+func (b *Batch) Run(ctx context.Context, dir string) (*BatchStats, error) {
+	stats := &BatchStats{}
+	pending := []string{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// results may be written inside the analyzed directory
+		if d.IsDir() {
+			if abs, _ := filepath.Abs(path); abs == b.absOutDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !IsImageFile(path) {
+			return nil
+		}
+
+		image, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		image = filepath.ToSlash(image)
+		stats.Images++
+
+		if b.done(image) {
+			stats.Skipped++
+			return nil
+		}
+
+		pending = append(pending, image)
+		return nil
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	if b.logger != nil {
+		b.logger.Info("analyzing images", "images", stats.Images, "skipped", stats.Skipped, "pending", len(pending))
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var writeErr error
+
+	images := make(chan string)
+
+	for range min(b.concurrency, len(pending)) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for image := range images {
+				result := b.Analyze(ctx, filepath.Join(dir, filepath.FromSlash(image)), image)
+
+				// an interrupted analysis is retried on the next run
+				if result.Error != "" && ctx.Err() != nil {
+					continue
+				}
+
+				err := b.write(result)
+
+				mu.Lock()
+				if err != nil {
+					writeErr = errors.Join(writeErr, err)
+				} else if result.Error != "" {
+					stats.Failed++
+				} else {
+					stats.Succeeded++
+				}
+				mu.Unlock()
+
+				if b.logger != nil {
+					if result.Error != "" {
+						b.logger.Info("failed analyzing image", "image", image, "error", result.Error)
+					} else {
+						b.logger.V(1).Info("analyzed image", "image", image, "seconds", result.Seconds)
+					}
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, image := range pending {
+		select {
+		case images <- image:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+
+	close(images)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return stats, err
+	}
+
+	return stats, writeErr
+}
+
+// Analyze sends one image with the prompt to the provider. Failures are
+// reported in the result's Error rather than returned.
+func (b *Batch) Analyze(ctx context.Context, path, image string) *Result {
+	start := time.Now()
+	result := &Result{
+		Image: image,
+	}
+
+	defer func() {
+		result.Seconds = time.Since(start).Seconds()
+		result.CompletedAt = time.Now()
+	}()
+
+	img, err := b.loader.File(path)
+	if err != nil {
+		result.Error = fmt.Sprintf("error loading image: %s", err)
+		return result
+	}
+
+	prompt := b.prompt
+	if len(b.schema) > 0 {
+		prompt = fmt.Sprintf("%s\n\nRespond ONLY with a JSON object matching this JSON schema:\n%s", prompt, b.schema)
+	}
+
+	opts := &core.GenerateOptions{
+		Messages: []*core.Message{
+			{
+				Role:    core.UserMessageRole,
+				Content: prompt,
+				Images:  []*core.Image{img},
+			},
+		},
+		MaxTokens: b.maxTokens,
+	}
+
+	msg, err := b.provider.Generate(ctx, opts)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	u := usage.FromResponse(opts, msg)
+	result.Content = msg.Content
+	result.PromptTokens = u.PromptTokens
+	result.CompletionTokens = u.CompletionTokens
+
+	if len(b.schema) > 0 {
+		output, err := b.parseOutput(msg.Content)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+
+		result.Output = output
+	}
+
+	return result
+}
+
+// ResultPath returns the path of an image's result file.
+func (b *Batch) ResultPath(image string) string {
+	return filepath.Join(b.outDir, filepath.FromSlash(image)+".json")
+}
+
+// ReadResult reads an image's result file.
+func (b *Batch) ReadResult(image string) (*Result, error) {
+	data, err := os.ReadFile(b.ResultPath(image))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, fmt.Errorf("error unmarshaling result of %s: %w", image, err)
+	}
+
+	return result, nil
+}
+
+// IsImageFile reports whether a path has one of the ImageExtensions.
+func IsImageFile(path string) bool {
+	return slices.Contains(ImageExtensions, strings.ToLower(filepath.Ext(path)))
+}
+
+// done reports whether an image already has a successful result.
+func (b *Batch) done(image string) bool {
+	result, err := b.ReadResult(image)
+	return err == nil && result.Error == ""
+}
+
+// write writes a result to a temporary file then renames it, so an
+// interrupted write never leaves a partial result behind.
+func (b *Batch) write(result *Result) error {
+	path := b.ResultPath(result.Image)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// parseOutput returns the JSON object in a response, checking it has the
+// schema's required properties.
+func (b *Batch) parseOutput(content string) (json.RawMessage, error) {
+	data, ok := llmjson.Extract(content, '{', '}')
+	if !ok {
+		return nil, errors.New("output is not a JSON object")
+	}
+
+	output := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(data), &output); err != nil {
+		return nil, fmt.Errorf("error unmarshaling output: %w", err)
+	}
+
+	for _, property := range b.required {
+		if _, ok := output[property]; !ok {
+			return nil, fmt.Errorf("output is missing required property %s", property)
+		}
+	}
+
+	return json.RawMessage(data), nil
+}
+
+func (b *Batch) absOutDir() string {
+	abs, err := filepath.Abs(b.outDir)
+	if err != nil {
+		return b.outDir
+	}
+
+	return abs
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/agent-api/anthropic/models"
+	"github.com/agent-api/core"
+	"github.com/agent-api/examples/modelinfo"
+	"github.com/agent-api/examples/vision"
+	googlegenaimodels "github.com/agent-api/googlegenai/models"
+	"github.com/agent-api/ollama"
+	ollamamodels "github.com/agent-api/ollama/models"
+	openaimodels "github.com/agent-api/openai/models"
+)
+
+func main() {
+	dir := flag.String("dir", "./images", "directory of images to analyze")
+	out := flag.String("out", "./results", "directory to write one JSON result per image to")
+	prompt := flag.String("prompt", "Describe this image in one short paragraph.", "prompt sent with every image")
+	schemaPath := flag.String("schema", "", "optional JSON schema file the output of each image must match")
+	providerName := flag.String("provider", "ollama", "vision provider: ollama, openai, anthropic or gemini")
+	concurrency := flag.Int("concurrency", 4, "images analyzed at once")
+	maxTokens := flag.Int("max-tokens", 0, "maximum tokens per response, 0 for the provider's default")
+	flag.Parse()
+
+	// Interrupting stops the batch: images in flight get no result and are
+	// analyzed again by the next run, completed ones are skipped
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Create a zap logger
+	config := zap.NewDevelopmentConfig()
+	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	zLogger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a logr.Logger using zapr adapter
+	logger := zapr.NewLogger(zLogger)
+
+	provider, limits, err := newProvider(ctx, *providerName, &logger)
+	if err != nil {
+		panic(err)
+	}
+
+	var schema []byte
+	if *schemaPath != "" {
+		schema, err = os.ReadFile(*schemaPath)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	batch, err := vision.NewBatch(&vision.BatchOpts{
+		Provider: provider,
+		Loader: vision.NewLoader(&vision.LoaderOpts{
+			Limits: limits,
+			Logger: &logger,
+		}),
+		Prompt:      *prompt,
+		Schema:      schema,
+		MaxTokens:   *maxTokens,
+		Concurrency: *concurrency,
+		OutDir:      *out,
+		Logger:      &logger,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	stats, err := batch.Run(ctx, *dir)
+
+	fmt.Printf("images: %d, skipped: %d, succeeded: %d, failed: %d\n",
+		stats.Images, stats.Skipped, stats.Succeeded, stats.Failed)
+
+	if err != nil {
+		logger.Error(err, "batch did not complete, run again to resume")
+		os.Exit(1)
+	}
+
+	if stats.Failed > 0 {
+		fmt.Println("run again to retry the failed images")
+	}
+}
+
+// newProvider returns a vision provider and the limits its images are fitted
+// to.
+func newProvider(ctx context.Context, name string, logger *logr.Logger) (core.Provider, *vision.Limits, error) {
+	switch name {
+	case "openai":
+		provider := vision.NewOpenAIProvider(&vision.OpenAIProviderOpts{
+			Logger: logger,
+		})
+		provider.UseModel(ctx, openaimodels.GPT4_O_MINI)
+
+		return provider, vision.LimitsFor(modelinfo.OpenAI), nil
+	case "anthropic":
+		provider := vision.NewAnthropicProvider(&vision.AnthropicProviderOpts{
+			Logger: logger,
+		})
+		provider.UseModel(ctx, models.CLAUDE_3_7_SONNET)
+
+		return provider, vision.LimitsFor(modelinfo.Anthropic), nil
+	case "gemini":
+		provider, err := vision.NewGeminiProvider(&vision.GeminiProviderOpts{
+			Logger: logger,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		provider.UseModel(ctx, googlegenaimodels.GEMINI_1_5_FLASH)
+
+		return provider, vision.LimitsFor(modelinfo.Google), nil
+	case "ollama":
+		provider := ollama.NewProvider(&ollama.ProviderOpts{
+			Logger:  logger,
+			BaseURL: "http://localhost",
+			Port:    11434,
+		})
+		provider.UseModel(ctx, ollamamodels.GEMMA3_LATEST)
+
+		return provider, vision.LimitsFor(modelinfo.Ollama), nil
+	}
+
+	return nil, nil, fmt.Errorf("unknown provider %s", name)
+}
@@ -0,0 +1,258 @@
+package vision
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"slices"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/examples/modelinfo"
+)
+
+// minDimension is the shortest side below which images are not shrunk
+// further to meet a size limit.
+const minDimension = 64
+
+// ErrUnsupportedFormat is returned for images a provider does not accept and
+// that cannot be converted: only JPEG, PNG and GIF images are decoded.
+var ErrUnsupportedFormat = errors.New("unsupported image format")
+
+// Limits are a provider's constraints on images. Zero values are unlimited.
+type Limits struct {
+	// MaxBytes is the maximum decoded size of an image
+	MaxBytes int
+
+	// MaxDimension is the maximum length of an image's longest side. Larger
+	// images are downscaled by providers anyway, so sending them only costs
+	// bandwidth.
+	MaxDimension int
+
+	// MaxImages is the maximum number of images in a request
+	MaxImages int
+
+	// Formats are the accepted MIME types. Empty accepts any.
+	Formats []string
+
+	// Quality of recompressed JPEGs, lowered as needed to meet MaxBytes.
+	// Defaults to 85.
+	Quality int
+}
+
+// LimitsFor returns the image limits of a provider, by its modelinfo
+// provider name, or nil for unknown providers.
+func LimitsFor(provider string) *Limits {
+	switch provider {
+	case modelinfo.OpenAI:
+		return &Limits{
+			MaxBytes:     20 << 20,
+			MaxDimension: 2048,
+			MaxImages:    500,
+			Formats:      []string{"image/jpeg", "image/png", "image/gif", "image/webp"},
+		}
+	case modelinfo.Anthropic:
+		return &Limits{
+			MaxBytes:     5 << 20,
+			MaxDimension: 1568,
+			MaxImages:    100,
+			Formats:      []string{"image/jpeg", "image/png", "image/gif", "image/webp"},
+		}
+	case modelinfo.Google:
+		// inline images count towards Gemini's 20MB request limit
+		return &Limits{
+			MaxBytes:     7 << 20,
+			MaxDimension: 3072,
+			MaxImages:    3600,
+			Formats:      []string{"image/jpeg", "image/png", "image/webp", "image/heic", "image/heif"},
+		}
+	case modelinfo.Ollama:
+		return &Limits{
+			MaxBytes:     20 << 20,
+			MaxDimension: 1536,
+			Formats:      []string{"image/jpeg", "image/png"},
+		}
+	}
+
+	return nil
+}
+
+// Fit returns the image unchanged if it meets the limits. Otherwise it is
+// downscaled to MaxDimension and recompressed, as PNG if it has transparency
+// and fits, else as JPEG of decreasing quality and size until it meets
+// MaxBytes.
+func (l *Limits) Fit(img *core.Image) (*core.Image, error) {
+	data, err := base64.StdEncoding.DecodeString(img.Base64Encoding)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding base64 image: %w", err)
+	}
+
+	accepted := len(l.Formats) == 0 || slices.Contains(l.Formats, img.MimeType)
+	fitsBytes := l.MaxBytes <= 0 || len(data) <= l.MaxBytes
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		// formats that cannot be decoded can still be sent as is
+		if accepted && fitsBytes {
+			return img, nil
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, img.MimeType)
+	}
+
+	longest := max(config.Width, config.Height)
+	fitsDimension := l.MaxDimension <= 0 || longest <= l.MaxDimension
+
+	if accepted && fitsBytes && fitsDimension {
+		return img, nil
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding image: %w", err)
+	}
+
+	scale := 1.0
+	if !fitsDimension {
+		scale = float64(l.MaxDimension) / float64(longest)
+	}
+
+	for {
+		width := max(int(float64(config.Width)*scale), 1)
+		height := max(int(float64(config.Height)*scale), 1)
+
+		encoded, mimeType, err := l.encode(Resize(decoded, width, height))
+		if err != nil {
+			return nil, err
+		}
+
+		if l.MaxBytes <= 0 || len(encoded) <= l.MaxBytes {
+			return &core.Image{
+				MimeType:       mimeType,
+				Base64Encoding: base64.StdEncoding.EncodeToString(encoded),
+			}, nil
+		}
+
+		if min(width, height) <= minDimension {
+			return nil, fmt.Errorf("%w: cannot compress below %d bytes", ErrTooLarge, l.MaxBytes)
+		}
+
+		scale *= 0.75
+	}
+}
+
+// FitAll fits every image, in order. It fails if there are more images than
+// MaxImages.
+func (l *Limits) FitAll(images []*core.Image) ([]*core.Image, error) {
+	if l.MaxImages > 0 && len(images) > l.MaxImages {
+		return nil, fmt.Errorf("%d images exceed the limit of %d", len(images), l.MaxImages)
+	}
+
+	fitted := make([]*core.Image, len(images))
+	for i, img := range images {
+		f, err := l.Fit(img)
+		if err != nil {
+			return nil, err
+		}
+
+		fitted[i] = f
+	}
+
+	return fitted, nil
+}
+
+// encode encodes an image as PNG if it has transparency and PNG is accepted
+// and fits, else as JPEG of the highest quality that fits.
+func (l *Limits) encode(img *image.RGBA) ([]byte, string, error) {
+	var buf bytes.Buffer
+
+	acceptsPNG := len(l.Formats) == 0 || slices.Contains(l.Formats, "image/png")
+	acceptsJPEG := len(l.Formats) == 0 || slices.Contains(l.Formats, "image/jpeg")
+
+	if acceptsPNG && (!img.Opaque() || !acceptsJPEG) {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", err
+		}
+
+		if l.MaxBytes <= 0 || buf.Len() <= l.MaxBytes || !acceptsJPEG {
+			return buf.Bytes(), "image/png", nil
+		}
+	}
+
+	if !acceptsJPEG {
+		return nil, "", fmt.Errorf("%w: neither JPEG nor PNG are accepted", ErrUnsupportedFormat)
+	}
+
+	quality := l.Quality
+	if quality <= 0 {
+		quality = 85
+	}
+
+	for {
+		buf.Reset()
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, "", err
+		}
+
+		if l.MaxBytes <= 0 || buf.Len() <= l.MaxBytes || quality <= 40 {
+			return buf.Bytes(), "image/jpeg", nil
+		}
+
+		quality -= 15
+	}
+}
+
+// Resize scales an image to width x height, averaging the source pixels
+// each destination pixel covers. It is meant for downscaling.
+func Resize(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+
+	rgba, ok := src.(*image.RGBA)
+	if !ok || bounds.Min != (image.Point{}) {
+		rgba = image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	}
+
+	srcWidth, srcHeight := rgba.Bounds().Dx(), rgba.Bounds().Dy()
+	if srcWidth == width && srcHeight == height {
+		return rgba
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := y * srcHeight / height
+		y1 := max((y+1)*srcHeight/height, y0+1)
+
+		for x := 0; x < width; x++ {
+			x0 := x * srcWidth / width
+			x1 := max((x+1)*srcWidth/width, x0+1)
+
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint32(p[0])
+					g += uint32(p[1])
+					b += uint32(p[2])
+					a += uint32(p[3])
+					n++
+				}
+			}
+
+			d := dst.Pix[y*dst.Stride+x*4:]
+			d[0] = uint8(r / n)
+			d[1] = uint8(g / n)
+			d[2] = uint8(b / n)
+			d[3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,182 @@
+package vision
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/agent-api/core"
+)
+
+// testImage returns a PNG of random pixels, which compresses poorly, with
+// transparent pixels unless opaque.
+func testImage(t *testing.T, width, height int, opaque bool) *core.Image {
+	t.Helper()
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			alpha := uint8(255)
+			if !opaque && x < width/2 {
+				alpha = 0
+			}
+
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(rng.IntN(256)), G: uint8(rng.IntN(256)), B: uint8(rng.IntN(256)), A: alpha})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	return &core.Image{
+		MimeType:       "image/png",
+		Base64Encoding: base64.StdEncoding.EncodeToString(buf.Bytes()),
+	}
+}
+
+func TestLimitsFit(t *testing.T) {
+	webp := &core.Image{
+		MimeType:       "image/webp",
+		Base64Encoding: base64.StdEncoding.EncodeToString([]byte("RIFF\x00\x00\x00\x00WEBPVP8 ")),
+	}
+
+	tests := []struct {
+		name      string
+		limits    *Limits
+		image     *core.Image
+		unchanged bool
+		mimeType  string
+		width     int
+		height    int
+		err       error
+	}{
+		{
+			name:      "within limits",
+			limits:    &Limits{MaxBytes: 1 << 20, MaxDimension: 200, Formats: []string{"image/png"}},
+			image:     testImage(t, 100, 50, true),
+			unchanged: true,
+		},
+		{
+			name:     "downscaled to the maximum dimension",
+			limits:   &Limits{MaxDimension: 100},
+			image:    testImage(t, 400, 200, true),
+			mimeType: "image/jpeg",
+			width:    100,
+			height:   50,
+		},
+		{
+			name:     "transparency is kept as PNG",
+			limits:   &Limits{MaxDimension: 100},
+			image:    testImage(t, 200, 400, false),
+			mimeType: "image/png",
+			width:    50,
+			height:   100,
+		},
+		{
+			name:     "converted to an accepted format",
+			limits:   &Limits{Formats: []string{"image/jpeg"}},
+			image:    testImage(t, 60, 40, false),
+			mimeType: "image/jpeg",
+			width:    60,
+			height:   40,
+		},
+		{
+			name:     "PNG only",
+			limits:   &Limits{MaxDimension: 50, Formats: []string{"image/png"}},
+			image:    testImage(t, 100, 100, true),
+			mimeType: "image/png",
+			width:    50,
+			height:   50,
+		},
+		{
+			name:     "compressed to the maximum bytes",
+			limits:   &Limits{MaxBytes: 20000},
+			image:    testImage(t, 512, 512, true),
+			mimeType: "image/jpeg",
+		},
+		{
+			name:   "cannot be compressed enough",
+			limits: &Limits{MaxBytes: 100},
+			image:  testImage(t, 512, 512, true),
+			err:    ErrTooLarge,
+		},
+		{
+			name:      "undecodable but accepted",
+			limits:    &Limits{MaxBytes: 1 << 20},
+			image:     webp,
+			unchanged: true,
+		},
+		{
+			name:   "undecodable and not accepted",
+			limits: &Limits{Formats: []string{"image/jpeg"}},
+			image:  webp,
+			err:    ErrUnsupportedFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fitted, err := tt.limits.Fit(tt.image)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("got error %v, want %v", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.unchanged {
+				if fitted != tt.image {
+					t.Error("image was changed")
+				}
+				return
+			}
+
+			if fitted.MimeType != tt.mimeType {
+				t.Errorf("got %s, want %s", fitted.MimeType, tt.mimeType)
+			}
+
+			data, err := base64.StdEncoding.DecodeString(fitted.Base64Encoding)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.limits.MaxBytes > 0 && len(data) > tt.limits.MaxBytes {
+				t.Errorf("got %d bytes, want at most %d", len(data), tt.limits.MaxBytes)
+			}
+
+			config, _, err := image.DecodeConfig(bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.width > 0 && (config.Width != tt.width || config.Height != tt.height) {
+				t.Errorf("got %dx%d, want %dx%d", config.Width, config.Height, tt.width, tt.height)
+			}
+		})
+	}
+}
+
+func TestLimitsFitAll(t *testing.T) {
+	limits := &Limits{MaxImages: 2}
+	img := testImage(t, 10, 10, true)
+
+	if _, err := limits.FitAll([]*core.Image{img, img}); err != nil {
+		t.Errorf("fitting 2 images: %s", err)
+	}
+
+	if _, err := limits.FitAll([]*core.Image{img, img, img}); err == nil {
+		t.Error("fitting 3 images: expected an error")
+	}
+}
@@ -0,0 +1,318 @@
+package vision
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/examples/modelinfo"
+)
+
+// GeminiProviderOpts configures a new GeminiProvider.
+type GeminiProviderOpts struct {
+	// APIKey defaults to the GEMINI_API_KEY environment variable
+	APIKey string
+
+	// BaseURL defaults to https://generativelanguage.googleapis.com/v1beta
+	BaseURL string
+
+	// Limits images are fitted to. Defaults to LimitsFor(modelinfo.Google).
+	Limits *Limits
+
+	// HTTPClient defaults to http.DefaultClient
+	HTTPClient *http.Client
+
+	Logger *logr.Logger
+}
+
+// GeminiProvider is a core.Provider for Gemini models, using the Gemini
+// generateContent REST API, that sends the images of user messages.
+type GeminiProvider struct {
+	apiKey  string
+	baseURL string
+	model   *core.Model
+	limits  *Limits
+	client  *http.Client
+
+	logger *logr.Logger
+}
+
+// NewGeminiProvider creates a new GeminiProvider.
+func NewGeminiProvider(opts *GeminiProviderOpts) (*GeminiProvider, error) {
+	if opts.APIKey == "" {
+		opts.APIKey = os.Getenv("GEMINI_API_KEY")
+	}
+
+	if opts.APIKey == "" {
+		return nil, errors.New("gemini provider requires an API key")
+	}
+
+	if opts.BaseURL == "" {
+		opts.BaseURL = "https://generativelanguage.googleapis.com/v1beta"
+	}
+
+	if opts.Limits == nil {
+		opts.Limits = LimitsFor(modelinfo.Google)
+	}
+
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+
+	return &GeminiProvider{
+		apiKey:  opts.APIKey,
+		baseURL: strings.TrimSuffix(opts.BaseURL, "/"),
+		limits:  opts.Limits,
+		client:  opts.HTTPClient,
+		logger:  opts.Logger,
+	}, nil
+}
+
+func (p *GeminiProvider) GetCapabilities(ctx context.Context) (*core.Capabilities, error) {
+	return capabilities(p.model), nil
+}
+
+func (p *GeminiProvider) UseModel(ctx context.Context, model *core.Model) error {
+	p.model = model
+	return nil
+}
+
+type geminiPart struct {
+	Text             string                  `json:"text,omitempty"`
+	InlineData       *geminiInlineData       `json:"inlineData,omitempty"`
+	FunctionCall     *geminiFunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *geminiFunctionResponse `json:"functionResponse,omitempty"`
+}
+
+type geminiInlineData struct {
+	MimeType string `json:"mimeType"`
+	Data     string `json:"data"`
+}
+
+type geminiFunctionCall struct {
+	Name string          `json:"name"`
+	Args json.RawMessage `json:"args,omitempty"`
+}
+
+type geminiFunctionResponse struct {
+	Name     string         `json:"name"`
+	Response map[string]any `json:"response"`
+}
+
+type geminiContent struct {
+	Role  string        `json:"role,omitempty"`
+	Parts []*geminiPart `json:"parts"`
+}
+
+type geminiFunctionDeclaration struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+type geminiTool struct {
+	FunctionDeclarations []*geminiFunctionDeclaration `json:"functionDeclarations"`
+}
+
+type geminiGenerationConfig struct {
+	Temperature     float64  `json:"temperature,omitempty"`
+	TopP            float64  `json:"topP,omitempty"`
+	MaxOutputTokens int      `json:"maxOutputTokens,omitempty"`
+	StopSequences   []string `json:"stopSequences,omitempty"`
+}
+
+type geminiRequest struct {
+	SystemInstruction *geminiContent          `json:"systemInstruction,omitempty"`
+	Contents          []*geminiContent        `json:"contents"`
+	Tools             []*geminiTool           `json:"tools,omitempty"`
+	GenerationConfig  *geminiGenerationConfig `json:"generationConfig,omitempty"`
+}
+
+type geminiResponse struct {
+	Candidates []struct {
+		Content      geminiContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+
+	UsageMetadata struct {
+		PromptTokenCount     int64 `json:"promptTokenCount"`
+		CandidatesTokenCount int64 `json:"candidatesTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+// Generate sends a generateContent request with the messages' images.
+func (p *GeminiProvider) Generate(ctx context.Context, opts *core.GenerateOptions) (*core.Message, error) {
+	if p.model == nil {
+		return nil, errors.New("no model set: call UseModel first")
+	}
+
+	opts, err := fitRequest(opts, p.limits)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(geminiRequestOf(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/models/%s:generateContent", p.baseURL, p.model.ID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", p.apiKey)
+
+	if p.logger != nil {
+		p.logger.V(1).Info("generating gemini content", "model", p.model.ID, "messages", len(opts.Messages))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error generating content: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("error generating content: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	out := &geminiResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, fmt.Errorf("error decoding content: %w", err)
+	}
+
+	if len(out.Candidates) == 0 {
+		return nil, errors.New("generated content has no candidates")
+	}
+
+	msg := &core.Message{
+		Role: core.AssistantMessageRole,
+	}
+
+	// Gemini function calls have no IDs: they are identified by name and
+	// position, which geminiRequestOf maps back from the tool results
+	for i, part := range out.Candidates[0].Content.Parts {
+		switch {
+		case part.FunctionCall != nil:
+			msg.ToolCalls = append(msg.ToolCalls, &core.ToolCall{
+				ID:        fmt.Sprintf("%s-%d", part.FunctionCall.Name, i),
+				Name:      part.FunctionCall.Name,
+				Arguments: part.FunctionCall.Args,
+			})
+		default:
+			msg.Content += part.Text
+		}
+	}
+
+	return withUsage(msg, out.UsageMetadata.PromptTokenCount, out.UsageMetadata.CandidatesTokenCount), nil
+}
+
+// GenerateStream generates the response with Generate and streams it as a
+// single message.
+func (p *GeminiProvider) GenerateStream(ctx context.Context, opts *core.GenerateOptions) (<-chan *core.Message, <-chan string, <-chan error) {
+	return streamOf(ctx, opts, p.Generate)
+}
+
+func geminiRequestOf(opts *core.GenerateOptions) *geminiRequest {
+	req := &geminiRequest{
+		Contents: []*geminiContent{},
+	}
+
+	// function responses are matched to their calls by name
+	toolNames := map[string]string{}
+
+	for _, m := range opts.Messages {
+		parts := []*geminiPart{}
+		role := "user"
+
+		switch m.Role {
+		case core.SystemMessageRole:
+			if req.SystemInstruction == nil {
+				req.SystemInstruction = &geminiContent{Parts: []*geminiPart{}}
+			}
+
+			req.SystemInstruction.Parts = append(req.SystemInstruction.Parts, &geminiPart{Text: m.Content})
+			continue
+
+		case core.AssistantMessageRole:
+			role = "model"
+
+			if m.Content != "" {
+				parts = append(parts, &geminiPart{Text: m.Content})
+			}
+
+			for _, tc := range m.ToolCalls {
+				toolNames[tc.ID] = tc.Name
+				parts = append(parts, &geminiPart{FunctionCall: &geminiFunctionCall{Name: tc.Name, Args: tc.Arguments}})
+			}
+
+		case core.ToolMessageRole:
+			for _, tr := range m.ToolResult {
+				response := map[string]any{"content": modelinfo.ToolResultText(tr)}
+				if tr.Error != "" {
+					response = map[string]any{"error": tr.Error}
+				}
+
+				parts = append(parts, &geminiPart{FunctionResponse: &geminiFunctionResponse{Name: toolNames[tr.ToolCallID], Response: response}})
+			}
+
+		default:
+			for _, img := range m.Images {
+				parts = append(parts, &geminiPart{InlineData: &geminiInlineData{MimeType: img.MimeType, Data: img.Base64Encoding}})
+			}
+
+			if m.Content != "" {
+				parts = append(parts, &geminiPart{Text: m.Content})
+			}
+		}
+
+		if len(parts) == 0 {
+			continue
+		}
+
+		// merge consecutive contents of a role, i.e., function responses
+		// followed by user input
+		if last := len(req.Contents) - 1; last >= 0 && req.Contents[last].Role == role {
+			req.Contents[last].Parts = append(req.Contents[last].Parts, parts...)
+			continue
+		}
+
+		req.Contents = append(req.Contents, &geminiContent{Role: role, Parts: parts})
+	}
+
+	if len(opts.Tools) > 0 {
+		declarations := []*geminiFunctionDeclaration{}
+		for _, t := range opts.Tools {
+			declarations = append(declarations, &geminiFunctionDeclaration{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  t.JSONSchema,
+			})
+		}
+
+		req.Tools = []*geminiTool{{FunctionDeclarations: declarations}}
+	}
+
+	if opts.Temperature > 0 || opts.TopP > 0 || opts.MaxTokens > 0 || len(opts.StopSequences) > 0 {
+		req.GenerationConfig = &geminiGenerationConfig{
+			Temperature:     opts.Temperature,
+			TopP:            opts.TopP,
+			MaxOutputTokens: opts.MaxTokens,
+			StopSequences:   opts.StopSequences,
+		}
+	}
+
+	return req
+}
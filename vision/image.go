@@ -0,0 +1,245 @@
+// Package vision loads images for vision models from files, URLs, raw bytes
+// and base64, sniffing their MIME type and fitting them to each provider's
+// limits. It includes image capable providers for OpenAI, Anthropic and
+// Google Gemini, whose agent-api providers only send text, so the same
+// images and run options work against every vision model, including
+// Ollama's.
+package vision
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/agent-api/core"
+)
+
+// DefaultMaxBytes is the default size limit of loaded images.
+const DefaultMaxBytes = 20 << 20
+
+var (
+	// ErrNotImage is returned for data whose sniffed type is not an image.
+	ErrNotImage = errors.New("not an image")
+
+	// ErrTooLarge is returned for images larger than the loader's MaxBytes.
+	ErrTooLarge = errors.New("image too large")
+)
+
+// LoaderOpts configures a new Loader.
+type LoaderOpts struct {
+	// MaxBytes is the size limit of loaded images, checked while reading so
+	// larger files and downloads are never fully read. Defaults to
+	// DefaultMaxBytes.
+	MaxBytes int64
+
+	// Limits the loaded images are fitted to, i.e., LimitsFor(modelinfo.OpenAI).
+	// When nil, images are loaded as is.
+	Limits *Limits
+
+	// Timeout of image downloads. Defaults to 30 seconds.
+	Timeout time.Duration
+
+	// HTTPClient defaults to http.DefaultClient
+	HTTPClient *http.Client
+
+	Logger *logr.Logger
+}
+
+// Loader loads images from files, URLs, bytes and base64.
+type Loader struct {
+	maxBytes int64
+	limits   *Limits
+	timeout  time.Duration
+	client   *http.Client
+
+	logger *logr.Logger
+}
+
+// NewLoader creates a new Loader.
+func NewLoader(opts *LoaderOpts) *Loader {
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = DefaultMaxBytes
+	}
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
+
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+
+	return &Loader{
+		maxBytes: opts.MaxBytes,
+		limits:   opts.Limits,
+		timeout:  opts.Timeout,
+		client:   opts.HTTPClient,
+		logger:   opts.Logger,
+	}
+}
+
+// Load loads an image from a reference: an http or https URL, a base64 data
+// URI or a file path.
+func (l *Loader) Load(ctx context.Context, ref string) (*core.Image, error) {
+	switch {
+	case strings.HasPrefix(ref, "http://"), strings.HasPrefix(ref, "https://"):
+		return l.URL(ctx, ref)
+	case strings.HasPrefix(ref, "data:"):
+		return l.DataURI(ref)
+	default:
+		return l.File(ref)
+	}
+}
+
+// LoadAll loads every reference, in order.
+func (l *Loader) LoadAll(ctx context.Context, refs ...string) ([]*core.Image, error) {
+	images := make([]*core.Image, 0, len(refs))
+
+	for _, ref := range refs {
+		img, err := l.Load(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("error loading image %s: %w", ref, err)
+		}
+
+		images = append(images, img)
+	}
+
+	return images, nil
+}
+
+// File loads an image file.
+func (l *Loader) File(path string) (*core.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return l.Reader(f)
+}
+
+// URL downloads an image. Downloads are aborted once they exceed MaxBytes.
+func (l *Loader) URL(ctx context.Context, url string) (*core.Image, error) {
+	ctx, cancel := context.WithTimeout(ctx, l.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "image/*")
+
+	if l.logger != nil {
+		l.logger.V(1).Info("downloading image", "url", url)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error downloading image: %s", resp.Status)
+	}
+
+	if resp.ContentLength > l.maxBytes {
+		return nil, fmt.Errorf("%w: %d bytes, limit is %d", ErrTooLarge, resp.ContentLength, l.maxBytes)
+	}
+
+	return l.Reader(resp.Body)
+}
+
+// Reader loads an image from r, reading at most MaxBytes.
+func (l *Loader) Reader(r io.Reader) (*core.Image, error) {
+	data, err := io.ReadAll(io.LimitReader(r, l.maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > l.maxBytes {
+		return nil, fmt.Errorf("%w: over %d bytes", ErrTooLarge, l.maxBytes)
+	}
+
+	return l.Bytes(data)
+}
+
+// Bytes loads an image from raw bytes.
+func (l *Loader) Bytes(data []byte) (*core.Image, error) {
+	if int64(len(data)) > l.maxBytes {
+		return nil, fmt.Errorf("%w: %d bytes, limit is %d", ErrTooLarge, len(data), l.maxBytes)
+	}
+
+	mimeType, err := Sniff(data)
+	if err != nil {
+		return nil, err
+	}
+
+	img := &core.Image{
+		MimeType:       mimeType,
+		Base64Encoding: base64.StdEncoding.EncodeToString(data),
+	}
+
+	if l.limits == nil {
+		return img, nil
+	}
+
+	return l.limits.Fit(img)
+}
+
+// Base64 loads an image from its base64 encoding. Its MIME type is sniffed
+// rather than trusted.
+func (l *Loader) Base64(encoding string) (*core.Image, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoding))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding base64 image: %w", err)
+	}
+
+	return l.Bytes(data)
+}
+
+// DataURI loads an image from a base64 data URI, i.e.,
+// "data:image/png;base64,iVBOR...".
+func (l *Loader) DataURI(uri string) (*core.Image, error) {
+	header, encoding, ok := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+	if !ok || !strings.HasSuffix(header, ";base64") {
+		return nil, errors.New("image data URI must be base64 encoded")
+	}
+
+	return l.Base64(encoding)
+}
+
+// Sniff returns the MIME type of image data from its leading bytes, or
+// ErrNotImage.
+func Sniff(data []byte) (string, error) {
+	mimeType := http.DetectContentType(data)
+
+	// DetectContentType does not know HEIC/HEIF, which phones and Gemini use
+	if len(data) >= 12 && string(data[4:8]) == "ftyp" {
+		switch string(data[8:12]) {
+		case "heic", "heix", "heim", "heis":
+			mimeType = "image/heic"
+		case "mif1", "msf1", "heif":
+			mimeType = "image/heif"
+		}
+	}
+
+	if !strings.HasPrefix(mimeType, "image/") {
+		return "", fmt.Errorf("%w: detected %s", ErrNotImage, mimeType)
+	}
+
+	return mimeType, nil
+}
+
+// DataURI returns an image as a base64 data URI.
+func DataURI(img *core.Image) string {
+	return "data:" + img.MimeType + ";base64," + img.Base64Encoding
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/agent-api/anthropic/models"
+	"github.com/agent-api/core"
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/core/agent/bootstrap"
+	"github.com/agent-api/examples/modelinfo"
+	"github.com/agent-api/examples/vision"
+	googlegenaimodels "github.com/agent-api/googlegenai/models"
+	"github.com/agent-api/ollama"
+	ollamamodels "github.com/agent-api/ollama/models"
+	openaimodels "github.com/agent-api/openai/models"
+)
+
+func main() {
+	ctx := context.Background()
+
+	providerName := flag.String("provider", "ollama", "vision provider: ollama, openai, anthropic or gemini")
+	prompt := flag.String("prompt", "What is in these images? Compare them.", "prompt sent with the images")
+	flag.Parse()
+
+	// Images can be file paths, http(s) URLs or base64 data URIs. Note: paths
+	// are relative to where you "go run" this program.
+	refs := flag.Args()
+	if len(refs) == 0 {
+		refs = []string{
+			"./cute-dog.jpg",
+			"https://upload.wikimedia.org/wikipedia/commons/thumb/4/4d/Cat_November_2010-1a.jpg/640px-Cat_November_2010-1a.jpg",
+		}
+	}
+
+	// Create a zap logger
+	config := zap.NewDevelopmentConfig()
+	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	zLogger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	// Create a logr.Logger using zapr adapter
+	logger := zapr.NewLogger(zLogger)
+
+	provider, limits, err := newProvider(ctx, *providerName, &logger)
+	if err != nil {
+		panic(err)
+	}
+
+	// Images are sniffed, size limited while downloading and fitted to the
+	// provider's limits as they are loaded
+	loader := vision.NewLoader(&vision.LoaderOpts{
+		Limits: limits,
+		Logger: &logger,
+	})
+
+	images, err := loader.LoadAll(ctx, refs...)
+	if err != nil {
+		panic(err)
+	}
+
+	for i, img := range images {
+		fmt.Printf("image %d: %s, %d base64 bytes\n", i+1, img.MimeType, len(img.Base64Encoding))
+	}
+
+	// Create a new agent
+	myAgent, err := agent.NewAgent(
+		bootstrap.WithProvider(provider),
+		bootstrap.WithLogger(&logger),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	response, err := myAgent.Run(
+		ctx,
+		agent.WithInput(*prompt),
+		vision.WithImages(images...),
+	)
+	if err != nil {
+		logger.Error(err, "failed sending message to agent")
+		return
+	}
+
+	fmt.Println("Agent response:", response.Messages[len(response.Messages)-1].Content)
+}
+
+// newProvider returns a vision provider and the limits its images are fitted
+// to.
+func newProvider(ctx context.Context, name string, logger *logr.Logger) (core.Provider, *vision.Limits, error) {
+	switch name {
+	case "openai":
+		provider := vision.NewOpenAIProvider(&vision.OpenAIProviderOpts{
+			Logger: logger,
+		})
+		provider.UseModel(ctx, openaimodels.GPT4_O)
+
+		return provider, vision.LimitsFor(modelinfo.OpenAI), nil
+	case "anthropic":
+		provider := vision.NewAnthropicProvider(&vision.AnthropicProviderOpts{
+			Logger: logger,
+		})
+		provider.UseModel(ctx, models.CLAUDE_3_7_SONNET)
+
+		return provider, vision.LimitsFor(modelinfo.Anthropic), nil
+	case "gemini":
+		provider, err := vision.NewGeminiProvider(&vision.GeminiProviderOpts{
+			Logger: logger,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		provider.UseModel(ctx, googlegenaimodels.GEMINI_1_5_FLASH)
+
+		return provider, vision.LimitsFor(modelinfo.Google), nil
+	case "ollama":
+		limits := vision.LimitsFor(modelinfo.Ollama)
+
+		// the ollama provider already sends images: only fit them
+		provider, err := vision.NewProvider(&vision.ProviderOpts{
+			Provider: ollama.NewProvider(&ollama.ProviderOpts{
+				Logger:  logger,
+				BaseURL: "http://localhost",
+				Port:    11434,
+			}),
+			Limits: limits,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		provider.UseModel(ctx, ollamamodels.GEMMA3_LATEST)
+
+		return provider, limits, nil
+	}
+
+	return nil, nil, fmt.Errorf("unknown provider %s", name)
+}
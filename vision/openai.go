@@ -0,0 +1,246 @@
+package vision
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/examples/modelinfo"
+)
+
+// OpenAIProviderOpts configures a new OpenAIProvider.
+type OpenAIProviderOpts struct {
+	// APIKey defaults to the OPENAI_API_KEY environment variable
+	APIKey string
+
+	// BaseURL targets OpenAI compatible APIs
+	BaseURL string
+
+	// Limits images are fitted to. Defaults to LimitsFor(modelinfo.OpenAI).
+	Limits *Limits
+
+	// Detail is the image detail level: "low", "high" or "auto", the
+	// default
+	Detail string
+
+	Logger *logr.Logger
+}
+
+// OpenAIProvider is a core.Provider for OpenAI chat models that sends the
+// images of user messages.
+type OpenAIProvider struct {
+	client *openai.Client
+	model  *core.Model
+	limits *Limits
+	detail string
+
+	logger *logr.Logger
+}
+
+// NewOpenAIProvider creates a new OpenAIProvider.
+func NewOpenAIProvider(opts *OpenAIProviderOpts) *OpenAIProvider {
+	if opts.Limits == nil {
+		opts.Limits = LimitsFor(modelinfo.OpenAI)
+	}
+
+	if opts.Detail == "" {
+		opts.Detail = "auto"
+	}
+
+	clientOpts := []option.RequestOption{}
+	if opts.APIKey != "" {
+		clientOpts = append(clientOpts, option.WithAPIKey(opts.APIKey))
+	}
+
+	if opts.BaseURL != "" {
+		clientOpts = append(clientOpts, option.WithBaseURL(opts.BaseURL))
+	}
+
+	return &OpenAIProvider{
+		client: openai.NewClient(clientOpts...),
+		limits: opts.Limits,
+		detail: opts.Detail,
+		logger: opts.Logger,
+	}
+}
+
+func (p *OpenAIProvider) GetCapabilities(ctx context.Context) (*core.Capabilities, error) {
+	return capabilities(p.model), nil
+}
+
+func (p *OpenAIProvider) UseModel(ctx context.Context, model *core.Model) error {
+	p.model = model
+	return nil
+}
+
+// Generate sends a chat completion request with the messages' images.
+func (p *OpenAIProvider) Generate(ctx context.Context, opts *core.GenerateOptions) (*core.Message, error) {
+	if p.model == nil {
+		return nil, errors.New("no model set: call UseModel first")
+	}
+
+	opts, err := fitRequest(opts, p.limits)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []openai.ChatCompletionMessageParamUnion{}
+	for _, m := range opts.Messages {
+		messages = append(messages, p.convertMessage(m)...)
+	}
+
+	params := openai.ChatCompletionNewParams{
+		Messages: openai.F(messages),
+		Model:    openai.F(p.model.ID),
+	}
+
+	if len(opts.Tools) > 0 {
+		tools := []openai.ChatCompletionToolParam{}
+		for _, t := range opts.Tools {
+			tool, err := openAITool(t)
+			if err != nil {
+				return nil, err
+			}
+
+			tools = append(tools, tool)
+		}
+
+		params.Tools = openai.F(tools)
+	}
+
+	if opts.MaxTokens > 0 {
+		params.MaxCompletionTokens = openai.F(int64(opts.MaxTokens))
+	}
+
+	if opts.Temperature > 0 {
+		params.Temperature = openai.F(opts.Temperature)
+	}
+
+	if opts.TopP > 0 {
+		params.TopP = openai.F(opts.TopP)
+	}
+
+	if len(opts.StopSequences) > 0 {
+		params.Stop = openai.F[openai.ChatCompletionNewParamsStopUnion](openai.ChatCompletionNewParamsStopArray(opts.StopSequences))
+	}
+
+	if p.logger != nil {
+		p.logger.V(1).Info("creating openai chat completion", "model", p.model.ID, "messages", len(messages))
+	}
+
+	resp, err := p.client.Chat.Completions.New(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("error creating chat completion: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return nil, errors.New("chat completion has no choices")
+	}
+
+	choice := resp.Choices[0].Message
+	msg := &core.Message{
+		Role:    core.AssistantMessageRole,
+		Content: choice.Content,
+	}
+
+	for _, tc := range choice.ToolCalls {
+		msg.ToolCalls = append(msg.ToolCalls, &core.ToolCall{
+			ID:        tc.ID,
+			Name:      tc.Function.Name,
+			Arguments: json.RawMessage(tc.Function.Arguments),
+		})
+	}
+
+	return withUsage(msg, resp.Usage.PromptTokens, resp.Usage.CompletionTokens), nil
+}
+
+// GenerateStream generates the response with Generate and streams it as a
+// single message.
+func (p *OpenAIProvider) GenerateStream(ctx context.Context, opts *core.GenerateOptions) (<-chan *core.Message, <-chan string, <-chan error) {
+	return streamOf(ctx, opts, p.Generate)
+}
+
+// convertMessage converts a message to OpenAI messages: tool messages
+// become one message per result.
+func (p *OpenAIProvider) convertMessage(m *core.Message) []openai.ChatCompletionMessageParamUnion {
+	switch m.Role {
+	case core.SystemMessageRole:
+		return []openai.ChatCompletionMessageParamUnion{openai.SystemMessage(m.Content)}
+
+	case core.AssistantMessageRole:
+		message := openai.ChatCompletionAssistantMessageParam{
+			Role: openai.F(openai.ChatCompletionAssistantMessageParamRoleAssistant),
+		}
+
+		if m.Content != "" {
+			message.Content = openai.F([]openai.ChatCompletionAssistantMessageParamContentUnion{openai.TextPart(m.Content)})
+		}
+
+		if len(m.ToolCalls) > 0 {
+			toolCalls := []openai.ChatCompletionMessageToolCallParam{}
+			for _, tc := range m.ToolCalls {
+				toolCalls = append(toolCalls, openai.ChatCompletionMessageToolCallParam{
+					ID:   openai.F(tc.ID),
+					Type: openai.F(openai.ChatCompletionMessageToolCallTypeFunction),
+					Function: openai.F(openai.ChatCompletionMessageToolCallFunctionParam{
+						Name:      openai.F(tc.Name),
+						Arguments: openai.F(string(tc.Arguments)),
+					}),
+				})
+			}
+
+			message.ToolCalls = openai.F(toolCalls)
+		}
+
+		return []openai.ChatCompletionMessageParamUnion{message}
+
+	case core.ToolMessageRole:
+		messages := []openai.ChatCompletionMessageParamUnion{}
+		for _, tr := range m.ToolResult {
+			messages = append(messages, openai.ToolMessage(tr.ToolCallID, modelinfo.ToolResultText(tr)))
+		}
+
+		return messages
+	}
+
+	parts := []openai.ChatCompletionContentPartUnionParam{}
+	if m.Content != "" {
+		parts = append(parts, openai.TextPart(m.Content))
+	}
+
+	for _, img := range m.Images {
+		parts = append(parts, openai.ChatCompletionContentPartImageParam{
+			Type: openai.F(openai.ChatCompletionContentPartImageTypeImageURL),
+			ImageURL: openai.F(openai.ChatCompletionContentPartImageImageURLParam{
+				URL:    openai.F(DataURI(img)),
+				Detail: openai.F(openai.ChatCompletionContentPartImageImageURLDetail(p.detail)),
+			}),
+		})
+	}
+
+	return []openai.ChatCompletionMessageParamUnion{openai.UserMessageParts(parts...)}
+}
+
+func openAITool(t *core.Tool) (openai.ChatCompletionToolParam, error) {
+	parameters := map[string]any{"type": "object", "properties": map[string]any{}}
+	if len(t.JSONSchema) > 0 {
+		if err := json.Unmarshal(t.JSONSchema, &parameters); err != nil {
+			return openai.ChatCompletionToolParam{}, fmt.Errorf("error unmarshaling schema of tool %s: %w", t.Name, err)
+		}
+	}
+
+	return openai.ChatCompletionToolParam{
+		Type: openai.F(openai.ChatCompletionToolTypeFunction),
+		Function: openai.F(openai.FunctionDefinitionParam{
+			Name:        openai.String(t.Name),
+			Description: openai.String(t.Description),
+			Parameters:  openai.F(openai.FunctionParameters(parameters)),
+		}),
+	}, nil
+}
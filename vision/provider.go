@@ -0,0 +1,185 @@
+package vision
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/agent-api/core"
+	"github.com/agent-api/core/agent"
+	"github.com/agent-api/examples/usage"
+)
+
+// WithImages adds images to a run's input, like agent.WithImagePath does
+// for a single file.
+func WithImages(images ...*core.Image) agent.RunOptionFunc {
+	return func(opts *agent.RunOptions) {
+		opts.Images = append(opts.Images, images...)
+	}
+}
+
+// FitMessages returns the messages with their images fitted to the limits.
+// Messages with images are copied, the others and the originals are not
+// modified.
+func FitMessages(messages []*core.Message, limits *Limits) ([]*core.Message, error) {
+	fitted := make([]*core.Message, len(messages))
+
+	for i, m := range messages {
+		if len(m.Images) == 0 {
+			fitted[i] = m
+			continue
+		}
+
+		images, err := limits.FitAll(m.Images)
+		if err != nil {
+			return nil, err
+		}
+
+		copied := *m
+		copied.Images = images
+		fitted[i] = &copied
+	}
+
+	return fitted, nil
+}
+
+// ProviderOpts configures a new fitting Provider.
+type ProviderOpts struct {
+	// The wrapped core.Provider
+	Provider core.Provider
+
+	// Limits the images of requests are fitted to, i.e.,
+	// LimitsFor(modelinfo.Ollama)
+	Limits *Limits
+}
+
+// Provider is a core.Provider that fits the images of requests to its
+// limits before calling the wrapped provider. The OpenAI, Anthropic and
+// Gemini providers of this package fit images themselves; it is meant for
+// providers that already send images, like Ollama's.
+type Provider struct {
+	provider core.Provider
+	limits   *Limits
+}
+
+// NewProvider creates a new fitting Provider.
+func NewProvider(opts *ProviderOpts) (*Provider, error) {
+	if opts.Provider == nil || opts.Limits == nil {
+		return nil, errors.New("fitting provider requires a provider and limits")
+	}
+
+	return &Provider{
+		provider: opts.Provider,
+		limits:   opts.Limits,
+	}, nil
+}
+
+func (p *Provider) GetCapabilities(ctx context.Context) (*core.Capabilities, error) {
+	return p.provider.GetCapabilities(ctx)
+}
+
+func (p *Provider) UseModel(ctx context.Context, model *core.Model) error {
+	return p.provider.UseModel(ctx, model)
+}
+
+// Generate fits the request's images before calling the wrapped provider.
+func (p *Provider) Generate(ctx context.Context, opts *core.GenerateOptions) (*core.Message, error) {
+	opts, err := fitRequest(opts, p.limits)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.provider.Generate(ctx, opts)
+}
+
+// GenerateStream fits the request's images before calling the wrapped
+// provider. A failure to fit is sent on the error channel.
+func (p *Provider) GenerateStream(ctx context.Context, opts *core.GenerateOptions) (<-chan *core.Message, <-chan string, <-chan error) {
+	opts, err := fitRequest(opts, p.limits)
+	if err != nil {
+		return nil, nil, errorChan(err)
+	}
+
+	return p.provider.GenerateStream(ctx, opts)
+}
+
+// fitRequest returns a copy of the request with its images fitted.
+func fitRequest(opts *core.GenerateOptions, limits *Limits) (*core.GenerateOptions, error) {
+	messages, err := FitMessages(opts.Messages, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	fitted := *opts
+	fitted.Messages = messages
+
+	return &fitted, nil
+}
+
+// streamOf streams the result of a non streaming generate call as a single
+// message and delta, for the providers of this package.
+func streamOf(ctx context.Context, opts *core.GenerateOptions, generate func(context.Context, *core.GenerateOptions) (*core.Message, error)) (<-chan *core.Message, <-chan string, <-chan error) {
+	msgChan := make(chan *core.Message, 1)
+	deltaChan := make(chan string, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(msgChan)
+		defer close(deltaChan)
+		defer close(errChan)
+
+		msg, err := generate(ctx, opts)
+		if err != nil {
+			errChan <- err
+			return
+		}
+
+		if msg.Content != "" {
+			deltaChan <- msg.Content
+		}
+		msgChan <- msg
+	}()
+
+	return msgChan, deltaChan, errChan
+}
+
+func errorChan(err error) <-chan error {
+	errChan := make(chan error, 1)
+	errChan <- err
+	close(errChan)
+
+	return errChan
+}
+
+// withUsage records a response's token usage in its metadata, where the
+// usage package reads it.
+func withUsage(msg *core.Message, promptTokens, completionTokens int64) *core.Message {
+	msg.Metadata = &core.Metadata{
+		ProviderProperties: map[string]string{
+			usage.PromptTokensProperty:     strconv.FormatInt(promptTokens, 10),
+			usage.CompletionTokensProperty: strconv.FormatInt(completionTokens, 10),
+		},
+	}
+
+	return msg
+}
+
+// capabilities returns the capabilities of the providers of this package
+// using model.
+func capabilities(model *core.Model) *core.Capabilities {
+	c := &core.Capabilities{
+		SupportsCompletion: true,
+		SupportsChat:       true,
+		SupportsStreaming:  true,
+		SupportsTools:      true,
+		SupportsImages:     true,
+		AvailableModels:    []*core.Model{},
+	}
+
+	if model != nil {
+		c.DefaultModel = model.ID
+		c.AvailableModels = append(c.AvailableModels, model)
+	}
+
+	return c
+}